@@ -0,0 +1,62 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	suite "github.com/makerdao/oracle-suite"
+	feednodeConfig "github.com/makerdao/oracle-suite/pkg/feednode/config"
+	logrusFlag "github.com/makerdao/oracle-suite/pkg/log/logrus/flag"
+)
+
+type options struct {
+	LogVerbosity   string
+	LogFormat      logrusFlag.FormatTypeValue
+	ConfigFilePath string
+	Config         feednodeConfig.Config
+}
+
+func NewRootCommand(opts *options) *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:           "feednode",
+		Version:       suite.Version,
+		Short:         "Feed node running Gofer, Ghost and Spire in a single process",
+		Long:          ``,
+		SilenceErrors: false,
+		SilenceUsage:  true,
+	}
+
+	rootCmd.PersistentFlags().StringVarP(
+		&opts.LogVerbosity,
+		"log.verbosity", "v",
+		"info",
+		"verbosity level",
+	)
+	rootCmd.PersistentFlags().Var(
+		&opts.LogFormat,
+		"log.format",
+		"log format",
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&opts.ConfigFilePath,
+		"config", "c",
+		"./feednode.json",
+		"feed node config file",
+	)
+
+	return rootCmd
+}
@@ -0,0 +1,70 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRunCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "run",
+		Args:    cobra.ExactArgs(0),
+		Aliases: []string{"agent"},
+		Short:   "Start Ghost and the Spire agent",
+		Long: `Start Ghost and the Spire agent in a single process.
+
+Both services use the same Gofer instance, Ethereum signer and P2P transport,
+so only one set of keys and one P2P node is needed.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			l, err := newLogger(opts)
+			if err != nil {
+				return err
+			}
+
+			ins, err := newFeedNode(opts, l)
+			if err != nil {
+				return err
+			}
+
+			err = ins.Node.Start()
+			if err != nil {
+				_ = ins.Transport.Close()
+				return err
+			}
+			defer ins.Node.Stop()
+
+			// Start the health endpoints:
+			if ins.Health != nil {
+				err = ins.Health.Start()
+				if err != nil {
+					return err
+				}
+				defer ins.Health.Stop()
+			}
+
+			c := make(chan os.Signal, 1)
+			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+			<-c
+
+			return nil
+		},
+	}
+}
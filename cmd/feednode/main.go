@@ -0,0 +1,82 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/makerdao/oracle-suite/pkg/config/loader"
+	feednodeConfig "github.com/makerdao/oracle-suite/pkg/feednode/config"
+	"github.com/makerdao/oracle-suite/pkg/log"
+	logLogrus "github.com/makerdao/oracle-suite/pkg/log/logrus"
+)
+
+func main() {
+	var opts options
+	rootCmd := NewRootCommand(&opts)
+
+	rootCmd.AddCommand(
+		NewRunCmd(&opts),
+		NewConfigCmd(&opts),
+	)
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newLogger(opts *options) (log.Logger, error) {
+	ll, err := logrus.ParseLevel(opts.LogVerbosity)
+	if err != nil {
+		return nil, err
+	}
+
+	lr := logrus.New()
+	lr.SetLevel(ll)
+	lr.SetFormatter(opts.LogFormat.Formatter())
+
+	return logLogrus.New(lr), nil
+}
+
+func loadConfig(opts *options) error {
+	absPath, err := filepath.Abs(opts.ConfigFilePath)
+	if err != nil {
+		return err
+	}
+
+	return loader.LoadFile(&opts.Config, absPath)
+}
+
+func newFeedNode(opts *options, log log.Logger) (*feednodeConfig.Instances, error) {
+	err := loadConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := opts.Config.Configure(feednodeConfig.Dependencies{
+		Context: context.Background(),
+		Logger:  log,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
@@ -0,0 +1,97 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/makerdao/oracle-suite/pkg/config/loader"
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+)
+
+func NewConfigCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Args:  cobra.NoArgs,
+		Short: "Commands related to the config file",
+		Long:  `Commands related to the config file.`,
+	}
+
+	cmd.AddCommand(
+		NewConfigValidateCmd(opts),
+	)
+
+	return cmd
+}
+
+func NewConfigValidateCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "validate",
+		Aliases: []string{"lint"},
+		Args:    cobra.NoArgs,
+		Short:   "Validate the config file",
+		Long: `Validate the config file, including Gofer's price models, and report all problems found.
+
+Each problem is reported with a JSON pointer to the invalid value. If any problem
+is found, the command returns a non-zero status code.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			absPath, err := filepath.Abs(opts.GhostConfigFilePath)
+			if err != nil {
+				return err
+			}
+
+			l, err := newLogger(opts)
+			if err != nil {
+				return err
+			}
+
+			err = loader.LoadFile(&opts.GoferConfig, absPath)
+			if err != nil {
+				return err
+			}
+			err = loader.LoadFile(&opts.GhostConfig, absPath)
+			if err != nil {
+				return err
+			}
+
+			// Pairs can be checked against Gofer's price models only if
+			// the Gofer's configuration is valid.
+			var gof gofer.Gofer
+			goferErr := opts.GoferConfig.Validate()
+			if goferErr == nil {
+				gof, err = opts.GoferConfig.ConfigureGofer(l)
+				if err != nil {
+					return err
+				}
+			}
+
+			var errs validation.Errors
+			if !errors.As(validation.Join(goferErr, opts.GhostConfig.Validate(gof)), &errs) {
+				return nil
+			}
+			for _, e := range errs {
+				fmt.Println(e)
+			}
+
+			return errors.New("invalid configuration")
+		},
+	}
+}
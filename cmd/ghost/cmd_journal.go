@@ -0,0 +1,148 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/makerdao/oracle-suite/pkg/config/loader"
+	"github.com/makerdao/oracle-suite/pkg/ghost"
+)
+
+func NewJournalCmd(opts *options) *cobra.Command {
+	var at string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "journal [PAIR...]",
+		Args:  cobra.MinimumNArgs(0),
+		Short: "Print prices recorded in the broadcast journal",
+		Long: `Print prices recorded in the broadcast journal configured in the journal.path field.
+
+Without the --at flag, all recorded prices for the given pairs, or for all pairs if none
+are given, are printed as JSON lines. With the --at flag, only the last price broadcast
+for each pair before or at the given time is printed, which is the price our feed was
+reporting at that time. The time may be given as a Unix timestamp or in RFC 3339 format.
+
+Prices recorded in the dry-run mode were never sent to the network, so they are skipped
+unless the --dry-run flag is used.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			absPath, err := filepath.Abs(opts.GhostConfigFilePath)
+			if err != nil {
+				return err
+			}
+			err = loader.LoadFile(&opts.GhostConfig, absPath)
+			if err != nil {
+				return err
+			}
+			if opts.GhostConfig.Journal.Path == "" {
+				return errors.New("journal is not configured")
+			}
+
+			f, err := os.Open(opts.GhostConfig.Journal.Path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			entries, err := ghost.ReadJournal(f)
+			if err != nil {
+				return err
+			}
+
+			enc := json.NewEncoder(os.Stdout)
+			if at == "" {
+				for _, e := range entries {
+					if len(args) > 0 && !contains(args, e.Pair) {
+						continue
+					}
+					if e.DryRun && !dryRun {
+						continue
+					}
+					if err := enc.Encode(e); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			t, err := parseTime(at)
+			if err != nil {
+				return err
+			}
+			pairs := args
+			if len(pairs) == 0 {
+				for _, e := range entries {
+					if e.DryRun && !dryRun {
+						continue
+					}
+					if !contains(pairs, e.Pair) {
+						pairs = append(pairs, e.Pair)
+					}
+				}
+			}
+			for _, pair := range pairs {
+				e, ok := ghost.FindJournalEntry(entries, pair, t, dryRun)
+				if !ok {
+					fmt.Fprintf(os.Stderr, "no price was broadcast for %s before %s\n", pair, t)
+					continue
+				}
+				if err := enc.Encode(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&at,
+		"at",
+		"",
+		"print prices which were the most recent ones at the given time",
+	)
+	cmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"include prices recorded in the dry-run mode",
+	)
+
+	return cmd
+}
+
+func parseTime(s string) (time.Time, error) {
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(ts, 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func contains(s []string, v string) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -16,6 +16,7 @@
 package main
 
 import (
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -25,12 +26,19 @@ import (
 )
 
 func NewRunCmd(opts *options) *cobra.Command {
-	return &cobra.Command{
+	var dryRun bool
+	var dryRunOutput string
+
+	cmd := &cobra.Command{
 		Use:     "run",
 		Args:    cobra.ExactArgs(0),
 		Aliases: []string{"agent"},
 		Short:   "",
-		Long:    ``,
+		Long: `Start Ghost.
+
+In the dry-run mode, prices are fetched and signed as usual, but signed messages are
+written as JSON lines to the standard output, or to the file given by the
+--dry-run.output flag, instead of being sent to the network.`,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			ghostAbsPath, err := filepath.Abs(opts.GhostConfigFilePath)
 			if err != nil {
@@ -47,7 +55,20 @@ func NewRunCmd(opts *options) *cobra.Command {
 				return err
 			}
 
-			ins, err := newGhost(opts, ghostAbsPath, gof, l)
+			var out io.Writer
+			if dryRun {
+				out = os.Stdout
+				if dryRunOutput != "" {
+					f, err := os.OpenFile(dryRunOutput, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					out = f
+				}
+			}
+
+			ins, err := newGhost(opts, ghostAbsPath, gof, out, l)
 			if err != nil {
 				return err
 			}
@@ -63,6 +84,15 @@ func NewRunCmd(opts *options) *cobra.Command {
 				}
 			}()
 
+			// Start the health endpoints:
+			if ins.Health != nil {
+				err = ins.Health.Start()
+				if err != nil {
+					return err
+				}
+				defer ins.Health.Stop()
+			}
+
 			c := make(chan os.Signal, 1)
 			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 			<-c
@@ -70,4 +100,19 @@ func NewRunCmd(opts *options) *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"write signed prices to the output instead of broadcasting them",
+	)
+	cmd.Flags().StringVar(
+		&dryRunOutput,
+		"dry-run.output",
+		"",
+		"file to which prices are appended in the dry-run mode, stdout if empty",
+	)
+
+	return cmd
 }
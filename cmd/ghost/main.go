@@ -17,15 +17,15 @@ package main
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/makerdao/oracle-suite/pkg/config/loader"
 	ghostConfig "github.com/makerdao/oracle-suite/pkg/ghost/config"
-	ghostJSON "github.com/makerdao/oracle-suite/pkg/ghost/config/json"
 	"github.com/makerdao/oracle-suite/pkg/gofer"
-	goferJSON "github.com/makerdao/oracle-suite/pkg/gofer/config/json"
 	"github.com/makerdao/oracle-suite/pkg/log"
 	logLogrus "github.com/makerdao/oracle-suite/pkg/log/logrus"
 )
@@ -36,6 +36,8 @@ func main() {
 
 	rootCmd.AddCommand(
 		NewRunCmd(&opts),
+		NewConfigCmd(&opts),
+		NewJournalCmd(&opts),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -62,7 +64,7 @@ func newGofer(opts *options, path string, log log.Logger) (gofer.Gofer, error) {
 		return nil, err
 	}
 
-	err = goferJSON.ParseJSONFile(&opts.GoferConfig, absPath)
+	err = loader.LoadFile(&opts.GoferConfig, absPath)
 	if err != nil {
 		return nil, err
 	}
@@ -75,13 +77,13 @@ func newGofer(opts *options, path string, log log.Logger) (gofer.Gofer, error) {
 	return gof, nil
 }
 
-func newGhost(opts *options, path string, gof gofer.Gofer, log log.Logger) (*ghostConfig.Instances, error) {
+func newGhost(opts *options, path string, gof gofer.Gofer, dryRun io.Writer, log log.Logger) (*ghostConfig.Instances, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
 	}
 
-	err = ghostJSON.ParseJSONFile(&opts.GhostConfig, absPath)
+	err = loader.LoadFile(&opts.GhostConfig, absPath)
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +92,7 @@ func newGhost(opts *options, path string, gof gofer.Gofer, log log.Logger) (*gho
 		Context: context.Background(),
 		Gofer:   gof,
 		Logger:  log,
+		DryRun:  dryRun,
 	})
 	if err != nil {
 		return nil, err
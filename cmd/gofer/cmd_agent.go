@@ -39,6 +39,8 @@ func NewAgentCmd(opts *options) *cobra.Command {
 				return err
 			}
 
+			hs := opts.Config.ConfigureHealthServer(srv, log)
+
 			// Start the RPC server:
 			err = srv.Start()
 			if err != nil {
@@ -46,6 +48,15 @@ func NewAgentCmd(opts *options) *cobra.Command {
 			}
 			defer srv.Stop()
 
+			// Start the health endpoints:
+			if hs != nil {
+				err = hs.Start()
+				if err != nil {
+					return err
+				}
+				defer hs.Stop()
+			}
+
 			// Wait for the interrupt signal:
 			c := make(chan os.Signal, 1)
 			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
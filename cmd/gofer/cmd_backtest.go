@@ -0,0 +1,102 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/makerdao/oracle-suite/internal/gofer/marshal"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+)
+
+func NewBacktestCmd(opts *options) *cobra.Command {
+	var dataFilePath string
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "backtest [PAIR...]",
+		Args:  cobra.MinimumNArgs(0),
+		Short: "Replay historical prices through price models",
+		Long: `Replay historical origin prices through price models defined in the config file.
+
+Historical prices must be provided in the same format as returned by the "gofer price"
+command with the json or ndjson format. Prices are evaluated on every tick between the
+oldest and the newest price. The command returns the calculated price series followed by
+the summary for each pair with the number of ticks on which quorum would have failed.`,
+		RunE: func(_ *cobra.Command, args []string) (err error) {
+			mar, err := marshal.NewMarshal(opts.Format.format)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err != nil {
+					exitCode = 1
+					_ = mar.Write(os.Stderr, err)
+				}
+				_ = mar.Flush()
+				// Set err to nil because error was already handled by marshaller.
+				err = nil
+			}()
+
+			bt, err := newBacktest(opts, opts.ConfigFilePath, dataFilePath, interval)
+			if err != nil {
+				return err
+			}
+
+			pairs, err := gofer.NewPairs(args...)
+			if err != nil {
+				return err
+			}
+
+			res, err := bt.Run(pairs...)
+			if err != nil {
+				return err
+			}
+
+			for i := range res.Ticks {
+				if err := mar.Write(os.Stdout, &res.Ticks[i]); err != nil {
+					_ = mar.Write(os.Stderr, err)
+				}
+			}
+			for _, s := range res.Stats {
+				if err := mar.Write(os.Stdout, s); err != nil {
+					_ = mar.Write(os.Stderr, err)
+				}
+			}
+
+			return
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&dataFilePath,
+		"data",
+		"",
+		"historical prices file",
+	)
+	cmd.Flags().DurationVar(
+		&interval,
+		"interval",
+		time.Minute,
+		"interval between ticks",
+	)
+	_ = cmd.MarkFlagRequired("data")
+
+	return cmd
+}
@@ -19,13 +19,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	suite "github.com/makerdao/oracle-suite"
 	"github.com/makerdao/oracle-suite/internal/gofer/marshal"
+	"github.com/makerdao/oracle-suite/pkg/config/loader"
 	"github.com/makerdao/oracle-suite/pkg/gofer"
-	configJSON "github.com/makerdao/oracle-suite/pkg/gofer/config/json"
+	"github.com/makerdao/oracle-suite/pkg/gofer/backtest"
 	"github.com/makerdao/oracle-suite/pkg/gofer/rpc"
 	"github.com/makerdao/oracle-suite/pkg/log"
 	logLogrus "github.com/makerdao/oracle-suite/pkg/log/logrus"
@@ -45,6 +47,8 @@ func main() {
 		NewPairsCmd(&opts),
 		NewPricesCmd(&opts),
 		NewAgentCmd(&opts),
+		NewBacktestCmd(&opts),
+		NewConfigCmd(&opts),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -75,7 +79,7 @@ func newGofer(opts *options, path string, logger log.Logger) (gofer.Gofer, error
 		return nil, err
 	}
 
-	err = configJSON.ParseJSONFile(&opts.Config, absPath)
+	err = loader.LoadFile(&opts.Config, absPath)
 	if err != nil {
 		return nil, err
 	}
@@ -102,10 +106,35 @@ func newAgent(opts *options, path string, logger log.Logger) (*rpc.Agent, error)
 		return nil, err
 	}
 
-	err = configJSON.ParseJSONFile(&opts.Config, absPath)
+	err = loader.LoadFile(&opts.Config, absPath)
 	if err != nil {
 		return nil, err
 	}
 
 	return opts.Config.ConfigureRPCAgent(logger)
 }
+
+func newBacktest(opts *options, path, dataPath string, interval time.Duration) (*backtest.Backtest, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loader.LoadFile(&opts.Config, absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := backtest.ReadData(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.Config.ConfigureBacktest(data, interval)
+}
@@ -59,20 +59,26 @@ func der(mnemonic string, path accounts.DerivationPath, pass string, showPass, s
 	if err != nil {
 		return nil, err
 	}
+	k, err := genStark(wallet, path, showPriv)
+	if err != nil {
+		return nil, err
+	}
 
 	return &derOut{
-		Eth:  e,
-		Caps: c,
-		P2p:  p,
-		Ssb:  s,
+		Eth:   e,
+		Caps:  c,
+		P2p:   p,
+		Ssb:   s,
+		Stark: k,
 	}, nil
 }
 
 type derOut struct {
-	Eth  *eth  `json:"eth"`
-	Caps *caps `json:"caps"`
-	P2p  *p2p  `json:"p2p"`
-	Ssb  *ssb  `json:"ssb"`
+	Eth   *eth      `json:"eth"`
+	Caps  *caps     `json:"caps"`
+	P2p   *p2p      `json:"p2p"`
+	Ssb   *ssb      `json:"ssb"`
+	Stark *starkKey `json:"stark"`
 }
 
 func setPurpose(base accounts.DerivationPath, purpose uint32) accounts.DerivationPath {
@@ -127,7 +133,7 @@ func derInput(args []string, file *os.File) (string, accounts.DerivationPath, st
 
 	var showPass, showPriv bool
 	fs.BoolVar(&showPass, "showPass", false, "Include ethereum keystore password")
-	fs.BoolVar(&showPriv, "showPriv", false, "Include ethereum private key")
+	fs.BoolVar(&showPriv, "showPriv", false, "Include ethereum and stark private keys")
 
 	if err := fs.Parse(args[1:]); err != nil {
 		return "", nil, "", showPass, showPriv, err
@@ -0,0 +1,74 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
+
+	"github.com/makerdao/oracle-suite/pkg/stark"
+)
+
+type starkKey struct {
+	PrivateKey *big.Int
+	PublicKey  *big.Int
+	showPriv   bool
+}
+
+func (s starkKey) MarshalJSON() ([]byte, error) {
+	var priv string
+	if s.showPriv {
+		priv = "0x" + s.PrivateKey.Text(16)
+	}
+	return json.Marshal(struct {
+		PrivateKey string `json:"priv,omitempty"`
+		PublicKey  string `json:"pub"`
+	}{
+		PrivateKey: priv,
+		PublicKey:  "0x" + s.PublicKey.Text(16),
+	})
+}
+
+// genStark derives the Stark key from the third key on the caps path. The
+// derived secp256k1 key is used as a seed for the stark.GrindKey function.
+func genStark(wallet *hdwallet.Wallet, path accounts.DerivationPath, showPriv bool) (*starkKey, error) {
+	iter, err := capsIterator(path)
+	if err != nil {
+		return nil, err
+	}
+	iter() // caps.shs
+	iter() // caps.sign
+
+	dp := iter()
+	log.Printf("stark path: %s", dp)
+	k, err := deriveKey(wallet, dp)
+	if err != nil {
+		return nil, err
+	}
+
+	priv := stark.GrindKey(crypto.FromECDSA(k))
+	sig, err := stark.NewSigner(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &starkKey{PrivateKey: priv, PublicKey: sig.PublicKey(), showPriv: showPriv}, nil
+}
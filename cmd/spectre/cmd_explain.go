@@ -0,0 +1,144 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+)
+
+func NewExplainCmd(opts *options) *cobra.Command {
+	var wait time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "explain [PAIR]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Explain whether Oracles would be updated",
+		Long: `Collect prices from feeds and explain whether the Oracle for the given pair, or for all
+pairs if none is given, would be updated and why.
+
+The command performs the same checks as the "run" command: it reads the bar, age and val
+from the Oracle contract, rejects expired prices, computes the spread and checks the quorum.
+The report is printed as JSON and includes the calldata of the poke transaction. No
+transactions are sent.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			absPath, err := filepath.Abs(opts.ConfigFilePath)
+			if err != nil {
+				return err
+			}
+
+			l, err := newLogger(opts)
+			if err != nil {
+				return err
+			}
+
+			opts.DryRun = true
+			ins, err := newSpectre(opts, absPath, l)
+			if err != nil {
+				return err
+			}
+
+			err = ins.Ethereum.Start()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				err := ins.Ethereum.Stop()
+				if err != nil {
+					l.Errorf("Unable to stop the Ethereum client: %s", err)
+				}
+			}()
+
+			if g, ok := ins.Gofer.(gofer.StartableGofer); ok {
+				err = g.Start()
+				if err != nil {
+					return err
+				}
+				defer func() {
+					err := g.Stop()
+					if err != nil {
+						l.Errorf("Unable to stop the reference Gofer: %s", err)
+					}
+				}()
+			}
+
+			err = ins.Datastore.Start()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				err := ins.Datastore.Stop()
+				if err != nil {
+					l.Errorf("Unable to stop the datastore: %s", err)
+				}
+			}()
+
+			// Prices are only received from the network, so we have to wait
+			// until feeds broadcast them:
+			c := make(chan os.Signal, 1)
+			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+			select {
+			case <-time.After(wait):
+			case <-c:
+				return errors.New("interrupted")
+			}
+
+			pairs := args
+			if len(pairs) == 0 {
+				pairs = ins.Spectre.AssetPairs()
+			}
+
+			failed := false
+			for _, pair := range pairs {
+				rep, err := ins.Spectre.Explain(pair)
+				if err != nil {
+					failed = true
+					cmd.PrintErrf("%s: %s\n", pair, err)
+					continue
+				}
+				b, err := json.MarshalIndent(rep, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+			}
+			if failed {
+				return errors.New("unable to explain some pairs")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(
+		&wait,
+		"wait",
+		time.Minute,
+		"time to wait for prices from feeds",
+	)
+
+	return cmd
+}
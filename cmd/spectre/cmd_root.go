@@ -19,6 +19,7 @@ import (
 	"github.com/spf13/cobra"
 
 	suite "github.com/makerdao/oracle-suite"
+	goferConfig "github.com/makerdao/oracle-suite/pkg/gofer/config"
 	logrusFlag "github.com/makerdao/oracle-suite/pkg/log/logrus/flag"
 	"github.com/makerdao/oracle-suite/pkg/spectre/config"
 )
@@ -27,7 +28,9 @@ type options struct {
 	LogVerbosity   string
 	LogFormat      logrusFlag.FormatTypeValue
 	ConfigFilePath string
+	DryRun         bool
 	Config         config.Config
+	GoferConfig    goferConfig.Config
 }
 
 func NewRootCommand(opts *options) *cobra.Command {
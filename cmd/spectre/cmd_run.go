@@ -22,10 +22,12 @@ import (
 	"syscall"
 
 	"github.com/spf13/cobra"
+
+	"github.com/makerdao/oracle-suite/pkg/gofer"
 )
 
 func NewRunCmd(opts *options) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "run",
 		Args:    cobra.ExactArgs(0),
 		Aliases: []string{"agent"},
@@ -47,6 +49,56 @@ func NewRunCmd(opts *options) *cobra.Command {
 				return err
 			}
 
+			err = ins.Ethereum.Start()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				err := ins.Ethereum.Stop()
+				if err != nil {
+					l.Errorf("Unable to stop the Ethereum client: %s", err)
+				}
+			}()
+
+			err = ins.TxManager.Start()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				err := ins.TxManager.Stop()
+				if err != nil {
+					l.Errorf("Unable to stop the transaction manager: %s", err)
+				}
+			}()
+
+			// Start the coordinator if the coordination is enabled:
+			if ins.Coordinator != nil {
+				err = ins.Coordinator.Start()
+				if err != nil {
+					return err
+				}
+				defer func() {
+					err := ins.Coordinator.Stop()
+					if err != nil {
+						l.Errorf("Unable to stop the coordinator: %s", err)
+					}
+				}()
+			}
+
+			// Start the reference Gofer if it has to be started:
+			if g, ok := ins.Gofer.(gofer.StartableGofer); ok {
+				err = g.Start()
+				if err != nil {
+					return err
+				}
+				defer func() {
+					err := g.Stop()
+					if err != nil {
+						l.Errorf("Unable to stop the reference Gofer: %s", err)
+					}
+				}()
+			}
+
 			err = ins.Spectre.Start()
 			if err != nil {
 				return err
@@ -58,6 +110,15 @@ func NewRunCmd(opts *options) *cobra.Command {
 				}
 			}()
 
+			// Start the health endpoints:
+			if ins.Health != nil {
+				err = ins.Health.Start()
+				if err != nil {
+					return err
+				}
+				defer ins.Health.Stop()
+			}
+
 			c := make(chan os.Signal, 1)
 			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 			<-c
@@ -65,4 +126,13 @@ func NewRunCmd(opts *options) *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(
+		&opts.DryRun,
+		"dry-run",
+		false,
+		"log relay decisions instead of sending transactions",
+	)
+
+	return cmd
 }
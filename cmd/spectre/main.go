@@ -22,10 +22,11 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/makerdao/oracle-suite/pkg/config/loader"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
 	"github.com/makerdao/oracle-suite/pkg/log"
 	logLogrus "github.com/makerdao/oracle-suite/pkg/log/logrus"
 	"github.com/makerdao/oracle-suite/pkg/spectre/config"
-	configJSON "github.com/makerdao/oracle-suite/pkg/spectre/config/json"
 )
 
 func main() {
@@ -34,6 +35,8 @@ func main() {
 
 	rootCmd.AddCommand(
 		NewRunCmd(&opts),
+		NewExplainCmd(&opts),
+		NewConfigCmd(&opts),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -60,13 +63,30 @@ func newSpectre(opts *options, path string, log log.Logger) (*config.Instances,
 		return nil, err
 	}
 
-	err = configJSON.ParseJSONFile(&opts.Config, absPath)
+	err = loader.LoadFile(&opts.Config, absPath)
 	if err != nil {
 		return nil, err
 	}
+	if opts.DryRun {
+		opts.Config.Options.DryRun = true
+	}
+
+	// The reference Gofer uses the gofer section of the same config file:
+	var gof gofer.Gofer
+	if opts.Config.EmbeddedGofer() {
+		err = loader.LoadFile(&opts.GoferConfig, absPath)
+		if err != nil {
+			return nil, err
+		}
+		gof, err = opts.GoferConfig.ConfigureGofer(log)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	i, err := opts.Config.Configure(config.Dependencies{
 		Context: context.Background(),
+		Gofer:   gof,
 		Logger:  log,
 	})
 	if err != nil {
@@ -0,0 +1,104 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewFeedsCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feeds",
+		Args:  cobra.ExactArgs(1),
+		Short: "",
+		Long:  ``,
+		PersistentPreRunE: func(_ *cobra.Command, args []string) error {
+			var err error
+
+			logger, err = newLogger(opts)
+			if err != nil {
+				return err
+			}
+			client, err = newSpire(opts, logger)
+			if err != nil {
+				return err
+			}
+
+			return client.Start()
+		},
+		PersistentPostRunE: func(_ *cobra.Command, args []string) error {
+			err := client.Stop()
+			if err != nil {
+				logger.WithError(err).Error("Unable to stop RPC Client")
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(NewFeedsStatusCmd())
+
+	return cmd
+}
+
+type feedsStatusOptions struct {
+	FilterPair string
+	FilterFrom string
+}
+
+func NewFeedsStatusCmd() *cobra.Command {
+	var feedsStatusOpts feedsStatusOptions
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Args:  cobra.ExactArgs(0),
+		Short: "Show the performance and liveness of feeders",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) error {
+			s, err := client.FeedsStatus(feedsStatusOpts.FilterPair, feedsStatusOpts.FilterFrom)
+			if err != nil {
+				return err
+			}
+
+			bts, err := json.Marshal(s)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%s\n", string(bts))
+
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(
+		&feedsStatusOpts.FilterFrom,
+		"filter.from",
+		"",
+		"",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&feedsStatusOpts.FilterPair,
+		"filter.pair",
+		"",
+		"",
+	)
+
+	return cmd
+}
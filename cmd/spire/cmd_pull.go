@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -55,6 +56,7 @@ func NewPullCmd(opts *options) *cobra.Command {
 	cmd.AddCommand(
 		NewPullPricesCmd(),
 		NewPullPriceCmd(),
+		NewPullHistoryCmd(),
 	)
 
 	return cmd
@@ -133,3 +135,68 @@ func NewPullPricesCmd() *cobra.Command {
 
 	return cmd
 }
+
+type pullHistoryOptions struct {
+	FilterPair string
+	FilterFrom string
+	Since      time.Duration
+}
+
+func NewPullHistoryCmd() *cobra.Command {
+	var pullHistoryOpts pullHistoryOptions
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Args:  cobra.ExactArgs(0),
+		Short: "Pull the history of prices for each feeder",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, args []string) error {
+			var from time.Time
+			if pullHistoryOpts.Since > 0 {
+				from = time.Now().Add(-pullHistoryOpts.Since)
+			}
+
+			h, err := client.PullPriceHistory(
+				pullHistoryOpts.FilterPair,
+				pullHistoryOpts.FilterFrom,
+				from,
+				time.Time{},
+			)
+			if err != nil {
+				return err
+			}
+
+			bts, err := json.Marshal(h)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%s\n", string(bts))
+
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(
+		&pullHistoryOpts.FilterFrom,
+		"filter.from",
+		"",
+		"",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&pullHistoryOpts.FilterPair,
+		"filter.pair",
+		"",
+		"",
+	)
+
+	cmd.PersistentFlags().DurationVar(
+		&pullHistoryOpts.Since,
+		"since",
+		0,
+		"pull only prices not older than the given duration",
+	)
+
+	return cmd
+}
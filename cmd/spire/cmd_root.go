@@ -66,6 +66,8 @@ func NewRootCommand(opts *options) *cobra.Command {
 		NewAgentCmd(opts),
 		NewPullCmd(opts),
 		NewPushCmd(opts),
+		NewFeedsCmd(opts),
+		NewConfigCmd(opts),
 	)
 
 	return rootCmd
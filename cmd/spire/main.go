@@ -25,11 +25,11 @@ import (
 	"github.com/sirupsen/logrus"
 
 	suite "github.com/makerdao/oracle-suite"
+	"github.com/makerdao/oracle-suite/pkg/config/loader"
 	"github.com/makerdao/oracle-suite/pkg/log"
 	logLogrus "github.com/makerdao/oracle-suite/pkg/log/logrus"
 	"github.com/makerdao/oracle-suite/pkg/spire"
 	"github.com/makerdao/oracle-suite/pkg/spire/config"
-	configJSON "github.com/makerdao/oracle-suite/pkg/spire/config/json"
 )
 
 var (
@@ -66,7 +66,7 @@ func newServer(opts *options, log log.Logger) (*spire.Agent, error) {
 			return nil, err
 		}
 
-		err = configJSON.ParseJSONFile(&opts.Config, absPath)
+		err = loader.LoadFile(&opts.Config, absPath)
 		if err != nil {
 			return nil, err
 		}
@@ -90,7 +90,7 @@ func newSpire(opts *options, log log.Logger) (*spire.Spire, error) {
 			return nil, err
 		}
 
-		err = configJSON.ParseJSONFile(&opts.Config, absPath)
+		err = loader.LoadFile(&opts.Config, absPath)
 		if err != nil {
 			return nil, err
 		}
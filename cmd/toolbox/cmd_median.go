@@ -22,11 +22,11 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
 
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
 	ethereumGeth "github.com/makerdao/oracle-suite/pkg/ethereum/geth"
+	"github.com/makerdao/oracle-suite/pkg/log/null"
 	"github.com/makerdao/oracle-suite/pkg/oracle"
 	oracleGeth "github.com/makerdao/oracle-suite/pkg/oracle/geth"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
@@ -50,22 +50,32 @@ func NewMedianCmd(opts *options) *cobra.Command {
 			var err error
 
 			// Create signer:
-			account, err := ethereumGeth.NewAccount(
-				opts.EthereumKeystore,
-				opts.EthereumPassword,
-				ethereum.HexToAddress(opts.EthereumAddress),
-			)
+			signer, err := newSigner(opts)
 			if err != nil {
 				return err
 			}
-			signer := ethereumGeth.NewSigner(account)
 
 			// Create Ethereum client:
-			client, err := ethclient.Dial(opts.EthereumRPC)
+			var endpoints []ethereumGeth.Endpoint
+			for _, addr := range opts.EthereumRPC {
+				client, err := ethereumGeth.DialEthClient(addr)
+				if err != nil {
+					return err
+				}
+				endpoints = append(endpoints, ethereumGeth.Endpoint{
+					Name:   addr,
+					Client: ethereumGeth.NewClient(client, signer),
+				})
+			}
+			gethClient, err := ethereumGeth.NewMultiClient(ethereumGeth.MultiClientConfig{
+				Endpoints: endpoints,
+				Signer:    signer,
+				Quorum:    opts.EthereumQuorum,
+				Logger:    null.New(),
+			})
 			if err != nil {
 				return err
 			}
-			gethClient := ethereumGeth.NewClient(client, signer)
 
 			// Median instance:
 			median = oracleGeth.NewMedian(gethClient, ethereum.HexToAddress(medianOpts.Address))
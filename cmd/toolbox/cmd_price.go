@@ -16,12 +16,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
 
-	"github.com/makerdao/oracle-suite/pkg/ethereum"
 	"github.com/makerdao/oracle-suite/pkg/ethereum/geth"
+	"github.com/makerdao/oracle-suite/pkg/oracle"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
 )
 
@@ -50,17 +51,11 @@ func NewPriceSignCmd(opts *options) *cobra.Command {
 		RunE: func(_ *cobra.Command, args []string) error {
 			var err error
 
-			account, err := geth.NewAccount(
-				opts.EthereumKeystore,
-				opts.EthereumPassword,
-				ethereum.HexToAddress(opts.EthereumAddress),
-			)
+			signer, err := newSigner(opts)
 			if err != nil {
 				return err
 			}
 
-			signer := geth.NewSigner(account)
-
 			// Read JSON and parse it:
 			input, err := readInput(args, 0)
 			if err != nil {
@@ -95,7 +90,7 @@ func NewPriceVerifyCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "verify [json_message]",
 		Args:  cobra.MaximumNArgs(1),
-		Short: "verifies given JSON price message",
+		Short: "verifies given JSON price message, including its Stark signature if present",
 		Long:  ``,
 		RunE: func(_ *cobra.Command, args []string) error {
 			var err error
@@ -119,6 +114,15 @@ func NewPriceVerifyCmd() *cobra.Command {
 				fmt.Printf("%-4s %s\n", k, fields[k])
 			}
 
+			// Verify the Stark signature:
+			stark := "*not signed*"
+			if err := msg.Price.VerifyStark(); err == nil {
+				stark = "valid signature"
+			} else if !errors.Is(err, oracle.ErrStarkSignatureNotSet) {
+				stark = fmt.Sprintf("*%s*", err)
+			}
+			fmt.Printf("%-4s %s\n", "stark", stark)
+
 			return nil
 		},
 	}
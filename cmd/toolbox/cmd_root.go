@@ -19,13 +19,17 @@ import (
 	"github.com/spf13/cobra"
 
 	suite "github.com/makerdao/oracle-suite"
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/ethereum/geth"
 )
 
 type options struct {
 	EthereumKeystore string
 	EthereumPassword string
 	EthereumAddress  string
-	EthereumRPC      string
+	EthereumSigner   string
+	EthereumRPC      []string
+	EthereumQuorum   int
 }
 
 func NewRootCommand() *cobra.Command {
@@ -62,10 +66,24 @@ func NewRootCommand() *cobra.Command {
 	)
 
 	rootCmd.PersistentFlags().StringVar(
+		&opts.EthereumSigner,
+		"eth-remote-signer",
+		"",
+		"address of an external signer (like Clef) used instead of the keystore",
+	)
+
+	rootCmd.PersistentFlags().StringSliceVar(
 		&opts.EthereumRPC,
 		"eth-rpc",
-		"",
-		"ethereum RPC address",
+		nil,
+		"ethereum RPC address, may be repeated to use multiple endpoints",
+	)
+
+	rootCmd.PersistentFlags().IntVar(
+		&opts.EthereumQuorum,
+		"eth-quorum",
+		0,
+		"number of ethereum RPC endpoints which must agree on read results",
 	)
 
 	rootCmd.AddCommand(
@@ -76,3 +94,19 @@ func NewRootCommand() *cobra.Command {
 
 	return rootCmd
 }
+
+// newSigner returns the signer for the account given in the ethereum flags.
+func newSigner(opts *options) (ethereum.Signer, error) {
+	if opts.EthereumSigner != "" {
+		return geth.DialRemoteSigner(opts.EthereumSigner, ethereum.HexToAddress(opts.EthereumAddress))
+	}
+	account, err := geth.NewAccount(
+		opts.EthereumKeystore,
+		opts.EthereumPassword,
+		ethereum.HexToAddress(opts.EthereumAddress),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return geth.NewSigner(account), nil
+}
@@ -41,16 +41,11 @@ func NewSignerCmd(opts *options) *cobra.Command {
 		Long:  ``,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			if opts.EthereumAddress != "" {
-				account, err := geth.NewAccount(
-					opts.EthereumKeystore,
-					opts.EthereumPassword,
-					ethereum.HexToAddress(opts.EthereumAddress),
-				)
+				var err error
+				signer, err = newSigner(opts)
 				if err != nil {
 					return err
 				}
-
-				signer = geth.NewSigner(account)
 			} else {
 				signer = geth.NewSigner(nil)
 			}
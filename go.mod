@@ -11,8 +11,10 @@ require (
 	github.com/go-ole/go-ole v1.2.5 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/google/uuid v1.2.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1
+	github.com/hashicorp/hcl v1.0.0
 	github.com/ipfs/go-ipns v0.1.0 // indirect
 	github.com/ipld/go-ipld-prime v0.10.0 // indirect
 	github.com/klauspost/cpuid/v2 v2.0.6 // indirect
@@ -32,6 +34,7 @@ require (
 	github.com/miguelmota/go-ethereum-hdwallet v0.0.1
 	github.com/multiformats/go-multiaddr v0.3.2
 	github.com/polydawn/refmt v0.0.0-20201211092308-30ac6d18308e // indirect
+	github.com/prometheus/client_golang v1.11.0
 	github.com/prometheus/common v0.29.0 // indirect
 	github.com/rjeczalik/notify v0.9.2 // indirect
 	github.com/shirou/gopsutil v3.21.5+incompatible // indirect
@@ -48,4 +51,5 @@ require (
 	golang.org/x/net v0.0.0-20210610132358-84b48f89b13b // indirect
 	golang.org/x/sys v0.0.0-20210611083646-a4fc73990273 // indirect
 	golang.org/x/time v0.0.0-20210611083556-38a9dc6acbc6
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
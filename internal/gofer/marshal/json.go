@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/gofer/backtest"
 )
 
 type jsonItem struct {
@@ -48,6 +49,10 @@ func (j *json) Write(writer io.Writer, item interface{}) error {
 		i = j.handlePrice(typedItem)
 	case *gofer.Model:
 		i = j.handleModel(typedItem)
+	case *backtest.Tick:
+		i = j.handleTick(typedItem)
+	case *backtest.Stats:
+		i = j.handleStats(typedItem)
 	case error:
 		i = j.handleError(typedItem)
 	default:
@@ -106,6 +111,34 @@ func (*json) handleModel(node *gofer.Model) interface{} {
 	return node.Pair.String()
 }
 
+func (*json) handleTick(tick *backtest.Tick) interface{} {
+	return struct {
+		Tick time.Time `json:"tick"`
+		jsonPrice
+	}{
+		Tick:      tick.Time.In(time.UTC),
+		jsonPrice: jsonPriceFromGoferPrice(tick.Price),
+	}
+}
+
+func (*json) handleStats(stats *backtest.Stats) interface{} {
+	return struct {
+		Base              string  `json:"base"`
+		Quote             string  `json:"quote"`
+		Ticks             int     `json:"ticks"`
+		Failures          int     `json:"failures"`
+		QuorumFailures    int     `json:"quorumFailures"`
+		QuorumFailureRate float64 `json:"quorumFailureRate"`
+	}{
+		Base:              stats.Pair.Base,
+		Quote:             stats.Pair.Quote,
+		Ticks:             stats.Ticks,
+		Failures:          stats.Failures,
+		QuorumFailures:    stats.QuorumFailures,
+		QuorumFailureRate: stats.QuorumFailureRate(),
+	}
+}
+
 func (*json) handleError(err error) interface{} {
 	return struct {
 		Error string `json:"error"`
@@ -19,8 +19,10 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/gofer/backtest"
 )
 
 type plainItem struct {
@@ -44,6 +46,10 @@ func (p *plain) Write(writer io.Writer, item interface{}) error {
 		i = p.handlePrice(typedItem)
 	case *gofer.Model:
 		i = p.handleModel(typedItem)
+	case *backtest.Tick:
+		i = p.handleTick(typedItem)
+	case *backtest.Stats:
+		i = p.handleStats(typedItem)
 	case error:
 		i = []byte(fmt.Sprintf("Error: %s", typedItem.Error()))
 	default:
@@ -80,3 +86,18 @@ func (*plain) handlePrice(price *gofer.Price) []byte {
 func (*plain) handleModel(node *gofer.Model) []byte {
 	return []byte(node.Pair.String())
 }
+
+func (p *plain) handleTick(tick *backtest.Tick) []byte {
+	return []byte(fmt.Sprintf("%s %s", tick.Time.In(time.UTC).Format(time.RFC3339), p.handlePrice(tick.Price)))
+}
+
+func (*plain) handleStats(stats *backtest.Stats) []byte {
+	return []byte(fmt.Sprintf(
+		"%s ticks:%d failures:%d quorumFailures:%d (%.2f%%)",
+		stats.Pair,
+		stats.Ticks,
+		stats.Failures,
+		stats.QuorumFailures,
+		stats.QuorumFailureRate()*100,
+	))
+}
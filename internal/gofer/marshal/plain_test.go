@@ -18,11 +18,13 @@ package marshal
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/makerdao/oracle-suite/internal/gofer/marshal/testutil"
 	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/gofer/backtest"
 )
 
 func TestPlain_Nodes(t *testing.T) {
@@ -78,3 +80,28 @@ C/D - something
 
 	assert.Equal(t, expected, b.String())
 }
+
+func TestPlain_Backtest(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newPlain()
+
+	ab := gofer.Pair{Base: "A", Quote: "B"}
+	ns := testutil.Prices(ab)
+
+	err = m.Write(b, &backtest.Tick{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Price: ns[ab]})
+	assert.NoError(t, err)
+
+	err = m.Write(b, &backtest.Stats{Pair: ab, Ticks: 4, Failures: 2, QuorumFailures: 1})
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+2021-01-01T00:00:00Z A/B 10.000000
+A/B ticks:4 failures:2 quorumFailures:1 (25.00%)
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
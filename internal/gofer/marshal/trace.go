@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/gofer/backtest"
 )
 
 type traceItem struct {
@@ -48,6 +49,10 @@ func (t *trace) Write(writer io.Writer, item interface{}) error {
 		i = t.handlePrice(typedItem)
 	case *gofer.Model:
 		i = t.handleModel(typedItem)
+	case *backtest.Tick:
+		i = t.handleTick(typedItem)
+	case *backtest.Stats:
+		i = t.handleStats(typedItem)
 	case error:
 		i = []byte(fmt.Sprintf("Error: %s", typedItem.Error()))
 	default:
@@ -70,8 +75,40 @@ func (t *trace) Flush() error {
 	return nil
 }
 
-func (*trace) handlePrice(price *gofer.Price) []byte {
-	tree := renderTree(func(node interface{}) ([]byte, []interface{}) {
+func (t *trace) handlePrice(price *gofer.Price) []byte {
+	buf := bytes.Buffer{}
+	buf.Write([]byte(fmt.Sprintf("Price for %s:\n", price.Pair)))
+	buf.Write(t.renderPrice(price))
+	return buf.Bytes()
+}
+
+func (t *trace) handleTick(tick *backtest.Tick) []byte {
+	buf := bytes.Buffer{}
+	buf.Write([]byte(fmt.Sprintf(
+		"Price for %s at %s:\n",
+		tick.Price.Pair,
+		tick.Time.In(time.UTC).Format(time.RFC3339),
+	)))
+	buf.Write(t.renderPrice(tick.Price))
+	return buf.Bytes()
+}
+
+func (*trace) handleStats(stats *backtest.Stats) []byte {
+	buf := bytes.Buffer{}
+	buf.Write([]byte(fmt.Sprintf("Backtest for %s:\n", stats.Pair)))
+	buf.Write(renderTree(func(interface{}) ([]byte, []interface{}) {
+		return renderNode("stats", []param{
+			{key: "failures", value: stats.Failures},
+			{key: "quorumFailureRate", value: stats.QuorumFailureRate()},
+			{key: "quorumFailures", value: stats.QuorumFailures},
+			{key: "ticks", value: stats.Ticks},
+		}, nil), nil
+	}, []interface{}{stats}, 0))
+	return buf.Bytes()
+}
+
+func (*trace) renderPrice(price *gofer.Price) []byte {
+	return renderTree(func(node interface{}) ([]byte, []interface{}) {
 		t := node.(*gofer.Price)
 		var tErr error
 		if t.Error != "" {
@@ -98,11 +135,6 @@ func (*trace) handlePrice(price *gofer.Price) []byte {
 
 		return s, c
 	}, []interface{}{price}, 0)
-
-	buf := bytes.Buffer{}
-	buf.Write([]byte(fmt.Sprintf("Price for %s:\n", price.Pair)))
-	buf.Write(tree)
-	return buf.Bytes()
 }
 
 func (t *trace) handleModel(node *gofer.Model) []byte {
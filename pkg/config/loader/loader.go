@@ -0,0 +1,384 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"gopkg.in/yaml.v3"
+)
+
+// IncludeKey is the name of the top-level field which contains a list of
+// files to be included in the configuration.
+const IncludeKey = "include"
+
+// ConfigErr is returned when a config file cannot be loaded.
+type ConfigErr struct {
+	Path string
+	Err  error
+}
+
+func (e ConfigErr) Error() string {
+	return fmt.Sprintf("failed to load config file %s: %s", e.Path, e.Err)
+}
+
+func (e ConfigErr) Unwrap() error {
+	return e.Err
+}
+
+// LookupEnvFunc is used to look up environment variables during
+// interpolation.
+type LookupEnvFunc func(key string) (string, bool)
+
+// Loader loads configuration files.
+//
+// The file format is chosen based on the file extension: ".yaml" and ".yml"
+// for YAML, ".hcl" for HCL, and JSON for all other files. Once parsed,
+// the configuration is decoded into the target structure using its JSON tags,
+// so the same structures may be used for all formats. Numbers are passed to
+// the JSON decoder as they are written in the file, so they are not rounded
+// to float64.
+//
+// References to environment variables, written as ${NAME}, are replaced with
+// their values in all string values. To use a literal "${", it must be
+// written as "$${".
+//
+// The top-level "include" field may contain a list of other configuration
+// files, with paths relative to the including file. Included files are merged
+// in the order in which they are listed, and then the including file is
+// merged on top of them. Objects are merged recursively, other values are
+// replaced.
+type Loader struct {
+	LookupEnv LookupEnvFunc
+}
+
+// LoadFile loads the configuration file from the given path into v using
+// the default Loader, which uses the process environment.
+func LoadFile(v interface{}, path string) error {
+	return (&Loader{LookupEnv: os.LookupEnv}).LoadFile(v, path)
+}
+
+// LoadFile loads the configuration file from the given path into v.
+func (l *Loader) LoadFile(v interface{}, path string) error {
+	tree, err := l.load(path, nil)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(tree)
+	if err != nil {
+		return ConfigErr{Path: path, Err: err}
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return ConfigErr{Path: path, Err: err}
+	}
+	return nil
+}
+
+func (l *Loader) load(path string, parents []string) (interface{}, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, ConfigErr{Path: path, Err: err}
+	}
+	for _, p := range parents {
+		if p == absPath {
+			return nil, ConfigErr{Path: path, Err: fmt.Errorf("cyclic include")}
+		}
+	}
+	parents = append(parents, absPath)
+
+	b, err := ioutil.ReadFile(absPath)
+	if err != nil {
+		return nil, ConfigErr{Path: path, Err: err}
+	}
+	tree, err := parse(filepath.Ext(absPath), b)
+	if err != nil {
+		return nil, ConfigErr{Path: path, Err: err}
+	}
+	tree, err = l.interpolate(tree)
+	if err != nil {
+		return nil, ConfigErr{Path: path, Err: err}
+	}
+
+	root, ok := tree.(map[string]interface{})
+	if !ok {
+		return tree, nil
+	}
+	includes, ok := root[IncludeKey]
+	if !ok {
+		return root, nil
+	}
+	delete(root, IncludeKey)
+	list, ok := includes.([]interface{})
+	if !ok {
+		return nil, ConfigErr{Path: path, Err: fmt.Errorf("the %s field must be a list of paths", IncludeKey)}
+	}
+
+	var merged interface{} = map[string]interface{}{}
+	for _, include := range list {
+		includePath, ok := include.(string)
+		if !ok {
+			return nil, ConfigErr{Path: path, Err: fmt.Errorf("the %s field must be a list of paths", IncludeKey)}
+		}
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(filepath.Dir(absPath), includePath)
+		}
+		included, err := l.load(includePath, parents)
+		if err != nil {
+			return nil, err
+		}
+		merged = merge(merged, included)
+	}
+	return merge(merged, root), nil
+}
+
+var envRegexp = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// interpolate replaces references to environment variables in all string
+// values of the tree.
+func (l *Loader) interpolate(tree interface{}) (interface{}, error) {
+	switch typed := tree.(type) {
+	case map[string]interface{}:
+		for k, v := range typed {
+			iv, err := l.interpolate(v)
+			if err != nil {
+				return nil, err
+			}
+			typed[k] = iv
+		}
+	case []interface{}:
+		for i, v := range typed {
+			iv, err := l.interpolate(v)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = iv
+		}
+	case string:
+		var err error
+		s := envRegexp.ReplaceAllStringFunc(typed, func(s string) string {
+			if strings.HasPrefix(s, "$$") {
+				return s[1:]
+			}
+			name := s[2 : len(s)-1]
+			val, ok := l.LookupEnv(name)
+			if !ok && err == nil {
+				err = fmt.Errorf("environment variable %s is not set", name)
+			}
+			return val
+		})
+		return s, err
+	}
+	return tree, nil
+}
+
+// merge merges src into dst. Objects are merged recursively, other values
+// from src replace values in dst.
+func merge(dst, src interface{}) interface{} {
+	dstMap, dstOK := dst.(map[string]interface{})
+	srcMap, srcOK := src.(map[string]interface{})
+	if !dstOK || !srcOK {
+		return src
+	}
+	for k, v := range srcMap {
+		if dv, ok := dstMap[k]; ok {
+			dstMap[k] = merge(dv, v)
+		} else {
+			dstMap[k] = v
+		}
+	}
+	return dstMap
+}
+
+func parse(ext string, b []byte) (interface{}, error) {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		return parseYAML(b)
+	case ".hcl":
+		return parseHCL(b)
+	default:
+		return parseJSON(b)
+	}
+}
+
+// jsonNumberRegexp matches numbers which are valid JSON numbers.
+var jsonNumberRegexp = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+func parseJSON(b []byte) (interface{}, error) {
+	var tree interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid data after the top-level value")
+	}
+	return tree, nil
+}
+
+func parseYAML(b []byte) (interface{}, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return nil, err
+	}
+	return yamlNode(&node, map[*yaml.Node]bool{})
+}
+
+// yamlNode converts the YAML node into a tree of maps and slices. Maps
+// with non-string keys are converted into maps with string keys, so they
+// can be encoded as JSON. The aliases map is used to detect recursive
+// aliases.
+func yamlNode(node *yaml.Node, aliases map[*yaml.Node]bool) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNode(node.Content[0], aliases)
+	case yaml.AliasNode:
+		if aliases[node] {
+			return nil, fmt.Errorf("line %d: recursive alias", node.Line)
+		}
+		aliases[node] = true
+		defer delete(aliases, node)
+		return yamlNode(node.Alias, aliases)
+	case yaml.SequenceNode:
+		l := make([]interface{}, 0, len(node.Content))
+		for _, n := range node.Content {
+			v, err := yamlNode(n, aliases)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, v)
+		}
+		return l, nil
+	case yaml.MappingNode:
+		m := map[string]interface{}{}
+		merged := map[string]interface{}{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, err := yamlNode(node.Content[i], aliases)
+			if err != nil {
+				return nil, err
+			}
+			v, err := yamlNode(node.Content[i+1], aliases)
+			if err != nil {
+				return nil, err
+			}
+			if node.Content[i].ShortTag() != "!!merge" {
+				m[fmt.Sprint(k)] = v
+				continue
+			}
+			// Keys from merged maps never override keys defined in the map,
+			// and keys from earlier merged maps take precedence:
+			srcs, ok := v.([]interface{})
+			if !ok {
+				srcs = []interface{}{v}
+			}
+			for _, src := range srcs {
+				srcMap, ok := src.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("line %d: map merge requires a map or a list of maps", node.Content[i].Line)
+				}
+				for mk, mv := range srcMap {
+					if _, ok := merged[mk]; !ok {
+						merged[mk] = mv
+					}
+				}
+			}
+		}
+		for k, v := range merged {
+			if _, ok := m[k]; !ok {
+				m[k] = v
+			}
+		}
+		return m, nil
+	case yaml.ScalarNode:
+		tag := node.ShortTag()
+		if (tag == "!!int" || tag == "!!float") && jsonNumberRegexp.MatchString(node.Value) {
+			return json.Number(node.Value), nil
+		}
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+	return nil, nil
+}
+
+func parseHCL(b []byte) (interface{}, error) {
+	file, err := hcl.ParseBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	return hclNode(file.Node), nil
+}
+
+// hclNode converts the HCL AST into a tree of maps and slices. Unlike
+// the decoder from the hcl package, blocks are converted into objects
+// instead of lists of objects, so the result has the same structure as
+// an equivalent JSON document.
+func hclNode(node ast.Node) interface{} {
+	switch typed := node.(type) {
+	case *ast.ObjectList:
+		m := map[string]interface{}{}
+		for _, item := range typed.Items {
+			target := m
+			for i, key := range item.Keys {
+				k := fmt.Sprint(key.Token.Value())
+				if i == len(item.Keys)-1 {
+					target[k] = merge(target[k], hclNode(item.Val))
+					break
+				}
+				next, ok := target[k].(map[string]interface{})
+				if !ok {
+					next = map[string]interface{}{}
+					target[k] = next
+				}
+				target = next
+			}
+		}
+		return m
+	case *ast.ObjectType:
+		return hclNode(typed.List)
+	case *ast.ListType:
+		l := make([]interface{}, 0, len(typed.List))
+		for _, n := range typed.List {
+			l = append(l, hclNode(n))
+		}
+		return l
+	case *ast.LiteralType:
+		// Numbers are not converted with Token.Value, because it rounds
+		// them to int64 or float64:
+		if typed.Token.Type == token.NUMBER || typed.Token.Type == token.FLOAT {
+			if jsonNumberRegexp.MatchString(typed.Token.Text) {
+				return json.Number(typed.Token.Text)
+			}
+		}
+		return typed.Token.Value()
+	}
+	return nil
+}
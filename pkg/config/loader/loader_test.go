@@ -0,0 +1,117 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package loader
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/gofer/config"
+)
+
+func testEnv(env map[string]string) LookupEnvFunc {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestLoader_LoadFile(t *testing.T) {
+	var cfg struct {
+		config.Config
+		Literal string `json:"literal"`
+	}
+	l := &Loader{LookupEnv: testEnv(map[string]string{"TEST_API_KEY": "secret"})}
+	require.NoError(t, l.LoadFile(&cfg, "./testdata/config.hcl"))
+
+	// Values from the HCL file:
+	assert.Equal(t, "openexchangerates", cfg.Origins["openexchangerates"].Type)
+	assert.JSONEq(t, `{"apiKey":"secret"}`, string(cfg.Origins["openexchangerates"].Params))
+
+	// The including file overrides included files:
+	require.Contains(t, cfg.PriceModels, "BTC/USD")
+	assert.Equal(t, "median", cfg.PriceModels["BTC/USD"].Method)
+	require.Len(t, cfg.PriceModels["BTC/USD"].Sources, 2)
+	assert.Equal(t, config.Source{Origin: "coinbasepro", Pair: "BTC/USD", TTL: 120}, cfg.PriceModels["BTC/USD"].Sources[1][0])
+	assert.JSONEq(t, `{"minimumSuccessfulSources":2}`, string(cfg.PriceModels["BTC/USD"].Params))
+
+	// Values from the included YAML file:
+	require.Contains(t, cfg.PriceModels, "ETH/USD")
+	require.Len(t, cfg.PriceModels["ETH/USD"].Sources, 2)
+	assert.Equal(t, []config.Source{{Origin: ".", Pair: "ETH/BTC"}, {Origin: ".", Pair: "BTC/USD"}}, cfg.PriceModels["ETH/USD"].Sources[1])
+
+	// Values from the JSON file included by the YAML file:
+	require.Contains(t, cfg.PriceModels, "ETH/BTC")
+	assert.Equal(t, "$${NOT_INTERPOLATED}"[1:], cfg.Literal)
+
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoader_LoadFile_CyclicInclude(t *testing.T) {
+	var cfg config.Config
+	l := &Loader{LookupEnv: testEnv(nil)}
+	assert.Error(t, l.LoadFile(&cfg, "./testdata/cyclic.json"))
+}
+
+func TestLoader_LoadFile_MissingEnv(t *testing.T) {
+	var cfg struct {
+		Password string `json:"password"`
+	}
+	l := &Loader{LookupEnv: testEnv(nil)}
+	assert.EqualError(
+		t,
+		l.LoadFile(&cfg, "./testdata/missing_env.yaml"),
+		"failed to load config file ./testdata/missing_env.yaml: environment variable TEST_MISSING_ENV is not set",
+	)
+
+	l = &Loader{LookupEnv: testEnv(map[string]string{"TEST_MISSING_ENV": "pass"})}
+	require.NoError(t, l.LoadFile(&cfg, "./testdata/missing_env.yaml"))
+	assert.Equal(t, "pass", cfg.Password)
+}
+
+func TestLoader_LoadFile_Numbers(t *testing.T) {
+	type numbers struct {
+		Int   int64    `json:"int"`
+		Uint  uint64   `json:"uint"`
+		Big   *big.Int `json:"big"`
+		Float float64  `json:"float"`
+	}
+	expected := numbers{
+		Int:   9007199254740993,
+		Uint:  18446744073709551615,
+		Float: 0.1,
+	}
+	expected.Big, _ = new(big.Int).SetString("1000000000000000000000000000001", 10)
+
+	l := &Loader{LookupEnv: testEnv(nil)}
+	for _, path := range []string{"./testdata/numbers.json", "./testdata/numbers.hcl"} {
+		t.Run(path, func(t *testing.T) {
+			var cfg numbers
+			require.NoError(t, l.LoadFile(&cfg, path))
+			assert.Equal(t, expected, cfg)
+		})
+	}
+	t.Run("./testdata/numbers.yaml", func(t *testing.T) {
+		var cfg struct {
+			Numbers numbers `json:"numbers"`
+		}
+		require.NoError(t, l.LoadFile(&cfg, "./testdata/numbers.yaml"))
+		assert.Equal(t, expected, cfg.Numbers)
+	})
+}
@@ -0,0 +1,19 @@
+include = ["models.yaml"]
+
+origins "openexchangerates" {
+  type = "openexchangerates"
+  params {
+    apiKey = "${TEST_API_KEY}"
+  }
+}
+
+priceModels "BTC/USD" {
+  method = "median"
+  sources = [
+    [{ origin = "bitstamp", pair = "BTC/USD" }],
+    [{ origin = "coinbasepro", pair = "BTC/USD", ttl = 120 }],
+  ]
+  params {
+    minimumSuccessfulSources = 2
+  }
+}
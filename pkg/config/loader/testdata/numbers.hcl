@@ -0,0 +1,4 @@
+int   = 9007199254740993
+uint  = 18446744073709551615
+big   = 1000000000000000000000000000001
+float = 0.1
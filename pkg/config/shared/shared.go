@@ -0,0 +1,112 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package shared contains configuration helpers used by more than one
+// application in the suite.
+package shared
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/multiformats/go-multiaddr"
+
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+)
+
+var ErrFailedToReadPassphraseFile = errors.New("failed to read the ethereum password file")
+var ErrFailedToParsePrivKeySeed = errors.New("failed to parse the privKeySeed field")
+
+// P2PAddrs are the address lists of the P2P transport configuration.
+type P2PAddrs struct {
+	ListenAddrs      []string
+	BootstrapAddrs   []string
+	DirectPeersAddrs []string
+	BlockedAddrs     []string
+}
+
+// GeneratePrivKey returns the private key of the P2P node. The key is derived
+// from the hex encoded seed, if the seed is empty, a random key is generated.
+func GeneratePrivKey(seed string) (crypto.PrivKey, error) {
+	seedReader := rand.Reader
+	if len(seed) != 0 {
+		b, err := hex.DecodeString(seed)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", ErrFailedToParsePrivKeySeed, err)
+		}
+		if len(b) != ed25519.SeedSize {
+			return nil, fmt.Errorf("%v: seed must be 32 bytes", ErrFailedToParsePrivKeySeed)
+		}
+		seedReader = bytes.NewReader(b)
+	}
+	privKey, _, err := crypto.GenerateEd25519Key(seedReader)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrFailedToParsePrivKeySeed, err)
+	}
+	return privKey, nil
+}
+
+// ReadAccountPassphrase reads the Ethereum account password from the file.
+// If path is empty, an empty password is returned.
+func ReadAccountPassphrase(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	passphraseFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%v: %v", ErrFailedToReadPassphraseFile, err)
+	}
+	return strings.TrimSuffix(string(passphraseFile), "\n"), nil
+}
+
+// ValidateEthereum checks the "ethereum" section of a configuration.
+func ValidateEthereum(errs *validation.Errors, from, password string) {
+	if !ethereum.IsHexAddress(from) {
+		errs.Add(validation.Pointer("ethereum", "from"), "invalid address %s", from)
+	}
+	if _, err := ReadAccountPassphrase(password); err != nil {
+		errs.Add(validation.Pointer("ethereum", "password"), "%s", err)
+	}
+}
+
+// ValidateP2P checks the "p2p" section of a configuration.
+func ValidateP2P(errs *validation.Errors, privKeySeed string, addrs P2PAddrs) {
+	if _, err := GeneratePrivKey(privKeySeed); err != nil {
+		errs.Add(validation.Pointer("p2p", "privKeySeed"), "%s", err)
+	}
+	for _, list := range []struct {
+		name  string
+		addrs []string
+	}{
+		{name: "listenAddrs", addrs: addrs.ListenAddrs},
+		{name: "bootstrapAddrs", addrs: addrs.BootstrapAddrs},
+		{name: "directPeersAddrs", addrs: addrs.DirectPeersAddrs},
+		{name: "blockedAddrs", addrs: addrs.BlockedAddrs},
+	} {
+		for i, addr := range list.addrs {
+			if _, err := multiaddr.NewMultiaddr(addr); err != nil {
+				errs.Add(validation.Pointer("p2p", list.name, i), "%s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package shared
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+)
+
+func TestGeneratePrivKey(t *testing.T) {
+	seed := "0000000000000000000000000000000000000000000000000000000000000001"
+	k1, err := GeneratePrivKey(seed)
+	require.NoError(t, err)
+	k2, err := GeneratePrivKey(seed)
+	require.NoError(t, err)
+	assert.True(t, k1.Equals(k2))
+
+	_, err = GeneratePrivKey("")
+	assert.NoError(t, err)
+	_, err = GeneratePrivKey("00")
+	assert.Error(t, err)
+	_, err = GeneratePrivKey("x")
+	assert.Error(t, err)
+}
+
+func TestReadAccountPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, ioutil.WriteFile(path, []byte("secret\n"), 0600))
+
+	p, err := ReadAccountPassphrase(path)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", p)
+
+	p, err = ReadAccountPassphrase("")
+	require.NoError(t, err)
+	assert.Equal(t, "", p)
+
+	_, err = ReadAccountPassphrase(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestValidateEthereum(t *testing.T) {
+	var errs validation.Errors
+	ValidateEthereum(&errs, "0x2d800d93b065ce011af83f316cef9f0d005b0aa4", "")
+	assert.NoError(t, errs.Err())
+
+	ValidateEthereum(&errs, "x", "/nonexistent")
+	require.Len(t, errs, 2)
+	assert.Equal(t, "/ethereum/from", errs[0].Pointer)
+	assert.Equal(t, "/ethereum/password", errs[1].Pointer)
+}
+
+func TestValidateP2P(t *testing.T) {
+	var errs validation.Errors
+	ValidateP2P(&errs, "", P2PAddrs{ListenAddrs: []string{"/ip4/0.0.0.0/tcp/8000"}})
+	assert.NoError(t, errs.Err())
+
+	ValidateP2P(&errs, "00", P2PAddrs{
+		ListenAddrs:  []string{"/ip4/0.0.0.0/tcp/8000"},
+		BlockedAddrs: []string{"/ip4/0.0.0.0/tcp/8000", "invalid"},
+	})
+	require.Len(t, errs, 2)
+	assert.Equal(t, "/p2p/privKeySeed", errs[0].Pointer)
+	assert.Equal(t, "/p2p/blockedAddrs/1", errs[1].Pointer)
+}
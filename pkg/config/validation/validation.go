@@ -0,0 +1,120 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error describes a single problem found in a configuration.
+type Error struct {
+	// Pointer is a JSON pointer (RFC 6901) to the invalid value.
+	Pointer string
+	// Message describes the problem.
+	Message string
+}
+
+func (e Error) Error() string {
+	if e.Pointer == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Pointer, e.Message)
+}
+
+// Errors is a list of all problems found in a configuration.
+type Errors []Error
+
+func (e Errors) Error() string {
+	s := strings.Builder{}
+	s.WriteString(fmt.Sprintf("%d problem(s) found in the configuration:", len(e)))
+	for _, err := range e {
+		s.WriteString("\n\t")
+		s.WriteString(err.Error())
+	}
+	return s.String()
+}
+
+// Add adds a new problem for the value under the given JSON pointer.
+func (e *Errors) Add(pointer string, format string, args ...interface{}) {
+	*e = append(*e, Error{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns nil if there are no problems, otherwise it returns the list
+// itself.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Join merges problems from multiple errors into a single list. Errors other
+// than Errors or Error are added as problems without a pointer. It returns
+// nil if there are no problems.
+func Join(errs ...error) error {
+	var r Errors
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		var vErrs Errors
+		var vErr Error
+		switch {
+		case errors.As(err, &vErrs):
+			r = append(r, vErrs...)
+		case errors.As(err, &vErr):
+			r = append(r, vErr)
+		default:
+			r = append(r, Error{Message: err.Error()})
+		}
+	}
+	return r.Err()
+}
+
+// Pointer builds a JSON pointer from the given reference tokens. Tokens may
+// be strings or integers.
+func Pointer(tokens ...interface{}) string {
+	s := strings.Builder{}
+	for _, t := range tokens {
+		s.WriteByte('/')
+		switch tt := t.(type) {
+		case int:
+			s.WriteString(strconv.Itoa(tt))
+		default:
+			r := strings.NewReplacer("~", "~0", "/", "~1")
+			s.WriteString(r.Replace(fmt.Sprint(tt)))
+		}
+	}
+	return s.String()
+}
+
+// Prefix prepends the given reference tokens to pointers of all problems
+// found in err. It is used when a configuration is embedded in another one.
+// Errors other than Errors or Error are treated the same way as in Join.
+func Prefix(err error, tokens ...interface{}) error {
+	var errs Errors
+	if !errors.As(Join(err), &errs) {
+		return nil
+	}
+	r := make(Errors, len(errs))
+	for i, e := range errs {
+		r[i] = Error{Pointer: Pointer(tokens...) + e.Pointer, Message: e.Message}
+	}
+	return r
+}
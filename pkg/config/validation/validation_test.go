@@ -0,0 +1,63 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointer(t *testing.T) {
+	assert.Equal(t, "", Pointer())
+	assert.Equal(t, "/priceModels/BTC~1USD/sources/0/1", Pointer("priceModels", "BTC/USD", "sources", 0, 1))
+	assert.Equal(t, "/a~0b", Pointer("a~b"))
+}
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+	assert.NoError(t, errs.Err())
+
+	errs.Add("/a", "invalid %s", "a")
+	errs.Add("", "invalid b")
+	assert.Error(t, errs.Err())
+	assert.Equal(t, "2 problem(s) found in the configuration:\n\t/a: invalid a\n\tinvalid b", errs.Error())
+}
+
+func TestJoin(t *testing.T) {
+	var errs Errors
+	errs.Add("/a", "a")
+
+	assert.NoError(t, Join(nil, Errors(nil).Err()))
+	assert.Equal(t, Errors{
+		{Pointer: "/a", Message: "a"},
+		{Pointer: "/b", Message: "b"},
+		{Message: "c"},
+	}, Join(errs, Error{Pointer: "/b", Message: "b"}, errors.New("c")))
+}
+
+func TestPrefix(t *testing.T) {
+	var errs Errors
+	errs.Add("/a", "a")
+	errs.Add("", "b")
+
+	assert.NoError(t, Prefix(nil, "x"))
+	assert.Equal(t, Errors{
+		{Pointer: "/x/0/a", Message: "a"},
+		{Pointer: "/x/0", Message: "b"},
+	}, Prefix(errs, "x", 0))
+}
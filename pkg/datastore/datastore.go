@@ -19,8 +19,10 @@ import (
 	"errors"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
 	"github.com/makerdao/oracle-suite/pkg/transport"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
@@ -28,19 +30,35 @@ import (
 
 const LoggerTag = "DATASTORE"
 
+// DefaultStorageExpiration is used when Config.StorageExpiration is not set.
+const DefaultStorageExpiration = time.Hour
+
+// storagePruneInterval is the interval in which expired prices are removed
+// from the storage.
+const storagePruneInterval = 10 * time.Minute
+
 var errInvalidSignature = errors.New("received price has an invalid signature")
 var errInvalidPrice = errors.New("received price is invalid")
 var errUnknownPair = errors.New("received pair is not configured")
 var errUnknownFeeder = errors.New("feeder is not allowed to send prices")
 
+// subscriptionBufferSize is the size of the buffer of channels returned
+// by the Subscribe method.
+const subscriptionBufferSize = 100
+
 // Datastore reads and stores prices from the P2P network.
 type Datastore struct {
-	mu sync.Mutex
+	mu     sync.Mutex
+	subsMu sync.Mutex
 
 	signer     ethereum.Signer
 	transport  transport.Transport
 	pairs      map[string]*Pair
 	priceStore *PriceStore
+	storage    Storage
+	monitor    *Monitor
+	subs       map[chan ReceivedPrice]struct{}
+	expiration time.Duration
 	log        log.Logger
 	doneCh     chan struct{}
 }
@@ -55,11 +73,29 @@ type Config struct {
 	// Pairs is the list supported pairs by the datastore with their
 	// configuration.
 	Pairs map[string]*Pair
+	// Storage is an optional persistence layer. If set, collected prices
+	// are saved to it and reloaded when the Datastore starts.
+	Storage Storage
+	// StorageExpiration is the maximum age of prices kept in the Storage.
+	// If zero, DefaultStorageExpiration is used.
+	StorageExpiration time.Duration
+	// History configures the history of prices kept for each feeder. By
+	// default, only the latest price is kept.
+	History HistoryConfig
+	// Monitor is an optional monitor which tracks the performance of
+	// feeders using received prices.
+	Monitor *Monitor
 	// Logger is a current logger interface used by the Datastore.
 	// The Logger is required to monitor asynchronous processes.
 	Logger log.Logger
 }
 
+// ReceivedPrice is a price accepted by the Datastore, sent to subscribers.
+type ReceivedPrice struct {
+	Feeder ethereum.Address
+	Price  *messages.Price
+}
+
 type Pair struct {
 	// Feeds is the list of Ethereum addresses from which prices will be
 	// accepted.
@@ -67,11 +103,18 @@ type Pair struct {
 }
 
 func NewDatastore(config Config) *Datastore {
+	if config.StorageExpiration == 0 {
+		config.StorageExpiration = DefaultStorageExpiration
+	}
 	return &Datastore{
 		signer:     config.Signer,
 		transport:  config.Transport,
 		pairs:      config.Pairs,
-		priceStore: NewPriceStore(),
+		priceStore: NewPriceStoreWithHistory(config.History),
+		storage:    config.Storage,
+		monitor:    config.Monitor,
+		subs:       make(map[chan ReceivedPrice]struct{}),
+		expiration: config.StorageExpiration,
 		log:        config.Logger.WithField("tag", LoggerTag),
 		doneCh:     make(chan struct{}),
 	}
@@ -83,6 +126,10 @@ func (c *Datastore) Start() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if err := c.loadStorage(); err != nil {
+		return err
+	}
+
 	return c.collectorLoop()
 }
 
@@ -98,6 +145,10 @@ func (c *Datastore) Stop() error {
 		return err
 	}
 
+	if c.storage != nil {
+		return c.storage.Close()
+	}
+
 	return nil
 }
 
@@ -105,27 +156,158 @@ func (c *Datastore) Prices() *PriceStore {
 	return c.priceStore
 }
 
+// Subscribe returns a channel to which all newly accepted prices are sent.
+// The returned function must be called to unsubscribe. If the subscriber
+// does not read prices fast enough, new prices are dropped.
+func (c *Datastore) Subscribe() (<-chan ReceivedPrice, func()) {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	ch := make(chan ReceivedPrice, subscriptionBufferSize)
+	c.subs[ch] = struct{}{}
+	return ch, func() {
+		c.subsMu.Lock()
+		defer c.subsMu.Unlock()
+		if _, ok := c.subs[ch]; ok {
+			delete(c.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// HealthStatus implements the health.Checker interface. It reports
+// the number of prices stored for each configured pair.
+func (c *Datastore) HealthStatus() health.Status {
+	prices := make(map[string]interface{}, len(c.pairs))
+	for assetPair := range c.pairs {
+		prices[assetPair] = len(c.priceStore.AssetPair(assetPair))
+	}
+	return health.Status{
+		Ready:   true,
+		Details: map[string]interface{}{"prices": prices},
+	}
+}
+
 // collectPrice adds a price from a feeder which may be used to update
 // Oracle contract. The price will be added only if a feeder is
 // allowed to send prices.
 func (c *Datastore) collectPrice(msg *messages.Price) error {
+	from, err := c.validatePrice(msg)
+	if err != nil {
+		if c.monitor != nil {
+			c.monitor.RecordRejectedPrice(from, msg, rejectReason(err))
+		}
+		return err
+	}
+
+	c.priceStore.Add(*from, msg)
+
+	if c.monitor != nil {
+		c.monitor.RecordPrice(*from, msg, time.Now())
+	}
+
+	c.notify(ReceivedPrice{Feeder: *from, Price: msg})
+
+	if c.storage != nil {
+		if err := c.storage.Add(*from, msg); err != nil {
+			c.log.
+				WithError(err).
+				WithFields(msg.Price.Fields(c.signer)).
+				Warn("Unable to save price in the storage")
+		}
+	}
+
+	return nil
+}
+
+// notify sends the price to all subscribers.
+func (c *Datastore) notify(p ReceivedPrice) {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	for ch := range c.subs {
+		select {
+		case ch <- p:
+		default:
+			c.log.
+				WithFields(p.Price.Price.Fields(c.signer)).
+				Warn("Subscriber buffer is full, price dropped")
+		}
+	}
+}
+
+// validatePrice verifies the price signature and checks if the feeder is
+// allowed to send prices for the asset pair. It returns the feeder address,
+// which is also returned with an error if the signature is valid.
+func (c *Datastore) validatePrice(msg *messages.Price) (*ethereum.Address, error) {
 	from, err := msg.Price.From(c.signer)
 	if err != nil {
-		return errInvalidSignature
+		return nil, errInvalidSignature
 	}
 	if _, ok := c.pairs[msg.Price.Wat]; !ok {
-		return errUnknownPair
+		return from, errUnknownPair
 	}
 	if !c.isFeedAllowed(msg.Price.Wat, *from) {
-		return errUnknownFeeder
+		return from, errUnknownFeeder
 	}
 	if msg.Price.Val.Cmp(big.NewInt(0)) <= 0 {
-		return errInvalidPrice
+		return from, errInvalidPrice
 	}
+	return from, nil
+}
 
-	c.priceStore.Add(*from, msg)
+// rejectReason returns the Monitor reject reason for the error returned
+// by validatePrice.
+func rejectReason(err error) string {
+	switch err {
+	case errInvalidSignature:
+		return RejectInvalidSignature
+	case errUnknownPair:
+		return RejectUnknownPair
+	case errUnknownFeeder:
+		return RejectUnknownFeeder
+	default:
+		return RejectInvalidPrice
+	}
+}
 
-	return nil
+// loadStorage loads prices saved in the storage. Prices are validated
+// again, because the list of feeders might have changed since they were
+// saved. Expired prices are skipped and removed from the storage.
+func (c *Datastore) loadStorage() error {
+	if c.storage == nil {
+		return nil
+	}
+	msgs, err := c.storage.Load()
+	if err != nil {
+		return err
+	}
+	expiredBefore := time.Now().Add(-c.expiration)
+	loaded := 0
+	for _, msg := range msgs {
+		if msg.Price.Age.Before(expiredBefore) {
+			continue
+		}
+		from, err := c.validatePrice(msg)
+		if err != nil {
+			c.log.
+				WithError(err).
+				WithFields(msg.Price.Fields(c.signer)).
+				Warn("Skipping invalid price from the storage")
+			continue
+		}
+		c.priceStore.Add(*from, msg)
+		loaded++
+	}
+	c.log.WithField("prices", loaded).Info("Prices loaded from the storage")
+	return c.storage.Prune(expiredBefore)
+}
+
+// pruneStorage removes expired prices from the storage.
+func (c *Datastore) pruneStorage() {
+	if err := c.storage.Prune(time.Now().Add(-c.expiration)); err != nil {
+		c.log.WithError(err).Warn("Unable to prune the storage")
+	}
 }
 
 // collectorLoop creates a asynchronous loop which fetches prices from feeders.
@@ -134,10 +316,19 @@ func (c *Datastore) collectorLoop() error {
 		c.mu.Lock()
 		defer c.mu.Unlock()
 
+		var pruneCh <-chan time.Time
+		if c.storage != nil {
+			ticker := time.NewTicker(storagePruneInterval)
+			defer ticker.Stop()
+			pruneCh = ticker.C
+		}
+
 		for {
 			select {
 			case <-c.doneCh:
 				return
+			case <-pruneCh:
+				c.pruneStorage()
 			case status := <-c.transport.WaitFor(messages.PriceMessageName):
 				// If there was a problem while reading prices from the transport:
 				if status.Error != nil {
@@ -16,11 +16,13 @@
 package datastore
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/makerdao/oracle-suite/pkg/datastore/testutil"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
@@ -74,6 +76,10 @@ func TestDatastore_Prices(t *testing.T) {
 	assert.Contains(t, toOraclePrices(aaabbb), testutil.PriceAAABBB2.Price)
 	assert.Contains(t, toOraclePrices(xxxyyy), testutil.PriceXXXYYY1.Price)
 	assert.Contains(t, toOraclePrices(xxxyyy), testutil.PriceXXXYYY2.Price)
+
+	s := ds.HealthStatus()
+	assert.True(t, s.Ready)
+	assert.Equal(t, map[string]interface{}{"AAABBB": 2, "XXXYYY": 2}, s.Details["prices"])
 }
 
 func toOraclePrices(ps []*messages.Price) []*oracle.Price {
@@ -83,3 +89,38 @@ func toOraclePrices(ps []*messages.Price) []*oracle.Price {
 	}
 	return r
 }
+
+func TestDatastore_Storage(t *testing.T) {
+	sig := &mocks.Signer{}
+	sig.On("Recover", testutil.PriceAAABBB1.Price.Signature(), mock.Anything).Return(&testutil.Address1, nil)
+	sig.On("Recover", testutil.PriceAAABBB2.Price.Signature(), mock.Anything).Return(&testutil.Address2, nil)
+	sig.On("Recover", testutil.PriceXXXYYY1.Price.Signature(), mock.Anything).Return(&testutil.Address1, nil)
+
+	path := filepath.Join(t.TempDir(), "prices.jsonl")
+	s, err := NewFileStorage(path)
+	require.NoError(t, err)
+	assert.NoError(t, s.Add(testutil.Address1, testutil.PriceAAABBB1))
+	assert.NoError(t, s.Add(testutil.Address2, testutil.PriceAAABBB2)) // feeder is not allowed
+	assert.NoError(t, s.Add(testutil.Address1, testutil.PriceXXXYYY1)) // pair is not configured
+	assert.NoError(t, s.Close())
+
+	s, err = NewFileStorage(path)
+	require.NoError(t, err)
+	tra := local.New(0)
+	ds := NewDatastore(Config{
+		Signer:    sig,
+		Transport: tra,
+		Pairs: map[string]*Pair{
+			"AAABBB": {Feeds: []ethereum.Address{testutil.Address1}},
+		},
+		Storage:           s,
+		StorageExpiration: time.Since(time.Unix(0, 0)),
+		Logger:            null.New(),
+	})
+	assert.NoError(t, ds.transport.Subscribe(messages.PriceMessageName, (*messages.Price)(nil)))
+	assert.NoError(t, ds.Start())
+	defer ds.Stop()
+
+	assert.Equal(t, []*oracle.Price{testutil.PriceAAABBB1.Price}, toOraclePrices(ds.Prices().AssetPair("AAABBB")))
+	assert.Empty(t, ds.Prices().AssetPair("XXXYYY"))
+}
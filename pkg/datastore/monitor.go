@@ -0,0 +1,348 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package datastore
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+// Reasons for which a price may be rejected by the Datastore.
+const (
+	RejectInvalidSignature = "invalidSignature"
+	RejectUnknownPair      = "unknownPair"
+	RejectUnknownFeeder    = "unknownFeeder"
+	RejectInvalidPrice     = "invalidPrice"
+)
+
+// DefaultMonitorWindow is used when MonitorConfig.Window is not set.
+const DefaultMonitorWindow = time.Hour
+
+var (
+	feederLastMessage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "datastore",
+			Subsystem: "feeder",
+			Name:      "last_message_timestamp_seconds",
+			Help:      "Time when the last valid price was received from the feeder.",
+		},
+		[]string{"pair", "feeder"},
+	)
+	feederMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "datastore",
+			Subsystem: "feeder",
+			Name:      "messages_total",
+			Help:      "Number of valid prices received from the feeder.",
+		},
+		[]string{"pair", "feeder"},
+	)
+	feederDeviation = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "datastore",
+			Subsystem: "feeder",
+			Name:      "deviation_percent",
+			Help:      "Deviation of the last price from the feeder from the median of the last prices from all feeders.",
+		},
+		[]string{"pair", "feeder"},
+	)
+	feederRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "datastore",
+			Subsystem: "feeder",
+			Name:      "rejected_prices_total",
+			Help:      "Number of prices from the feeder which were rejected.",
+		},
+		[]string{"pair", "feeder", "reason"},
+	)
+	otherRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "datastore",
+			Subsystem: "feeder",
+			Name:      "other_rejected_prices_total",
+			Help:      "Number of rejected prices from feeders or for asset pairs which are not configured.",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(feederLastMessage, feederMessages, feederDeviation, feederRejected, otherRejected)
+}
+
+// Monitor tracks the performance and liveness of feeders, using prices
+// received by the Datastore and prices rejected by the transport.
+type Monitor struct {
+	mu sync.Mutex
+
+	window  time.Duration
+	pairs   map[string]*Pair
+	feeders map[FeederPrice]*feederStats
+	other   map[string]int
+}
+
+type MonitorConfig struct {
+	// Window is the time window used to calculate the message rate.
+	// If zero, DefaultMonitorWindow is used.
+	Window time.Duration
+	// Pairs is the list of asset pairs and feeders, usually the same as in
+	// the Datastore config. Stats are kept per feeder only for feeders
+	// from this list, prices from any other feeder or for any other asset
+	// pair are counted together, grouped by the reject reason only. This
+	// prevents peers from growing the stats without bounds.
+	Pairs map[string]*Pair
+}
+
+type feederStats struct {
+	lastMessage time.Time
+	lastPrice   *messages.Price
+	messages    int
+	received    []time.Time
+	rejected    map[string]int
+}
+
+// FeederStatus describes the performance of a feeder for an asset pair.
+type FeederStatus struct {
+	AssetPair string `json:"assetPair"`
+	// Feeder is the address of the feeder.
+	Feeder string `json:"feeder"`
+	// Live is true if a valid price was received from the feeder in
+	// the monitor window.
+	Live bool `json:"live"`
+	// LastMessage is the time when the last valid price was received.
+	LastMessage time.Time `json:"lastMessage"`
+	// SinceLastMessage is the time in seconds since the last valid price
+	// was received.
+	SinceLastMessage float64 `json:"sinceLastMessage"`
+	// LastPrice is the value of the last valid price.
+	LastPrice float64 `json:"lastPrice"`
+	// LastPriceAge is the age of the last valid price.
+	LastPriceAge time.Time `json:"lastPriceAge"`
+	// Messages is the total number of valid prices received.
+	Messages int `json:"messages"`
+	// MessageRate is the number of valid prices per minute received in
+	// the monitor window.
+	MessageRate float64 `json:"messageRate"`
+	// Deviation is the deviation in percent of the last price from
+	// the median of the last prices from all feeders.
+	Deviation float64 `json:"deviation"`
+	// Rejected is the number of rejected prices grouped by the reason.
+	Rejected map[string]int `json:"rejected"`
+}
+
+// NewMonitor creates a new Monitor instance.
+func NewMonitor(cfg MonitorConfig) *Monitor {
+	if cfg.Window == 0 {
+		cfg.Window = DefaultMonitorWindow
+	}
+	return &Monitor{
+		window:  cfg.Window,
+		pairs:   cfg.Pairs,
+		feeders: make(map[FeederPrice]*feederStats),
+		other:   make(map[string]int),
+	}
+}
+
+// RecordPrice records a valid price received from the feeder.
+func (m *Monitor) RecordPrice(from ethereum.Address, msg *messages.Price, received time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fp := FeederPrice{AssetPair: msg.Price.Wat, Feeder: from}
+	if !m.isFeeder(fp) {
+		return
+	}
+	s := m.stats(fp)
+	s.lastMessage = received
+	s.messages++
+	s.received = append(s.received, received)
+	s.trim(received.Add(-m.window))
+	if s.lastPrice == nil || !s.lastPrice.Price.Age.After(msg.Price.Age) {
+		s.lastPrice = msg
+	}
+
+	labels := prometheus.Labels{"pair": fp.AssetPair, "feeder": fp.Feeder.String()}
+	feederLastMessage.With(labels).Set(float64(received.Unix()))
+	feederMessages.With(labels).Inc()
+
+	// A new price changes the median, so deviations of all feeders must be
+	// updated:
+	median := m.median(fp.AssetPair)
+	for f, s := range m.feeders {
+		if f.AssetPair != fp.AssetPair || s.lastPrice == nil {
+			continue
+		}
+		feederDeviation.
+			With(prometheus.Labels{"pair": f.AssetPair, "feeder": f.Feeder.String()}).
+			Set(deviation(s.lastPrice.Price.Float64Price(), median))
+	}
+}
+
+// RecordRejectedPrice records a price rejected for the given reason. The
+// feeder is nil if it cannot be determined, e.g. when the signature is
+// invalid. Prices from unknown feeders, for unknown asset pairs or with
+// an invalid signature are recorded only in the other rejections.
+func (m *Monitor) RecordRejectedPrice(from *ethereum.Address, msg *messages.Price, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if from == nil || !m.isFeeder(FeederPrice{AssetPair: msg.Price.Wat, Feeder: *from}) {
+		m.other[reason]++
+		otherRejected.With(prometheus.Labels{"reason": reason}).Inc()
+		return
+	}
+	fp := FeederPrice{AssetPair: msg.Price.Wat, Feeder: *from}
+	s := m.stats(fp)
+	s.rejected[reason]++
+
+	feederRejected.
+		With(prometheus.Labels{"pair": fp.AssetPair, "feeder": fp.Feeder.String(), "reason": reason}).
+		Inc()
+}
+
+// Status returns statuses of all configured feeders, sorted by the asset
+// pair and the feeder address. Feeders which have never sent a price are
+// included too, so dead feeders can be detected.
+func (m *Monitor) Status(now time.Time) []*FeederStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for assetPair, p := range m.pairs {
+		for _, f := range p.Feeds {
+			m.stats(FeederPrice{AssetPair: assetPair, Feeder: f})
+		}
+	}
+
+	medians := map[string]float64{}
+	var statuses []*FeederStatus
+	for fp, s := range m.feeders {
+		s.trim(now.Add(-m.window))
+		st := &FeederStatus{
+			AssetPair:   fp.AssetPair,
+			Feeder:      fp.Feeder.String(),
+			LastMessage: s.lastMessage,
+			Messages:    s.messages,
+			Live:        len(s.received) > 0,
+			MessageRate: float64(len(s.received)) / m.window.Minutes(),
+			Rejected:    map[string]int{},
+		}
+		for r, n := range s.rejected {
+			st.Rejected[r] = n
+		}
+		if s.lastPrice != nil {
+			if _, ok := medians[fp.AssetPair]; !ok {
+				medians[fp.AssetPair] = m.median(fp.AssetPair)
+			}
+			st.SinceLastMessage = now.Sub(s.lastMessage).Seconds()
+			st.LastPrice = s.lastPrice.Price.Float64Price()
+			st.LastPriceAge = s.lastPrice.Price.Age
+			st.Deviation = deviation(st.LastPrice, medians[fp.AssetPair])
+		}
+		statuses = append(statuses, st)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].AssetPair != statuses[j].AssetPair {
+			return statuses[i].AssetPair < statuses[j].AssetPair
+		}
+		return strings.ToLower(statuses[i].Feeder) < strings.ToLower(statuses[j].Feeder)
+	})
+	return statuses
+}
+
+// OtherRejected returns the number of rejected prices which are not
+// assigned to any configured feeder, grouped by the reason.
+func (m *Monitor) OtherRejected() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rejected := map[string]int{}
+	for r, n := range m.other {
+		rejected[r] = n
+	}
+	return rejected
+}
+
+// isFeeder returns true if the feeder is configured for the asset pair.
+func (m *Monitor) isFeeder(fp FeederPrice) bool {
+	p, ok := m.pairs[fp.AssetPair]
+	if !ok {
+		return false
+	}
+	for _, f := range p.Feeds {
+		if f == fp.Feeder {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Monitor) stats(fp FeederPrice) *feederStats {
+	s, ok := m.feeders[fp]
+	if !ok {
+		s = &feederStats{rejected: map[string]int{}}
+		m.feeders[fp] = s
+	}
+	return s
+}
+
+// median returns the median of the last prices from all feeders for
+// the asset pair.
+func (m *Monitor) median(assetPair string) float64 {
+	var prices []float64
+	for fp, s := range m.feeders {
+		if fp.AssetPair == assetPair && s.lastPrice != nil {
+			prices = append(prices, s.lastPrice.Price.Float64Price())
+		}
+	}
+	if len(prices) == 0 {
+		return 0
+	}
+	sort.Float64s(prices)
+	if n := len(prices); n%2 == 0 {
+		return (prices[n/2-1] + prices[n/2]) / 2
+	}
+	return prices[len(prices)/2]
+}
+
+// trim removes receive times older than the given time.
+func (s *feederStats) trim(olderThan time.Time) {
+	n := 0
+	for n < len(s.received) && s.received[n].Before(olderThan) {
+		n++
+	}
+	if n > 0 {
+		s.received = append([]time.Time(nil), s.received[n:]...)
+	}
+}
+
+// deviation returns the deviation of the price from the median in percent.
+func deviation(price, median float64) float64 {
+	if median == 0 {
+		return 0
+	}
+	d := (price - median) / median * 100
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,86 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/datastore/testutil"
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+)
+
+func TestMonitor(t *testing.T) {
+	address3 := ethereum.HexToAddress("0x9d800d93b065ce011af83f316cef9f0d005b0aa4")
+	address4 := ethereum.HexToAddress("0x0000000000000000000000000000000000000004")
+	address5 := ethereum.HexToAddress("0x0000000000000000000000000000000000000005")
+	now := time.Unix(1000, 0)
+	m := NewMonitor(MonitorConfig{
+		Window: 10 * time.Minute,
+		Pairs: map[string]*Pair{
+			"AAABBB": {Feeds: []ethereum.Address{testutil.Address1, testutil.Address2, address3}},
+			"XXXYYY": {Feeds: []ethereum.Address{address5}},
+		},
+	})
+
+	m.RecordPrice(testutil.Address1, testutil.PriceAAABBB1, now.Add(-20*time.Minute)) // outside the window
+	m.RecordPrice(testutil.Address1, testutil.PriceAAABBB1, now.Add(-2*time.Minute))
+	m.RecordPrice(testutil.Address2, testutil.PriceAAABBB2, now.Add(-time.Minute))
+	m.RecordPrice(address3, testutil.PriceAAABBB3, now.Add(-time.Minute))
+	m.RecordRejectedPrice(&testutil.Address2, testutil.PriceAAABBB1, RejectInvalidPrice)
+	m.RecordRejectedPrice(nil, testutil.PriceAAABBB1, RejectInvalidSignature)
+	m.RecordRejectedPrice(&address4, testutil.PriceAAABBB1, RejectUnknownFeeder)
+	m.RecordRejectedPrice(&testutil.Address1, testutil.PriceXXXYYY1, RejectUnknownPair)
+	m.RecordPrice(address4, testutil.PriceAAABBB1, now)
+
+	s := m.Status(now)
+	require.Len(t, s, 4)
+
+	// Prices which cannot be assigned to a configured feeder are counted
+	// only by the reason:
+	assert.Equal(t, map[string]int{
+		RejectInvalidSignature: 1,
+		RejectUnknownFeeder:    1,
+		RejectUnknownPair:      1,
+	}, m.OtherRejected())
+
+	assert.Equal(t, testutil.Address1.String(), s[0].Feeder)
+	assert.Equal(t, 2, s[0].Messages)
+	assert.Equal(t, 0.1, s[0].MessageRate)
+	assert.Equal(t, float64(120), s[0].SinceLastMessage)
+	assert.Equal(t, testutil.PriceAAABBB1.Price.Age, s[0].LastPriceAge)
+	assert.InDelta(t, 50, s[0].Deviation, 1e-9)
+	assert.Empty(t, s[0].Rejected)
+
+	assert.Equal(t, testutil.Address2.String(), s[1].Feeder)
+	assert.InDelta(t, 0, s[1].Deviation, 1e-9)
+	assert.Equal(t, map[string]int{RejectInvalidPrice: 1}, s[1].Rejected)
+
+	assert.Equal(t, address3.String(), s[2].Feeder)
+	assert.InDelta(t, 50, s[2].Deviation, 1e-9)
+
+	// Configured feeders which have never sent a price are not live:
+	assert.Equal(t, "XXXYYY", s[3].AssetPair)
+	assert.Equal(t, address5.String(), s[3].Feeder)
+	assert.False(t, s[3].Live)
+	assert.Equal(t, 0, s[3].Messages)
+	for _, st := range s[:3] {
+		assert.True(t, st.Live)
+	}
+}
@@ -16,7 +16,9 @@
 package datastore
 
 import (
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
@@ -27,17 +29,42 @@ type FeederPrice struct {
 	Feeder    ethereum.Address
 }
 
+// HistoryConfig configures how many historical prices are kept for each
+// feeder. If both fields are zero, the history is disabled.
+type HistoryConfig struct {
+	// Size is the maximum number of prices kept for each feeder and asset
+	// pair. If zero, the number of prices is not limited.
+	Size int
+	// Duration is the maximum age of kept prices, relative to the newest
+	// price from the same feeder. If zero, the age is not limited.
+	Duration time.Duration
+}
+
+func (h HistoryConfig) enabled() bool {
+	return h.Size > 0 || h.Duration > 0
+}
+
 // PriceStore contains a list of messages.Price's.
 type PriceStore struct {
 	mu sync.RWMutex
 
-	prices map[FeederPrice]*messages.Price
+	prices     map[FeederPrice]*messages.Price
+	history    map[FeederPrice][]*messages.Price
+	historyCfg HistoryConfig
 }
 
 // NewPriceStore creates a new store instance.
 func NewPriceStore() *PriceStore {
+	return NewPriceStoreWithHistory(HistoryConfig{})
+}
+
+// NewPriceStoreWithHistory creates a new store instance which, in addition
+// to the latest prices, keeps the history of prices for each feeder.
+func NewPriceStoreWithHistory(cfg HistoryConfig) *PriceStore {
 	return &PriceStore{
-		prices: make(map[FeederPrice]*messages.Price),
+		prices:     make(map[FeederPrice]*messages.Price),
+		history:    make(map[FeederPrice][]*messages.Price),
+		historyCfg: cfg,
 	}
 }
 
@@ -52,6 +79,10 @@ func (p *PriceStore) Add(from ethereum.Address, msg *messages.Price) {
 		Feeder:    from,
 	}
 
+	if p.historyCfg.enabled() {
+		p.addHistory(fp, msg)
+	}
+
 	if prev, ok := p.prices[fp]; ok && prev.Price.Age.After(msg.Price.Age) {
 		return
 	}
@@ -103,3 +134,86 @@ func (p *PriceStore) Feeder(assetPair string, feeder ethereum.Address) *messages
 
 	return nil
 }
+
+// History returns prices for given asset pair sent by given feeder, with
+// the age in the given time range, sorted from the oldest. Zero from or to
+// times mean that the range is not limited on that side. It returns nil
+// if the history is disabled.
+func (p *PriceStore) History(assetPair string, feeder ethereum.Address, from, to time.Time) []*messages.Price {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	fp := FeederPrice{
+		AssetPair: assetPair,
+		Feeder:    feeder,
+	}
+
+	return timeRange(p.history[fp], from, to)
+}
+
+// AllHistory returns prices from all feeders with the age in the given time
+// range. Prices in the returned map are sorted from the oldest. Zero from or
+// to times mean that the range is not limited on that side.
+func (p *PriceStore) AllHistory(from, to time.Time) map[FeederPrice][]*messages.Price {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	r := map[FeederPrice][]*messages.Price{}
+	for fp, prices := range p.history {
+		if h := timeRange(prices, from, to); len(h) > 0 {
+			r[fp] = h
+		}
+	}
+	return r
+}
+
+// addHistory inserts the price into the history, keeping it sorted by age,
+// and removes prices which exceed the history limits.
+func (p *PriceStore) addHistory(fp FeederPrice, msg *messages.Price) {
+	h := p.history[fp]
+
+	// Find the position of the price. Prices with the same age are
+	// considered duplicates:
+	i := sort.Search(len(h), func(i int) bool {
+		return !h[i].Price.Age.Before(msg.Price.Age)
+	})
+	if i < len(h) && h[i].Price.Age.Equal(msg.Price.Age) {
+		return
+	}
+	h = append(h, nil)
+	copy(h[i+1:], h[i:])
+	h[i] = msg
+
+	// Remove prices exceeding the limits:
+	n := 0
+	if p.historyCfg.Size > 0 && len(h) > p.historyCfg.Size {
+		n = len(h) - p.historyCfg.Size
+	}
+	if p.historyCfg.Duration > 0 {
+		oldest := h[len(h)-1].Price.Age.Add(-p.historyCfg.Duration)
+		for n < len(h) && h[n].Price.Age.Before(oldest) {
+			n++
+		}
+	}
+	if n > 0 {
+		h = append([]*messages.Price(nil), h[n:]...)
+	}
+
+	p.history[fp] = h
+}
+
+// timeRange returns prices from the sorted list with the age between from
+// and to, inclusive.
+func timeRange(prices []*messages.Price, from, to time.Time) []*messages.Price {
+	var r []*messages.Price
+	for _, price := range prices {
+		if !from.IsZero() && price.Price.Age.Before(from) {
+			continue
+		}
+		if !to.IsZero() && price.Price.Age.After(to) {
+			break
+		}
+		r = append(r, price)
+	}
+	return r
+}
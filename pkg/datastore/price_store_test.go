@@ -17,10 +17,12 @@ package datastore
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/makerdao/oracle-suite/pkg/datastore/testutil"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
 )
 
 func TestPriceStore_Add(t *testing.T) {
@@ -73,3 +75,59 @@ func TestPriceStore_Feeder(t *testing.T) {
 	assert.Equal(t, testutil.PriceAAABBB2, ps.Feeder("AAABBB", testutil.Address1))
 	assert.Equal(t, testutil.PriceXXXYYY2, ps.Feeder("XXXYYY", testutil.Address1))
 }
+
+func TestPriceStore_History(t *testing.T) {
+	ps := NewPriceStoreWithHistory(HistoryConfig{Size: 3})
+
+	// Prices are added out of order and one of them is duplicated:
+	ps.Add(testutil.Address1, testutil.PriceAAABBB2)
+	ps.Add(testutil.Address1, testutil.PriceAAABBB1)
+	ps.Add(testutil.Address1, testutil.PriceAAABBB3)
+	ps.Add(testutil.Address1, testutil.PriceAAABBB3)
+	ps.Add(testutil.Address2, testutil.PriceAAABBB1)
+
+	assert.Equal(t,
+		[]*messages.Price{testutil.PriceAAABBB1, testutil.PriceAAABBB2, testutil.PriceAAABBB3},
+		ps.History("AAABBB", testutil.Address1, time.Time{}, time.Time{}),
+	)
+	assert.Equal(t,
+		[]*messages.Price{testutil.PriceAAABBB2},
+		ps.History("AAABBB", testutil.Address1, time.Unix(150, 0), time.Unix(250, 0)),
+	)
+	assert.Equal(t, testutil.PriceAAABBB3, ps.Feeder("AAABBB", testutil.Address1))
+
+	// Oldest price should be removed after exceeding the size limit:
+	ps.Add(testutil.Address1, testutil.PriceAAABBB4)
+	assert.Equal(t,
+		[]*messages.Price{testutil.PriceAAABBB2, testutil.PriceAAABBB3, testutil.PriceAAABBB4},
+		ps.History("AAABBB", testutil.Address1, time.Time{}, time.Time{}),
+	)
+
+	all := ps.AllHistory(time.Time{}, time.Unix(100, 0))
+	assert.Len(t, all, 1)
+	assert.Equal(t, []*messages.Price{testutil.PriceAAABBB1}, all[FeederPrice{AssetPair: "AAABBB", Feeder: testutil.Address2}])
+}
+
+func TestPriceStore_History_Duration(t *testing.T) {
+	ps := NewPriceStoreWithHistory(HistoryConfig{Duration: 150 * time.Second})
+
+	ps.Add(testutil.Address1, testutil.PriceAAABBB1)
+	ps.Add(testutil.Address1, testutil.PriceAAABBB2)
+	ps.Add(testutil.Address1, testutil.PriceAAABBB3)
+
+	// The AAABBB1 price is older than 150 seconds relative to the AAABBB3:
+	assert.Equal(t,
+		[]*messages.Price{testutil.PriceAAABBB2, testutil.PriceAAABBB3},
+		ps.History("AAABBB", testutil.Address1, time.Time{}, time.Time{}),
+	)
+}
+
+func TestPriceStore_History_Disabled(t *testing.T) {
+	ps := NewPriceStore()
+
+	ps.Add(testutil.Address1, testutil.PriceAAABBB1)
+	ps.Add(testutil.Address1, testutil.PriceAAABBB2)
+
+	assert.Nil(t, ps.History("AAABBB", testutil.Address1, time.Time{}, time.Time{}))
+	assert.Empty(t, ps.AllHistory(time.Time{}, time.Time{}))
+}
@@ -0,0 +1,213 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package datastore
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+// Storage persists prices collected by the Datastore, so they are available
+// after a restart.
+type Storage interface {
+	// Add saves the price from the given feeder. If a price from the same
+	// feeder already exists, the newer one is kept.
+	Add(from ethereum.Address, msg *messages.Price) error
+	// Load returns all saved prices. Prices must be validated before use,
+	// because the storage may be modified externally.
+	Load() ([]*messages.Price, error)
+	// Prune removes prices older than the given time.
+	Prune(olderThan time.Time) error
+	// Close closes the storage.
+	Close() error
+}
+
+// FileStorage implements the Storage interface using a local file. Prices
+// are appended to the file as JSON lines and the file is rewritten only
+// when it is pruned, so saving a price is cheap.
+type FileStorage struct {
+	mu sync.Mutex
+
+	path   string
+	file   *os.File
+	prices map[FeederPrice]*messages.Price
+}
+
+type fileStorageRecord struct {
+	From  ethereum.Address `json:"from"`
+	Price *messages.Price  `json:"price"`
+}
+
+// NewFileStorage opens the file storage at the given path. If the file does
+// not exist, it is created.
+func NewFileStorage(path string) (*FileStorage, error) {
+	s := &FileStorage{
+		path:   path,
+		prices: make(map[FeederPrice]*messages.Price),
+	}
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s.file = f
+	return s, nil
+}
+
+// Add implements the Storage interface.
+func (s *FileStorage) Add(from ethereum.Address, msg *messages.Price) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.add(from, msg) {
+		return nil
+	}
+	b, err := json.Marshal(fileStorageRecord{From: from, Price: msg})
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(append(b, '\n'))
+	return err
+}
+
+// Load implements the Storage interface.
+func (s *FileStorage) Load() ([]*messages.Price, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var prices []*messages.Price
+	for _, msg := range s.prices {
+		prices = append(prices, msg)
+	}
+	return prices, nil
+}
+
+// Prune implements the Storage interface. The file is rewritten with only
+// the latest price from each feeder.
+func (s *FileStorage) Prune(olderThan time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for fp, msg := range s.prices {
+		if msg.Price.Age.Before(olderThan) {
+			delete(s.prices, fp)
+		}
+	}
+
+	// Write prices to a temporary file first, so the storage is not lost if
+	// the process is interrupted. The temporary file is opened for appending,
+	// so after it replaces the storage file, it is used for next prices.
+	// The current file is closed only after that, so it remains usable if
+	// pruning fails:
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_APPEND|os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err := s.writeAll(tmp); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	old := s.file
+	s.file = tmp
+	return old.Close()
+}
+
+// writeAll writes all prices to the file and syncs it.
+func (s *FileStorage) writeAll(f *os.File) error {
+	w := bufio.NewWriter(f)
+	for fp, msg := range s.prices {
+		b, err := json.Marshal(fileStorageRecord{From: fp.Feeder, Price: msg})
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// Close implements the Storage interface.
+func (s *FileStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// read reads prices from the file. Malformed lines, which may be left if
+// the process was interrupted while writing, are skipped, but read errors
+// are returned, so a truncated storage is never loaded.
+func (s *FileStorage) read() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var rec fileStorageRecord
+			if json.Unmarshal(line, &rec) == nil && rec.Price != nil && rec.Price.Price != nil {
+				s.add(rec.From, rec.Price)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// add adds the price to the map. It returns false if a newer price from
+// the same feeder already exists.
+func (s *FileStorage) add(from ethereum.Address, msg *messages.Price) bool {
+	fp := FeederPrice{
+		AssetPair: msg.Price.Wat,
+		Feeder:    from,
+	}
+	if prev, ok := s.prices[fp]; ok && prev.Price.Age.After(msg.Price.Age) {
+		return false
+	}
+	s.prices[fp] = msg
+	return true
+}
@@ -0,0 +1,111 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package datastore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/datastore/testutil"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+func TestFileStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.jsonl")
+
+	s, err := NewFileStorage(path)
+	require.NoError(t, err)
+	assert.NoError(t, s.Add(testutil.Address1, testutil.PriceAAABBB1))
+	assert.NoError(t, s.Add(testutil.Address1, testutil.PriceAAABBB2))
+	assert.NoError(t, s.Add(testutil.Address1, testutil.PriceAAABBB1)) // older price must be ignored
+	assert.NoError(t, s.Add(testutil.Address2, testutil.PriceXXXYYY1))
+	assert.NoError(t, s.Close())
+
+	// Reopen the storage and verify if prices are loaded:
+	s, err = NewFileStorage(path)
+	require.NoError(t, err)
+	prices, err := s.Load()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, toOraclePrices(prices), toOraclePrices(
+		[]*messages.Price{testutil.PriceAAABBB2, testutil.PriceXXXYYY1},
+	))
+
+	// Prune prices older than the AAABBB2 price:
+	assert.NoError(t, s.Prune(time.Unix(150, 0)))
+	assert.NoError(t, s.Add(testutil.Address2, testutil.PriceAAABBB3))
+	assert.NoError(t, s.Close())
+
+	s, err = NewFileStorage(path)
+	require.NoError(t, err)
+	defer s.Close()
+	prices, err = s.Load()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, toOraclePrices(prices), toOraclePrices(
+		[]*messages.Price{testutil.PriceAAABBB2, testutil.PriceAAABBB3},
+	))
+}
+
+func TestFileStorage_Malformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.jsonl")
+
+	s, err := NewFileStorage(path)
+	require.NoError(t, err)
+	assert.NoError(t, s.Add(testutil.Address1, testutil.PriceAAABBB1))
+	assert.NoError(t, s.Close())
+
+	// Simulate a write interrupted in the middle of a line:
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"from":"0x2d800d93b065ce011af83f316cef9f0d005b0aa4","pri`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s, err = NewFileStorage(path)
+	require.NoError(t, err)
+	defer s.Close()
+	prices, err := s.Load()
+	require.NoError(t, err)
+	assert.Len(t, prices, 1)
+}
+
+func TestFileStorage_ReadError(t *testing.T) {
+	// Reading a directory fails with an I/O error other than io.EOF:
+	_, err := NewFileStorage(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestFileStorage_PruneError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.jsonl")
+
+	s, err := NewFileStorage(path)
+	require.NoError(t, err)
+	defer s.Close()
+	assert.NoError(t, s.Add(testutil.Address1, testutil.PriceAAABBB1))
+
+	// Replace the storage file with a non-empty directory, so the file
+	// cannot be replaced:
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "dir"), 0700))
+	assert.Error(t, s.Prune(time.Unix(0, 0)))
+
+	// The storage must be still usable:
+	assert.NoError(t, s.Add(testutil.Address2, testutil.PriceAAABBB2))
+}
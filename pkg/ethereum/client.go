@@ -20,15 +20,46 @@ import (
 	"math/big"
 )
 
+// TransactionType is the type of the transaction envelope.
+type TransactionType uint8
+
+const (
+	// LegacyTxType is the pre EIP-2718 transaction with a single gas price.
+	LegacyTxType TransactionType = 0
+	// DynamicFeeTxType is the EIP-1559 transaction with a max fee and
+	// a priority fee.
+	DynamicFeeTxType TransactionType = 2
+)
+
 type Transaction struct {
+	// Type is the transaction type. The default is LegacyTxType.
+	Type TransactionType
 	// Address is the contract's address.
 	Address Address
-	// Nonce is the transaction nonce. If zero, the nonce will be filled
-	// automatically.
+	// Nonce is the transaction nonce. If zero and NonceSet is false,
+	// the nonce will be filled automatically.
 	Nonce uint64
-	// Gas is the gas price. If nil, the suggested gas price will be used.
+	// NonceSet indicates that the Nonce field is set, even if it is zero.
+	// It is set when the nonce is filled automatically, so a copy of
+	// the transaction, for example a replacement, keeps the same nonce.
+	NonceSet bool
+	// Gas is the gas price of the legacy transaction. If nil, the suggested
+	// gas price will be used.
 	Gas *big.Int
+	// MaxFee is the maximum fee per gas of the dynamic fee transaction.
+	// If nil, it will be estimated using the fee history.
+	MaxFee *big.Int
+	// PriorityFee is the maximum priority fee per gas of the dynamic fee
+	// transaction. If nil, it will be estimated using the fee history.
+	PriorityFee *big.Int
+	// MaxFeeCap is the upper limit for the MaxFee field or for the Gas field
+	// in case of legacy transactions. If nil, there is no limit.
+	MaxFeeCap *big.Int
+	// PriorityFeeCap is the upper limit for the PriorityFee field. If nil,
+	// there is no limit.
+	PriorityFeeCap *big.Int
 	// GasLimit is the maximum gas available to be used for this transaction.
+	// If nil, the gas limit will be estimated.
 	GasLimit *big.Int
 	// Data is the raw transaction data.
 	Data []byte
@@ -40,6 +71,42 @@ type Transaction struct {
 	SignedTx interface{}
 }
 
+// Copy returns a copy of the transaction. The SignedTx field is copied
+// as is.
+func (t *Transaction) Copy() *Transaction {
+	c := *t
+	c.Data = make([]byte, len(t.Data))
+	copy(c.Data, t.Data)
+	return &c
+}
+
+// Receipt is the receipt of a mined transaction.
+type Receipt struct {
+	// TxHash is the hash of the transaction.
+	TxHash Hash
+	// BlockNumber is the number of the block in which the transaction
+	// was included.
+	BlockNumber *big.Int
+	// Status is 1 if the transaction succeeded and 0 if it was reverted.
+	Status uint64
+	// GasUsed is the amount of gas used by the transaction.
+	GasUsed uint64
+}
+
+// FeeHistory contains the base fees and the priority fees of a range
+// of blocks, as returned by the eth_feeHistory method.
+type FeeHistory struct {
+	// OldestBlock is the number of the first block in the range.
+	OldestBlock *big.Int
+	// Reward contains the requested priority fee percentiles per block.
+	Reward [][]*big.Int
+	// BaseFee contains the base fees per block, including the base fee of
+	// the next block after the range.
+	BaseFee []*big.Int
+	// GasUsedRatio contains the ratio of used gas to the gas limit per block.
+	GasUsedRatio []float64
+}
+
 type Call struct {
 	// Address is the contract's address.
 	Address Address
@@ -60,4 +127,30 @@ type Client interface {
 	// SendTransaction injects a signed transaction into the pending pool
 	// for execution.
 	SendTransaction(ctx context.Context, transaction *Transaction) (*Hash, error)
+	// FillTransaction fills the optional fields of the transaction, like
+	// the nonce, fees, gas limit and chain ID, in the same way as the
+	// SendTransaction does. The transaction is modified in place.
+	FillTransaction(ctx context.Context, transaction *Transaction) error
+	// TransactionReceipt returns the receipt of the transaction. If
+	// the transaction is not mined yet, nil is returned.
+	TransactionReceipt(ctx context.Context, hash Hash) (*Receipt, error)
+	// GasPrice returns the currently suggested gas price. For networks
+	// with dynamic fees, it includes the base fee and the priority fee.
+	GasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// BlockClient is a Client which can also read the state of the chain at
+// the given block. If the block is nil, the latest block is used.
+type BlockClient interface {
+	Client
+	// BlockNumber returns the number of the latest block.
+	BlockNumber(ctx context.Context) (*big.Int, error)
+	// CallAt works like the Call function but uses the state at the block.
+	CallAt(ctx context.Context, call Call, block *big.Int) ([]byte, error)
+	// MultiCallAt works like the MultiCall function but uses the state at
+	// the block.
+	MultiCallAt(ctx context.Context, calls []Call, block *big.Int) ([][]byte, error)
+	// StorageAt works like the Storage function but uses the state at
+	// the block.
+	StorageAt(ctx context.Context, address Address, key Hash, block *big.Int) ([]byte, error)
 }
@@ -34,6 +34,10 @@ type (
 // HexToAddress returns Address from hex representation.
 var HexToAddress = common.HexToAddress
 
+// IsHexAddress verifies whether a string can represent a valid hex-encoded
+// address or not.
+var IsHexAddress = common.IsHexAddress
+
 // SHA3Hash calculates SHA3 hash.
 func SHA3Hash(b []byte) []byte {
 	return crypto.Keccak256Hash(b).Bytes()
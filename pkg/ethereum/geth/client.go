@@ -22,14 +22,18 @@ import (
 	"fmt"
 	"math/big"
 	"regexp"
+	"sort"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 
 	pkgEthereum "github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/health"
 )
 
 const (
@@ -41,6 +45,29 @@ const (
 	xdaiChainID    = 100
 )
 
+// DefaultGasLimitMultiplier is the default multiplier applied to
+// the estimated gas limit.
+const DefaultGasLimitMultiplier = 1.25
+
+// Parameters used to estimate dynamic fees using the eth_feeHistory method.
+// The priority fee is the median of the rewardPercentile reward over
+// feeHistoryBlocks blocks and the max fee is twice the next block base fee
+// plus the priority fee, which keeps the transaction valid for at least
+// six consecutive full blocks.
+const (
+	feeHistoryBlocks           = 10
+	feeHistoryRewardPercentile = 50
+	baseFeeMultiplier          = 2
+)
+
+// defaultPriorityFee is used when the fee history does not contain
+// any rewards.
+var defaultPriorityFee = big.NewInt(1e9)
+
+// healthCheckTimeout is the maximum time to wait for the Ethereum node
+// response during the health check.
+const healthCheckTimeout = 5 * time.Second
+
 // Addresses of multicall contracts. They're used to implement
 // the Client.MultiCall function.
 //
@@ -56,6 +83,7 @@ var multiCallContracts = map[uint64]common.Address{
 
 var ErrMulticallNotSupported = errors.New("multicall is not supported on current chain")
 var ErrInvalidSignedTxType = errors.New("unable to send transaction, SignedTx field have invalid type")
+var ErrEmptyFeeHistory = errors.New("unable to estimate fees, fee history is empty")
 
 // ErrRevert may be returned by Client.Call method in case of EVM revert.
 type ErrRevert struct {
@@ -79,24 +107,80 @@ type EthClient interface {
 	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
 	SuggestGasPrice(ctx context.Context) (*big.Int, error)
 	NetworkID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*pkgEthereum.FeeHistory, error)
+	SendRawTransaction(ctx context.Context, tx []byte) error
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
 }
 
 // Client implements the ethereum.Client interface.
 type Client struct {
-	ethClient EthClient
-	signer    pkgEthereum.Signer
+	ethClient          EthClient
+	signer             pkgEthereum.Signer
+	gasLimitMultiplier float64
+}
+
+// ClientOptions contains optional parameters for the Client.
+type ClientOptions struct {
+	// GasLimitMultiplier is the multiplier applied to the gas limit
+	// estimated with the eth_estimateGas method. If zero,
+	// the DefaultGasLimitMultiplier is used.
+	GasLimitMultiplier float64
 }
 
 // NewClient returns a new Client instance.
 func NewClient(ethClient EthClient, signer pkgEthereum.Signer) *Client {
+	return NewClientWithOptions(ethClient, signer, ClientOptions{})
+}
+
+// NewClientWithOptions returns a new Client instance with the given options.
+func NewClientWithOptions(ethClient EthClient, signer pkgEthereum.Signer, opts ClientOptions) *Client {
+	if opts.GasLimitMultiplier == 0 {
+		opts.GasLimitMultiplier = DefaultGasLimitMultiplier
+	}
 	return &Client{
-		ethClient: ethClient,
-		signer:    signer,
+		ethClient:          ethClient,
+		signer:             signer,
+		gasLimitMultiplier: opts.GasLimitMultiplier,
+	}
+}
+
+// HealthStatus implements the health.Checker interface. The client is ready
+// if the Ethereum node responds to requests.
+func (e *Client) HealthStatus() health.Status {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	block, err := e.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return health.Status{
+			Ready:   false,
+			Details: map[string]interface{}{"error": err.Error()},
+		}
+	}
+	return health.Status{
+		Ready:   true,
+		Details: map[string]interface{}{"blockNumber": block},
 	}
 }
 
+// BlockNumber implements the ethereum.BlockClient interface.
+func (e *Client) BlockNumber(ctx context.Context) (*big.Int, error) {
+	n, err := e.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(n), nil
+}
+
 // Call implements the ethereum.Client interface.
 func (e *Client) Call(ctx context.Context, call pkgEthereum.Call) ([]byte, error) {
+	return e.CallAt(ctx, call, nil)
+}
+
+// CallAt implements the ethereum.BlockClient interface.
+func (e *Client) CallAt(ctx context.Context, call pkgEthereum.Call, block *big.Int) ([]byte, error) {
 	cm := ethereum.CallMsg{
 		From:     e.signer.Address(),
 		To:       &call.Address,
@@ -106,7 +190,7 @@ func (e *Client) Call(ctx context.Context, call pkgEthereum.Call) ([]byte, error
 		Data:     call.Data,
 	}
 
-	resp, err := e.ethClient.CallContract(ctx, cm, nil)
+	resp, err := e.ethClient.CallContract(ctx, cm, block)
 	if err := isRevertErr(err); err != nil {
 		return nil, err
 	}
@@ -122,6 +206,11 @@ func (e *Client) Call(ctx context.Context, call pkgEthereum.Call) ([]byte, error
 
 // MultiCall implements the ethereum.Client interface.
 func (e *Client) MultiCall(ctx context.Context, calls []pkgEthereum.Call) ([][]byte, error) {
+	return e.MultiCallAt(ctx, calls, nil)
+}
+
+// MultiCallAt implements the ethereum.BlockClient interface.
+func (e *Client) MultiCallAt(ctx context.Context, calls []pkgEthereum.Call, block *big.Int) ([][]byte, error) {
 	type abiCall struct {
 		Address common.Address `abi:"target"`
 		Data    []byte         `abi:"callData"`
@@ -146,7 +235,7 @@ func (e *Client) MultiCall(ctx context.Context, calls []pkgEthereum.Call) ([][]b
 	if err != nil {
 		return nil, err
 	}
-	response, err := e.Call(ctx, pkgEthereum.Call{Address: multicallAddr, Data: callData})
+	response, err := e.CallAt(ctx, pkgEthereum.Call{Address: multicallAddr, Data: callData}, block)
 	if err != nil {
 		return nil, err
 	}
@@ -160,58 +249,184 @@ func (e *Client) MultiCall(ctx context.Context, calls []pkgEthereum.Call) ([][]b
 
 // Storage implements the ethereum.Client interface.
 func (e *Client) Storage(ctx context.Context, address pkgEthereum.Address, key pkgEthereum.Hash) ([]byte, error) {
-	return e.ethClient.StorageAt(ctx, address, key, nil)
+	return e.StorageAt(ctx, address, key, nil)
+}
+
+// StorageAt implements the ethereum.BlockClient interface.
+func (e *Client) StorageAt(
+	ctx context.Context,
+	address pkgEthereum.Address,
+	key pkgEthereum.Hash,
+	block *big.Int,
+) ([]byte, error) {
+
+	return e.ethClient.StorageAt(ctx, address, key, block)
 }
 
 // SendTransaction implements the ethereum.Client interface.
 func (e *Client) SendTransaction(ctx context.Context, transaction *pkgEthereum.Transaction) (*pkgEthereum.Hash, error) {
-	var err error
-
 	// We don't want to modify passed structure because that would be rude, so
 	// we copy it here:
-	tx := &pkgEthereum.Transaction{
-		Address:  transaction.Address,
-		Nonce:    transaction.Nonce,
-		Gas:      transaction.Gas,
-		GasLimit: transaction.GasLimit,
-		ChainID:  transaction.ChainID,
-		SignedTx: transaction.SignedTx,
-	}
-	tx.Data = make([]byte, len(transaction.Data))
-	copy(tx.Data, transaction.Data)
+	tx := transaction.Copy()
 
 	// Fill optional values if necessary:
-	if tx.Nonce == 0 {
-		tx.Nonce, err = e.ethClient.PendingNonceAt(ctx, e.signer.Address())
-		if err != nil {
+	if err := e.FillTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	if tx.SignedTx == nil {
+		if err := e.signer.SignTransaction(tx); err != nil {
 			return nil, err
 		}
 	}
-	if tx.Gas == nil {
-		tx.Gas, err = e.ethClient.SuggestGasPrice(ctx)
+
+	// Send transaction:
+	switch stx := tx.SignedTx.(type) {
+	case *types.Transaction:
+		hash := stx.Hash()
+		return &hash, e.ethClient.SendTransaction(ctx, stx)
+	case *DynamicFeeTx:
+		raw, err := stx.MarshalBinary()
 		if err != nil {
 			return nil, err
 		}
+		hash := crypto.Keccak256Hash(raw)
+		return &hash, e.ethClient.SendRawTransaction(ctx, raw)
+	}
+	return nil, ErrInvalidSignedTxType
+}
+
+// FillTransaction implements the ethereum.Client interface.
+func (e *Client) FillTransaction(ctx context.Context, tx *pkgEthereum.Transaction) error {
+	var err error
+	if tx.Nonce == 0 && !tx.NonceSet {
+		tx.Nonce, err = e.ethClient.PendingNonceAt(ctx, e.signer.Address())
+		if err != nil {
+			return err
+		}
+	}
+	tx.NonceSet = true
+	if tx.Type == pkgEthereum.DynamicFeeTxType {
+		if tx.MaxFee == nil || tx.PriorityFee == nil {
+			maxFee, priorityFee, err := e.suggestDynamicFees(ctx)
+			if err != nil {
+				return err
+			}
+			if tx.MaxFee == nil {
+				tx.MaxFee = maxFee
+			}
+			if tx.PriorityFee == nil {
+				tx.PriorityFee = priorityFee
+			}
+		}
+		tx.MaxFee = capValue(tx.MaxFee, tx.MaxFeeCap)
+		tx.PriorityFee = capValue(tx.PriorityFee, tx.PriorityFeeCap)
+		// The priority fee is included in the max fee, so it cannot be higher:
+		tx.PriorityFee = capValue(tx.PriorityFee, tx.MaxFee)
+	} else {
+		if tx.Gas == nil {
+			tx.Gas, err = e.ethClient.SuggestGasPrice(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		tx.Gas = capValue(tx.Gas, tx.MaxFeeCap)
+	}
+	if tx.GasLimit == nil {
+		tx.GasLimit, err = e.estimateGasLimit(ctx, tx)
+		if err != nil {
+			return err
+		}
 	}
 	if tx.ChainID == nil {
 		tx.ChainID, err = e.ethClient.NetworkID(ctx)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
-	if tx.SignedTx == nil {
-		err = e.signer.SignTransaction(tx)
-		if err != nil {
-			return nil, err
+	return nil
+}
+
+// TransactionReceipt implements the ethereum.Client interface.
+func (e *Client) TransactionReceipt(ctx context.Context, hash pkgEthereum.Hash) (*pkgEthereum.Receipt, error) {
+	r, err := e.ethClient.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pkgEthereum.Receipt{
+		TxHash:      r.TxHash,
+		BlockNumber: r.BlockNumber,
+		Status:      r.Status,
+		GasUsed:     r.GasUsed,
+	}, nil
+}
+
+// GasPrice implements the ethereum.Client interface.
+func (e *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	return e.ethClient.SuggestGasPrice(ctx)
+}
+
+// suggestDynamicFees estimates the max fee and the priority fee using
+// the eth_feeHistory method.
+func (e *Client) suggestDynamicFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	fh, err := e.ethClient.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{feeHistoryRewardPercentile})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(fh.BaseFee) == 0 {
+		return nil, nil, ErrEmptyFeeHistory
+	}
+
+	// The last base fee in the history is the base fee of the next block:
+	baseFee := fh.BaseFee[len(fh.BaseFee)-1]
+
+	var rewards []*big.Int
+	for _, r := range fh.Reward {
+		if len(r) > 0 && r[0] != nil {
+			rewards = append(rewards, r[0])
 		}
 	}
+	priorityFee := defaultPriorityFee
+	if len(rewards) > 0 {
+		sort.Slice(rewards, func(i, j int) bool {
+			return rewards[i].Cmp(rewards[j]) < 0
+		})
+		priorityFee = rewards[len(rewards)/2]
+	}
 
-	// Send transaction:
-	if stx, ok := tx.SignedTx.(*types.Transaction); ok {
-		hash := stx.Hash()
-		return &hash, e.ethClient.SendTransaction(ctx, stx)
+	maxFee := new(big.Int).Mul(baseFee, big.NewInt(baseFeeMultiplier))
+	maxFee.Add(maxFee, priorityFee)
+
+	return maxFee, new(big.Int).Set(priorityFee), nil
+}
+
+// estimateGasLimit estimates the gas limit using the eth_estimateGas method
+// and multiplies it by the gas limit multiplier.
+func (e *Client) estimateGasLimit(ctx context.Context, tx *pkgEthereum.Transaction) (*big.Int, error) {
+	gas, err := e.ethClient.EstimateGas(ctx, ethereum.CallMsg{
+		From: e.signer.Address(),
+		To:   &tx.Address,
+		Data: tx.Data,
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil, ErrInvalidSignedTxType
+	limit, _ := new(big.Float).Mul(
+		new(big.Float).SetUint64(gas),
+		big.NewFloat(e.gasLimitMultiplier),
+	).Int(nil)
+	return limit, nil
+}
+
+// capValue returns the limit if v is greater than the limit. If the limit
+// is nil, v is returned.
+func capValue(v, limit *big.Int) *big.Int {
+	if limit != nil && v != nil && v.Cmp(limit) > 0 {
+		return limit
+	}
+	return v
 }
 
 func isRevertResp(resp []byte) error {
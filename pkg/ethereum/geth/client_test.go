@@ -18,17 +18,22 @@ package geth
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	pkgEthereum "github.com/makerdao/oracle-suite/pkg/ethereum"
 	"github.com/makerdao/oracle-suite/pkg/ethereum/geth/mocks"
+	ethereumMocks "github.com/makerdao/oracle-suite/pkg/ethereum/mocks"
 )
 
 var clientContractAddress = common.HexToAddress("0x0E30F0FC91FDbc4594b1e2E5d64E6F1f94cAB23D")
@@ -213,6 +218,34 @@ func TestClient_SendTransaction(t *testing.T) {
 	assert.Equal(t, stx.Data(), clientCallData)
 }
 
+func TestClient_SendTransaction_ZeroNonce(t *testing.T) {
+	account, _ := NewAccount("./testdata/keystore", "test123", clientAddress)
+	ethClient := &mocks.EthClient{}
+	client := NewClient(ethClient, NewSigner(account))
+
+	// The PendingNonceAt method must not be called if the nonce is set:
+	ethClient.On(
+		"SendTransaction",
+		mock.Anything,
+		mock.Anything,
+	).Return(nil)
+
+	tx := &pkgEthereum.Transaction{
+		Address:  clientContractAddress,
+		Nonce:    0,
+		NonceSet: true,
+		Gas:      big.NewInt(100),
+		GasLimit: big.NewInt(1000),
+		Data:     clientCallData,
+		ChainID:  big.NewInt(mainnetChainID),
+	}
+
+	_, err := client.SendTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	stx := ethClient.Calls[0].Arguments.Get(1).(*types.Transaction)
+	assert.Equal(t, uint64(0), stx.Nonce())
+}
+
 func TestClient_SendTransaction_Minimal(t *testing.T) {
 	account, _ := NewAccount("./testdata/keystore", "test123", clientAddress)
 	ethClient := &mocks.EthClient{}
@@ -258,3 +291,161 @@ func TestClient_SendTransaction_Minimal(t *testing.T) {
 	assert.Equal(t, stx.Gas(), uint64(1000))
 	assert.Equal(t, stx.ChainId(), big.NewInt(mainnetChainID))
 }
+
+func TestClient_SendTransaction_DynamicFee(t *testing.T) {
+	account, _ := NewAccount("./testdata/keystore", "test123", clientAddress)
+	ethClient := &mocks.EthClient{}
+	client := NewClientWithOptions(ethClient, NewSigner(account), ClientOptions{GasLimitMultiplier: 1.5})
+
+	ethClient.On(
+		"PendingNonceAt",
+		mock.Anything,
+		clientAddress,
+	).Return(10, nil)
+
+	ethClient.On(
+		"FeeHistory",
+		mock.Anything,
+		uint64(feeHistoryBlocks),
+		(*big.Int)(nil),
+		[]float64{feeHistoryRewardPercentile},
+	).Return(&pkgEthereum.FeeHistory{
+		Reward:  [][]*big.Int{{big.NewInt(3)}, {big.NewInt(1)}, {big.NewInt(2)}},
+		BaseFee: []*big.Int{big.NewInt(90), big.NewInt(95), big.NewInt(100), big.NewInt(110)},
+	}, nil)
+
+	ethClient.On(
+		"EstimateGas",
+		mock.Anything,
+		ethereum.CallMsg{From: clientAddress, To: &clientContractAddress, Data: clientCallData},
+	).Return(1000, nil)
+
+	ethClient.On(
+		"SendRawTransaction",
+		mock.Anything,
+		mock.Anything,
+	).Return(nil)
+
+	tx := &pkgEthereum.Transaction{
+		Type:     pkgEthereum.DynamicFeeTxType,
+		Address:  clientContractAddress,
+		Data:     clientCallData,
+		ChainID:  big.NewInt(mainnetChainID),
+		SignedTx: nil,
+	}
+
+	hash, err := client.SendTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	raw := ethClient.Calls[3].Arguments.Get(1).([]byte)
+	assert.Equal(t, crypto.Keccak256Hash(raw), *hash)
+
+	// Decode and verify the sent transaction:
+	require.Equal(t, byte(0x02), raw[0])
+	stx := &DynamicFeeTx{}
+	require.NoError(t, rlp.DecodeBytes(raw[1:], stx))
+	assert.Equal(t, big.NewInt(mainnetChainID), stx.ChainID)
+	assert.Equal(t, uint64(10), stx.Nonce)
+	assert.Equal(t, big.NewInt(2), stx.GasTipCap)   // median of rewards
+	assert.Equal(t, big.NewInt(222), stx.GasFeeCap) // 2 * 110 + 2
+	assert.Equal(t, uint64(1500), stx.Gas)
+	assert.Equal(t, clientContractAddress, *stx.To)
+	assert.Equal(t, clientCallData, stx.Data)
+
+	// Verify the signature:
+	sigHash, err := stx.SigningHash()
+	require.NoError(t, err)
+	sig := append(append(common.LeftPadBytes(stx.R.Bytes(), 32), common.LeftPadBytes(stx.S.Bytes(), 32)...), byte(stx.V.Uint64()))
+	pub, err := crypto.SigToPub(sigHash.Bytes(), sig)
+	require.NoError(t, err)
+	assert.Equal(t, clientAddress, crypto.PubkeyToAddress(*pub))
+}
+
+func TestClient_SendTransaction_FeeCaps(t *testing.T) {
+	account, _ := NewAccount("./testdata/keystore", "test123", clientAddress)
+	ethClient := &mocks.EthClient{}
+	signer := &ethereumMocks.Signer{}
+	client := NewClient(ethClient, signer)
+
+	ethClient.On("FeeHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&pkgEthereum.FeeHistory{
+		Reward:  [][]*big.Int{{big.NewInt(50)}},
+		BaseFee: []*big.Int{big.NewInt(100)},
+	}, nil)
+	ethClient.On("SuggestGasPrice", mock.Anything).Return(big.NewInt(300), nil)
+	signer.On("SignTransaction", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		_ = NewSigner(account).SignTransaction(args.Get(0).(*pkgEthereum.Transaction))
+	})
+	ethClient.On("SendRawTransaction", mock.Anything, mock.Anything).Return(nil)
+	ethClient.On("SendTransaction", mock.Anything, mock.Anything).Return(nil)
+
+	tests := []struct {
+		name           string
+		tx             *pkgEthereum.Transaction
+		maxFee         *big.Int
+		priorityFee    *big.Int
+		legacyGasPrice *big.Int
+	}{
+		{
+			name:        "estimated-below-caps",
+			tx:          &pkgEthereum.Transaction{Type: pkgEthereum.DynamicFeeTxType, MaxFeeCap: big.NewInt(1000)},
+			maxFee:      big.NewInt(250),
+			priorityFee: big.NewInt(50),
+		},
+		{
+			name:        "max-fee-capped",
+			tx:          &pkgEthereum.Transaction{Type: pkgEthereum.DynamicFeeTxType, MaxFeeCap: big.NewInt(200)},
+			maxFee:      big.NewInt(200),
+			priorityFee: big.NewInt(50),
+		},
+		{
+			name:        "priority-fee-capped",
+			tx:          &pkgEthereum.Transaction{Type: pkgEthereum.DynamicFeeTxType, PriorityFeeCap: big.NewInt(10)},
+			maxFee:      big.NewInt(250),
+			priorityFee: big.NewInt(10),
+		},
+		{
+			name:        "priority-fee-above-max-fee",
+			tx:          &pkgEthereum.Transaction{Type: pkgEthereum.DynamicFeeTxType, MaxFee: big.NewInt(20)},
+			maxFee:      big.NewInt(20),
+			priorityFee: big.NewInt(20),
+		},
+		{
+			name:           "legacy-capped",
+			tx:             &pkgEthereum.Transaction{MaxFeeCap: big.NewInt(200)},
+			legacyGasPrice: big.NewInt(200),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer.Calls = nil
+			tt.tx.Nonce = 1
+			tt.tx.ChainID = big.NewInt(mainnetChainID)
+			tt.tx.GasLimit = big.NewInt(1000)
+
+			_, err := client.SendTransaction(context.Background(), tt.tx)
+			require.NoError(t, err)
+
+			stx := signer.Calls[0].Arguments.Get(0).(*pkgEthereum.Transaction)
+			if tt.legacyGasPrice != nil {
+				assert.Equal(t, tt.legacyGasPrice, stx.Gas)
+				return
+			}
+			assert.Equal(t, tt.maxFee, stx.MaxFee)
+			assert.Equal(t, tt.priorityFee, stx.PriorityFee)
+		})
+	}
+}
+
+func TestClient_HealthStatus(t *testing.T) {
+	ethClient := &mocks.EthClient{}
+	client := NewClient(ethClient, nil)
+
+	ethClient.On("BlockNumber", mock.Anything).Return(42, nil).Once()
+	s := client.HealthStatus()
+	assert.True(t, s.Ready)
+	assert.Equal(t, uint64(42), s.Details["blockNumber"])
+
+	ethClient.On("BlockNumber", mock.Anything).Return(0, errors.New("connection refused")).Once()
+	s = client.HealthStatus()
+	assert.False(t, s.Ready)
+	assert.Equal(t, "connection refused", s.Details["error"])
+}
@@ -0,0 +1,130 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// dynamicFeeTxType is the EIP-2718 type byte of the EIP-1559 transaction.
+const dynamicFeeTxType = 0x02
+
+// DynamicFeeTx is the EIP-1559 transaction. The go-ethereum version used
+// by this project does not support typed transactions yet, so they are
+// encoded here. Transactions created by the Signer are stored in the
+// ethereum.Transaction.SignedTx field as *DynamicFeeTx.
+type DynamicFeeTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         *common.Address
+	Value      *big.Int
+	Data       []byte
+	AccessList []AccessTuple
+
+	// Signature values, V is the y parity (0 or 1):
+	V *big.Int
+	R *big.Int
+	S *big.Int
+}
+
+// AccessTuple is the element of the EIP-2930 access list.
+type AccessTuple struct {
+	Address     common.Address
+	StorageKeys []common.Hash
+}
+
+// SigningPayload returns the data which has to be signed. The signature
+// is calculated over the Keccak256 hash of the payload.
+func (tx *DynamicFeeTx) SigningPayload() ([]byte, error) {
+	b, err := rlp.EncodeToBytes([]interface{}{
+		tx.ChainID,
+		tx.Nonce,
+		tx.GasTipCap,
+		tx.GasFeeCap,
+		tx.Gas,
+		tx.To,
+		tx.Value,
+		tx.Data,
+		tx.accessList(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{dynamicFeeTxType}, b...), nil
+}
+
+// SigningHash returns the hash which has to be signed.
+func (tx *DynamicFeeTx) SigningHash() (common.Hash, error) {
+	p, err := tx.SigningPayload()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(p), nil
+}
+
+// MarshalBinary returns the signed transaction in the format expected by
+// the eth_sendRawTransaction method.
+func (tx *DynamicFeeTx) MarshalBinary() ([]byte, error) {
+	b, err := rlp.EncodeToBytes([]interface{}{
+		tx.ChainID,
+		tx.Nonce,
+		tx.GasTipCap,
+		tx.GasFeeCap,
+		tx.Gas,
+		tx.To,
+		tx.Value,
+		tx.Data,
+		tx.accessList(),
+		tx.V,
+		tx.R,
+		tx.S,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{dynamicFeeTxType}, b...), nil
+}
+
+// Hash returns the transaction hash.
+func (tx *DynamicFeeTx) Hash() (common.Hash, error) {
+	b, err := tx.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(b), nil
+}
+
+// setSignature sets the V, R and S values from the signature in
+// the [R || S || V] format, where V is 0 or 1.
+func (tx *DynamicFeeTx) setSignature(sig []byte) {
+	tx.R = new(big.Int).SetBytes(sig[:32])
+	tx.S = new(big.Int).SetBytes(sig[32:64])
+	tx.V = new(big.Int).SetBytes(sig[64:])
+}
+
+func (tx *DynamicFeeTx) accessList() []AccessTuple {
+	if tx.AccessList == nil {
+		return []AccessTuple{}
+	}
+	return tx.AccessList
+}
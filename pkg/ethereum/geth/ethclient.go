@@ -0,0 +1,99 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	pkgEthereum "github.com/makerdao/oracle-suite/pkg/ethereum"
+)
+
+// RPCEthClient extends the ethclient.Client with the methods which are
+// not available in the go-ethereum version used by this project.
+type RPCEthClient struct {
+	*ethclient.Client
+	rpc *rpc.Client
+}
+
+// DialEthClient connects to the Ethereum node at the given URL.
+func DialEthClient(url string) (*RPCEthClient, error) {
+	c, err := rpc.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCEthClient(c), nil
+}
+
+// NewRPCEthClient returns a new RPCEthClient instance which uses the given
+// RPC client.
+func NewRPCEthClient(c *rpc.Client) *RPCEthClient {
+	return &RPCEthClient{
+		Client: ethclient.NewClient(c),
+		rpc:    c,
+	}
+}
+
+// FeeHistory returns the base fees and the priority fees for the given
+// reward percentiles of blockCount blocks up to the lastBlock. If lastBlock
+// is nil, the latest block is used.
+func (c *RPCEthClient) FeeHistory(
+	ctx context.Context,
+	blockCount uint64,
+	lastBlock *big.Int,
+	rewardPercentiles []float64,
+) (*pkgEthereum.FeeHistory, error) {
+
+	var res struct {
+		OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+		Reward       [][]*hexutil.Big `json:"reward"`
+		BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+		GasUsedRatio []float64        `json:"gasUsedRatio"`
+	}
+	block := "latest"
+	if lastBlock != nil {
+		block = hexutil.EncodeBig(lastBlock)
+	}
+	err := c.rpc.CallContext(ctx, &res, "eth_feeHistory", hexutil.Uint64(blockCount), block, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	fh := &pkgEthereum.FeeHistory{
+		OldestBlock:  (*big.Int)(res.OldestBlock),
+		Reward:       make([][]*big.Int, len(res.Reward)),
+		BaseFee:      make([]*big.Int, len(res.BaseFee)),
+		GasUsedRatio: res.GasUsedRatio,
+	}
+	for i, r := range res.Reward {
+		fh.Reward[i] = make([]*big.Int, len(r))
+		for j, v := range r {
+			fh.Reward[i][j] = (*big.Int)(v)
+		}
+	}
+	for i, v := range res.BaseFee {
+		fh.BaseFee[i] = (*big.Int)(v)
+	}
+	return fh, nil
+}
+
+// SendRawTransaction sends the already encoded and signed transaction.
+func (c *RPCEthClient) SendRawTransaction(ctx context.Context, tx []byte) error {
+	return c.rpc.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(tx))
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/mock"
+
+	pkgEthereum "github.com/makerdao/oracle-suite/pkg/ethereum"
 )
 
 type EthClient struct {
@@ -58,3 +60,28 @@ func (e *EthClient) NetworkID(ctx context.Context) (*big.Int, error) {
 	args := e.Called(ctx)
 	return args.Get(0).(*big.Int), args.Error(1)
 }
+
+func (e *EthClient) BlockNumber(ctx context.Context) (uint64, error) {
+	args := e.Called(ctx)
+	return uint64(args.Int(0)), args.Error(1)
+}
+
+func (e *EthClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	args := e.Called(ctx, call)
+	return uint64(args.Int(0)), args.Error(1)
+}
+
+func (e *EthClient) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*pkgEthereum.FeeHistory, error) {
+	args := e.Called(ctx, blockCount, lastBlock, rewardPercentiles)
+	return args.Get(0).(*pkgEthereum.FeeHistory), args.Error(1)
+}
+
+func (e *EthClient) SendRawTransaction(ctx context.Context, tx []byte) error {
+	args := e.Called(ctx, tx)
+	return args.Error(0)
+}
+
+func (e *EthClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	args := e.Called(ctx, hash)
+	return args.Get(0).(*types.Receipt), args.Error(1)
+}
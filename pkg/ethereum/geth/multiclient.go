@@ -0,0 +1,500 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
+	"time"
+
+	pkgEthereum "github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/health"
+	"github.com/makerdao/oracle-suite/pkg/log"
+)
+
+const MultiClientLoggerTag = "ETHEREUM"
+
+// DefaultHealthCheckInterval is the default interval between health checks
+// of endpoints used by the MultiClient.
+const DefaultHealthCheckInterval = 30 * time.Second
+
+var ErrNoEndpoints = errors.New("no Ethereum endpoints are configured")
+var ErrAllEndpointsFailed = errors.New("all Ethereum endpoints failed")
+var ErrQuorumNotReached = errors.New("unable to reach a quorum of Ethereum endpoints")
+var ErrBlockReadsNotSupported = errors.New("the quorum requires endpoints which support reads at a given block")
+
+// Endpoint is a single Ethereum client used by the MultiClient.
+type Endpoint struct {
+	// Name identifies the endpoint in logs and health details. It should
+	// not contain any secrets like API keys.
+	Name string
+	// Client is the client connected to the endpoint.
+	Client pkgEthereum.Client
+}
+
+// MultiClientConfig is the configuration for the MultiClient.
+type MultiClientConfig struct {
+	// Endpoints is the list of clients connected to different endpoints.
+	// Endpoints are used in the given order.
+	Endpoints []Endpoint
+	// Signer is used to sign transactions before broadcasting them to all
+	// endpoints.
+	Signer pkgEthereum.Signer
+	// Quorum is the number of endpoints which have to return the same
+	// result for the Call, MultiCall and Storage methods. If less than two,
+	// the result of the first working endpoint is used. Otherwise, all
+	// clients must implement the ethereum.BlockClient interface, because
+	// endpoints are compared at the same block.
+	Quorum int
+	// HealthCheckInterval is the interval between health checks of
+	// endpoints. If zero, the DefaultHealthCheckInterval is used.
+	HealthCheckInterval time.Duration
+	// Logger is a current logger interface used by the MultiClient.
+	Logger log.Logger
+}
+
+// MultiClient implements the ethereum.Client interface using multiple
+// endpoints. Requests are sent to healthy endpoints first and the next
+// endpoint is used if the previous one fails. Reads may require a quorum
+// of endpoints to agree on the result. Transactions are signed once and
+// broadcast to all endpoints.
+type MultiClient struct {
+	mu        sync.RWMutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	endpoints []*endpoint
+	signer    pkgEthereum.Signer
+	quorum    int
+	interval  time.Duration
+	log       log.Logger
+}
+
+type endpoint struct {
+	name    string
+	client  pkgEthereum.Client
+	healthy bool
+	lastErr error
+}
+
+type readResult struct {
+	endpoint *endpoint
+	value    interface{}
+	err      error
+}
+
+// NewMultiClient returns a new MultiClient instance.
+func NewMultiClient(cfg MultiClientConfig) (*MultiClient, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	if cfg.Quorum >= 2 {
+		for _, e := range cfg.Endpoints {
+			if _, ok := e.Client.(pkgEthereum.BlockClient); !ok {
+				return nil, fmt.Errorf("%v: %s", ErrBlockReadsNotSupported, e.Name)
+			}
+		}
+	}
+	if cfg.HealthCheckInterval == 0 {
+		cfg.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &MultiClient{
+		ctx:      ctx,
+		cancel:   cancel,
+		signer:   cfg.Signer,
+		quorum:   cfg.Quorum,
+		interval: cfg.HealthCheckInterval,
+		log:      cfg.Logger.WithField("tag", MultiClientLoggerTag),
+	}
+	for _, e := range cfg.Endpoints {
+		m.endpoints = append(m.endpoints, &endpoint{
+			name:    e.Name,
+			client:  e.Client,
+			healthy: true,
+		})
+	}
+	return m, nil
+}
+
+// Start starts the loop which periodically checks the health of endpoints.
+// Only endpoints which implement the health.Checker interface are checked.
+func (m *MultiClient) Start() error {
+	m.log.Info("Starting")
+	go m.healthCheckLoop()
+	return nil
+}
+
+// Stop stops the health check loop.
+func (m *MultiClient) Stop() error {
+	defer m.log.Info("Stopped")
+	m.cancel()
+	return nil
+}
+
+// HealthStatus implements the health.Checker interface. The client is ready
+// if enough endpoints are healthy to reach a quorum.
+func (m *MultiClient) HealthStatus() health.Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	healthy := 0
+	endpoints := make(map[string]interface{}, len(m.endpoints))
+	for _, e := range m.endpoints {
+		s := map[string]interface{}{"ready": e.healthy}
+		if e.lastErr != nil {
+			s["error"] = e.lastErr.Error()
+		}
+		endpoints[e.name] = s
+		if e.healthy {
+			healthy++
+		}
+	}
+	return health.Status{
+		Ready:   healthy > 0 && healthy >= m.quorum,
+		Details: map[string]interface{}{"endpoints": endpoints},
+	}
+}
+
+// Call implements the ethereum.Client interface.
+func (m *MultiClient) Call(ctx context.Context, call pkgEthereum.Call) ([]byte, error) {
+	v, err := m.read(
+		ctx,
+		func(c pkgEthereum.Client) (interface{}, error) {
+			return c.Call(ctx, call)
+		},
+		func(c pkgEthereum.BlockClient, block *big.Int) (interface{}, error) {
+			return c.CallAt(ctx, call, block)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// MultiCall implements the ethereum.Client interface.
+func (m *MultiClient) MultiCall(ctx context.Context, calls []pkgEthereum.Call) ([][]byte, error) {
+	v, err := m.read(
+		ctx,
+		func(c pkgEthereum.Client) (interface{}, error) {
+			return c.MultiCall(ctx, calls)
+		},
+		func(c pkgEthereum.BlockClient, block *big.Int) (interface{}, error) {
+			return c.MultiCallAt(ctx, calls, block)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return v.([][]byte), nil
+}
+
+// Storage implements the ethereum.Client interface.
+func (m *MultiClient) Storage(ctx context.Context, address pkgEthereum.Address, key pkgEthereum.Hash) ([]byte, error) {
+	v, err := m.read(
+		ctx,
+		func(c pkgEthereum.Client) (interface{}, error) {
+			return c.Storage(ctx, address, key)
+		},
+		func(c pkgEthereum.BlockClient, block *big.Int) (interface{}, error) {
+			return c.StorageAt(ctx, address, key, block)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// FillTransaction implements the ethereum.Client interface.
+func (m *MultiClient) FillTransaction(ctx context.Context, transaction *pkgEthereum.Transaction) error {
+	_, err := m.failover(func(c pkgEthereum.Client) (interface{}, error) {
+		// Use a copy, so a failed endpoint cannot leave the transaction
+		// partially filled:
+		tx := transaction.Copy()
+		if err := c.FillTransaction(ctx, tx); err != nil {
+			return nil, err
+		}
+		*transaction = *tx
+		return nil, nil
+	})
+	return err
+}
+
+// TransactionReceipt implements the ethereum.Client interface. Because
+// endpoints may be out of sync, the receipt is searched on all endpoints
+// until it is found.
+func (m *MultiClient) TransactionReceipt(ctx context.Context, hash pkgEthereum.Hash) (*pkgEthereum.Receipt, error) {
+	var lastErr error
+	for _, e := range m.ordered() {
+		r, err := e.client.TransactionReceipt(ctx, hash)
+		m.report(e, err)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if r != nil {
+			return r, nil
+		}
+		lastErr = nil
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("%v: %w", ErrAllEndpointsFailed, lastErr)
+	}
+	return nil, nil
+}
+
+// GasPrice implements the ethereum.Client interface.
+func (m *MultiClient) GasPrice(ctx context.Context) (*big.Int, error) {
+	v, err := m.failover(func(c pkgEthereum.Client) (interface{}, error) {
+		return c.GasPrice(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*big.Int), nil
+}
+
+// SendTransaction implements the ethereum.Client interface. The transaction
+// is signed once and sent to all endpoints. It fails only if all endpoints
+// fail.
+func (m *MultiClient) SendTransaction(ctx context.Context, transaction *pkgEthereum.Transaction) (*pkgEthereum.Hash, error) {
+	tx := transaction.Copy()
+	if tx.SignedTx == nil {
+		if err := m.FillTransaction(ctx, tx); err != nil {
+			return nil, err
+		}
+		if err := m.signer.SignTransaction(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoints := m.ordered()
+	hashes := make([]*pkgEthereum.Hash, len(endpoints))
+	errs := make([]error, len(endpoints))
+	wg := sync.WaitGroup{}
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			hashes[i], errs[i] = e.client.SendTransaction(ctx, tx)
+		}(i, e)
+	}
+	wg.Wait()
+
+	var hash *pkgEthereum.Hash
+	var lastErr error
+	for i, e := range endpoints {
+		if errs[i] != nil {
+			// The error does not mean that the endpoint is unhealthy,
+			// for example the transaction may be already known:
+			m.log.
+				WithFields(log.Fields{"endpoint": e.name}).
+				WithError(errs[i]).
+				Warn("Unable to send the transaction")
+			lastErr = errs[i]
+			continue
+		}
+		if hash == nil {
+			hash = hashes[i]
+		}
+	}
+	if hash == nil {
+		return nil, fmt.Errorf("%v: %w", ErrAllEndpointsFailed, lastErr)
+	}
+	return hash, nil
+}
+
+// read uses the failover with the latest function if the quorum is not
+// required, otherwise it queries all endpoints with the at function and
+// returns the result on which the quorum of endpoints agreed.
+func (m *MultiClient) read(
+	ctx context.Context,
+	latest func(c pkgEthereum.Client) (interface{}, error),
+	at func(c pkgEthereum.BlockClient, block *big.Int) (interface{}, error),
+) (interface{}, error) {
+
+	if m.quorum < 2 {
+		return m.failover(latest)
+	}
+
+	// Endpoints may be at different blocks, so the "latest" state may
+	// differ between them. To compare results, all endpoints read
+	// the state at the lowest block known to all of them:
+	endpoints, block := m.commonBlock(ctx)
+	if len(endpoints) < m.quorum {
+		return nil, fmt.Errorf(
+			"%v: %d of %d endpoints must agree, %d returned the block number",
+			ErrQuorumNotReached, m.quorum, len(m.endpoints), len(endpoints),
+		)
+	}
+	results := make([]readResult, len(endpoints))
+	wg := sync.WaitGroup{}
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			v, err := at(e.client.(pkgEthereum.BlockClient), block)
+			results[i] = readResult{endpoint: e, value: v, err: err}
+		}(i, e)
+	}
+	wg.Wait()
+
+	// Group identical results. Reverts are valid results, so they are
+	// compared by their messages:
+	var groups [][]readResult
+	for _, r := range results {
+		var revert ErrRevert
+		isRevert := errors.As(r.err, &revert)
+		if r.err != nil && !isRevert {
+			m.report(r.endpoint, r.err)
+			continue
+		}
+		m.report(r.endpoint, nil)
+		found := false
+		for i, g := range groups {
+			if sameResult(g[0], r) {
+				groups[i] = append(groups[i], r)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, []readResult{r})
+		}
+	}
+	for _, g := range groups {
+		if len(g) >= m.quorum {
+			return g[0].value, g[0].err
+		}
+	}
+	return nil, fmt.Errorf("%v: %d of %d endpoints must agree", ErrQuorumNotReached, m.quorum, len(endpoints))
+}
+
+// commonBlock returns endpoints which returned the latest block number and
+// the lowest of these numbers. Endpoints which failed are reported as
+// unhealthy.
+func (m *MultiClient) commonBlock(ctx context.Context) ([]*endpoint, *big.Int) {
+	endpoints := m.ordered()
+	blocks := make([]*big.Int, len(endpoints))
+	errs := make([]error, len(endpoints))
+	wg := sync.WaitGroup{}
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			blocks[i], errs[i] = e.client.(pkgEthereum.BlockClient).BlockNumber(ctx)
+		}(i, e)
+	}
+	wg.Wait()
+
+	var ok []*endpoint
+	var block *big.Int
+	for i, e := range endpoints {
+		if errs[i] != nil {
+			m.report(e, errs[i])
+			continue
+		}
+		ok = append(ok, e)
+		if block == nil || blocks[i].Cmp(block) < 0 {
+			block = blocks[i]
+		}
+	}
+	return ok, block
+}
+
+// failover calls f with clients of subsequent endpoints until it succeeds.
+// EVM reverts are returned immediately because other endpoints would
+// return the same result.
+func (m *MultiClient) failover(f func(c pkgEthereum.Client) (interface{}, error)) (interface{}, error) {
+	var lastErr error
+	for _, e := range m.ordered() {
+		v, err := f(e.client)
+		var revert ErrRevert
+		if err == nil || errors.As(err, &revert) {
+			m.report(e, nil)
+			return v, err
+		}
+		m.report(e, err)
+		m.log.
+			WithFields(log.Fields{"endpoint": e.name}).
+			WithError(err).
+			Warn("Ethereum endpoint failed, trying the next one")
+		lastErr = err
+	}
+	return nil, fmt.Errorf("%v: %w", ErrAllEndpointsFailed, lastErr)
+}
+
+// ordered returns endpoints with healthy ones first, preserving the order
+// from the configuration.
+func (m *MultiClient) ordered() []*endpoint {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var healthy, unhealthy []*endpoint
+	for _, e := range m.endpoints {
+		if e.healthy {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// report updates the health of the endpoint using the result of a request.
+func (m *MultiClient) report(e *endpoint, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e.healthy = err == nil
+	e.lastErr = err
+}
+
+func (m *MultiClient) healthCheckLoop() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, e := range m.ordered() {
+				c, ok := e.client.(health.Checker)
+				if !ok {
+					continue
+				}
+				s := c.HealthStatus()
+				if s.Ready {
+					m.report(e, nil)
+					continue
+				}
+				m.report(e, fmt.Errorf("health check failed: %v", s.Details["error"]))
+			}
+		}
+	}
+}
+
+func sameResult(a, b readResult) bool {
+	if a.err != nil || b.err != nil {
+		return a.err != nil && b.err != nil && a.err.Error() == b.err.Error()
+	}
+	return reflect.DeepEqual(a.value, b.value)
+}
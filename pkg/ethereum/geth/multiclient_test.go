@@ -0,0 +1,201 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	pkgEthereum "github.com/makerdao/oracle-suite/pkg/ethereum"
+	ethereumMocks "github.com/makerdao/oracle-suite/pkg/ethereum/mocks"
+	"github.com/makerdao/oracle-suite/pkg/log/null"
+)
+
+func newTestMultiClient(t *testing.T, quorum int, n int) (*MultiClient, []*ethereumMocks.Client, *ethereumMocks.Signer) {
+	var endpoints []Endpoint
+	var clients []*ethereumMocks.Client
+	for i := 0; i < n; i++ {
+		c := &ethereumMocks.Client{}
+		clients = append(clients, c)
+		endpoints = append(endpoints, Endpoint{Name: string(rune('a' + i)), Client: c})
+	}
+	signer := &ethereumMocks.Signer{}
+	m, err := NewMultiClient(MultiClientConfig{
+		Endpoints: endpoints,
+		Signer:    signer,
+		Quorum:    quorum,
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+	return m, clients, signer
+}
+
+func TestMultiClient_Call_Failover(t *testing.T) {
+	m, clients, _ := newTestMultiClient(t, 0, 2)
+	call := pkgEthereum.Call{Address: clientContractAddress, Data: clientCallData}
+
+	clients[0].On("Call", mock.Anything, call).Return([]byte(nil), errors.New("connection refused")).Once()
+	clients[1].On("Call", mock.Anything, call).Return(clientCallResp, nil).Twice()
+
+	resp, err := m.Call(context.Background(), call)
+	require.NoError(t, err)
+	assert.Equal(t, clientCallResp, resp)
+	assert.Equal(t, "b", m.ordered()[0].name)
+
+	// The failed endpoint should be used after healthy ones:
+	resp, err = m.Call(context.Background(), call)
+	require.NoError(t, err)
+	assert.Equal(t, clientCallResp, resp)
+	clients[0].AssertNumberOfCalls(t, "Call", 1)
+}
+
+func TestMultiClient_Call_Revert(t *testing.T) {
+	m, clients, _ := newTestMultiClient(t, 0, 2)
+	call := pkgEthereum.Call{Address: clientContractAddress, Data: clientCallData}
+
+	clients[0].On("Call", mock.Anything, call).Return([]byte(nil), ErrRevert{Message: "Not owner"})
+
+	_, err := m.Call(context.Background(), call)
+	assert.Equal(t, ErrRevert{Message: "Not owner"}, err)
+	clients[1].AssertNotCalled(t, "Call", mock.Anything, mock.Anything)
+	assert.True(t, m.HealthStatus().Ready)
+}
+
+func TestMultiClient_Call_AllFailed(t *testing.T) {
+	m, clients, _ := newTestMultiClient(t, 0, 2)
+	call := pkgEthereum.Call{Address: clientContractAddress, Data: clientCallData}
+
+	clients[0].On("Call", mock.Anything, call).Return([]byte(nil), errors.New("a"))
+	clients[1].On("Call", mock.Anything, call).Return([]byte(nil), errors.New("b"))
+
+	_, err := m.Call(context.Background(), call)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrAllEndpointsFailed.Error())
+	assert.False(t, m.HealthStatus().Ready)
+}
+
+func TestMultiClient_Storage_Quorum(t *testing.T) {
+	m, clients, _ := newTestMultiClient(t, 2, 3)
+	key := pkgEthereum.Hash{1}
+	block := big.NewInt(10)
+
+	for _, c := range clients {
+		c.On("BlockNumber", mock.Anything).Return(block, nil)
+	}
+	clients[0].On("StorageAt", mock.Anything, clientContractAddress, key, block).Return([]byte{1}, nil)
+	clients[1].On("StorageAt", mock.Anything, clientContractAddress, key, block).Return([]byte{2}, nil)
+	clients[2].On("StorageAt", mock.Anything, clientContractAddress, key, block).Return([]byte{2}, nil)
+
+	resp, err := m.Storage(context.Background(), clientContractAddress, key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2}, resp)
+}
+
+func TestMultiClient_Storage_QuorumNotReached(t *testing.T) {
+	m, clients, _ := newTestMultiClient(t, 2, 3)
+	key := pkgEthereum.Hash{1}
+	block := big.NewInt(10)
+
+	for _, c := range clients {
+		c.On("BlockNumber", mock.Anything).Return(block, nil)
+	}
+	clients[0].On("StorageAt", mock.Anything, clientContractAddress, key, block).Return([]byte{1}, nil)
+	clients[1].On("StorageAt", mock.Anything, clientContractAddress, key, block).Return([]byte{2}, nil)
+	clients[2].On("StorageAt", mock.Anything, clientContractAddress, key, block).Return([]byte(nil), errors.New("timeout"))
+
+	_, err := m.Storage(context.Background(), clientContractAddress, key)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrQuorumNotReached.Error())
+}
+
+func TestMultiClient_Call_QuorumPinnedBlock(t *testing.T) {
+	m, clients, _ := newTestMultiClient(t, 2, 3)
+	call := pkgEthereum.Call{Address: clientContractAddress, Data: clientCallData}
+
+	// Endpoints are at different blocks, so all of them must read the state
+	// at the lowest one. The endpoint which failed is not used:
+	clients[0].On("BlockNumber", mock.Anything).Return(big.NewInt(12), nil)
+	clients[1].On("BlockNumber", mock.Anything).Return(big.NewInt(11), nil)
+	clients[2].On("BlockNumber", mock.Anything).Return((*big.Int)(nil), errors.New("timeout"))
+	clients[0].On("CallAt", mock.Anything, call, big.NewInt(11)).Return(clientCallResp, nil)
+	clients[1].On("CallAt", mock.Anything, call, big.NewInt(11)).Return(clientCallResp, nil)
+
+	resp, err := m.Call(context.Background(), call)
+	require.NoError(t, err)
+	assert.Equal(t, clientCallResp, resp)
+	clients[2].AssertNotCalled(t, "CallAt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMultiClient_Call_QuorumNoBlockNumber(t *testing.T) {
+	m, clients, _ := newTestMultiClient(t, 2, 2)
+	call := pkgEthereum.Call{Address: clientContractAddress, Data: clientCallData}
+
+	clients[0].On("BlockNumber", mock.Anything).Return(big.NewInt(10), nil)
+	clients[1].On("BlockNumber", mock.Anything).Return((*big.Int)(nil), errors.New("timeout"))
+
+	_, err := m.Call(context.Background(), call)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrQuorumNotReached.Error())
+	clients[0].AssertNotCalled(t, "CallAt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNewMultiClient_QuorumRequiresBlockClient(t *testing.T) {
+	_, err := NewMultiClient(MultiClientConfig{
+		Endpoints: []Endpoint{{Name: "a", Client: struct{ pkgEthereum.Client }{}}},
+		Quorum:    2,
+		Logger:    null.New(),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ErrBlockReadsNotSupported.Error())
+}
+
+func TestMultiClient_SendTransaction(t *testing.T) {
+	m, clients, signer := newTestMultiClient(t, 0, 3)
+	hash := pkgEthereum.Hash{1}
+	signedTx := "signed"
+
+	clients[0].On("FillTransaction", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*pkgEthereum.Transaction).Nonce = 10
+	})
+	signer.On("SignTransaction", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*pkgEthereum.Transaction).SignedTx = signedTx
+	})
+	clients[0].On("SendTransaction", mock.Anything, mock.Anything).Return((*pkgEthereum.Hash)(nil), errors.New("timeout"))
+	clients[1].On("SendTransaction", mock.Anything, mock.Anything).Return(&hash, nil)
+	clients[2].On("SendTransaction", mock.Anything, mock.Anything).Return(&hash, nil)
+
+	tx := &pkgEthereum.Transaction{Address: clientContractAddress, Data: clientCallData}
+	ret, err := m.SendTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, hash, *ret)
+
+	// The same signed transaction should be sent to all endpoints:
+	for _, c := range clients {
+		stx := c.Calls[len(c.Calls)-1].Arguments.Get(1).(*pkgEthereum.Transaction)
+		assert.Equal(t, uint64(10), stx.Nonce)
+		assert.Equal(t, signedTx, stx.SignedTx)
+	}
+
+	// The passed transaction must not be modified:
+	assert.Nil(t, tx.SignedTx)
+	assert.Equal(t, uint64(0), tx.Nonce)
+}
@@ -0,0 +1,165 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+)
+
+// remoteSignerTimeout is the maximum time to wait for the remote signer.
+// It is relatively long because the external signer may require a manual
+// approval.
+const remoteSignerTimeout = time.Minute
+
+var ErrInvalidRemoteSignature = errors.New("remote signer returned an invalid signature")
+var ErrEmptyRemoteTransaction = errors.New("remote signer returned an empty transaction")
+
+// RemoteSigner implements the ethereum.Signer interface using an external
+// signer, like Clef, with the account_signData and account_signTransaction
+// JSON-RPC methods.
+type RemoteSigner struct {
+	client  *rpc.Client
+	address ethereum.Address
+}
+
+// sendTxArgs are the arguments of the account_signTransaction method.
+type sendTxArgs struct {
+	From                 string         `json:"from"`
+	To                   string         `json:"to"`
+	Gas                  hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big   `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas,omitempty"`
+	Value                hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64 `json:"nonce"`
+	Data                 hexutil.Bytes  `json:"data"`
+	ChainID              *hexutil.Big   `json:"chainId,omitempty"`
+}
+
+// signTxResult is the result of the account_signTransaction method.
+type signTxResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+// NewRemoteSigner returns a new RemoteSigner instance which signs data with
+// the key of the given address using the given RPC client.
+func NewRemoteSigner(client *rpc.Client, address ethereum.Address) *RemoteSigner {
+	return &RemoteSigner{
+		client:  client,
+		address: address,
+	}
+}
+
+// DialRemoteSigner connects to the remote signer at the given URL. The URL
+// may be a HTTP, WebSocket or IPC endpoint.
+func DialRemoteSigner(url string, address ethereum.Address) (*RemoteSigner, error) {
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return NewRemoteSigner(client, address), nil
+}
+
+// Address implements the ethereum.Signer interface.
+func (s *RemoteSigner) Address() ethereum.Address {
+	return s.address
+}
+
+// SignTransaction implements the ethereum.Signer interface.
+func (s *RemoteSigner) SignTransaction(transaction *ethereum.Transaction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSignerTimeout)
+	defer cancel()
+
+	args := sendTxArgs{
+		From:    s.address.Hex(),
+		To:      transaction.Address.Hex(),
+		Gas:     hexutil.Uint64(transaction.GasLimit.Uint64()),
+		Nonce:   hexutil.Uint64(transaction.Nonce),
+		Data:    transaction.Data,
+		ChainID: (*hexutil.Big)(transaction.ChainID),
+	}
+	if transaction.Type == ethereum.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(transaction.MaxFee)
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(transaction.PriorityFee)
+	} else {
+		args.GasPrice = (*hexutil.Big)(transaction.Gas)
+	}
+
+	var res signTxResult
+	if err := s.client.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
+		return err
+	}
+	if len(res.Raw) == 0 {
+		return ErrEmptyRemoteTransaction
+	}
+	if res.Raw[0] == dynamicFeeTxType {
+		tx := &DynamicFeeTx{}
+		if err := rlp.DecodeBytes(res.Raw[1:], tx); err != nil {
+			return err
+		}
+		transaction.SignedTx = tx
+		return nil
+	}
+	tx := &types.Transaction{}
+	if err := tx.UnmarshalBinary(res.Raw); err != nil {
+		return err
+	}
+	transaction.SignedTx = tx
+	return nil
+}
+
+// Signature implements the ethereum.Signer interface.
+func (s *RemoteSigner) Signature(data []byte) (ethereum.Signature, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSignerTimeout)
+	defer cancel()
+
+	// The text/plain content type signs the data with the Ethereum signed
+	// message prefix, the same way as the Signature function does:
+	var res hexutil.Bytes
+	err := s.client.CallContext(ctx, &res, "account_signData", "text/plain", s.address.Hex(), hexutil.Bytes(data))
+	if err != nil {
+		return ethereum.Signature{}, err
+	}
+	if len(res) != ethereum.SignatureLength {
+		return ethereum.Signature{}, ErrInvalidRemoteSignature
+	}
+
+	// Some signers may return V as 0/1 instead of 27/28:
+	if res[64] < 27 {
+		res[64] += 27
+	}
+
+	return ethereum.SignatureFromBytes(res), nil
+}
+
+// Recover implements the ethereum.Signer interface.
+func (s *RemoteSigner) Recover(signature ethereum.Signature, data []byte) (*ethereum.Address, error) {
+	return Recover(signature, data)
+}
+
+// Close closes the connection to the remote signer.
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
@@ -0,0 +1,164 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+)
+
+// testClef is a stand-in for the Clef external signer API.
+type testClef struct {
+	key *ecdsa.PrivateKey
+}
+
+func (c *testClef) SignData(contentType string, addr string, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if contentType != "text/plain" {
+		return nil, fmt.Errorf("unsupported content type %s", contentType)
+	}
+	if !c.owns(addr) {
+		return nil, fmt.Errorf("unknown account %s", addr)
+	}
+	msg := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), []byte(data))
+	sig, err := crypto.Sign(crypto.Keccak256([]byte(msg)), c.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+func (c *testClef) SignTransaction(args sendTxArgs) (*signTxResult, error) {
+	if !c.owns(args.From) {
+		return nil, fmt.Errorf("unknown account %s", args.From)
+	}
+	to := common.HexToAddress(args.To)
+	if args.MaxFeePerGas != nil {
+		tx := &DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        &to,
+			Data:      args.Data,
+		}
+		hash, err := tx.SigningHash()
+		if err != nil {
+			return nil, err
+		}
+		sig, err := crypto.Sign(hash.Bytes(), c.key)
+		if err != nil {
+			return nil, err
+		}
+		tx.setSignature(sig)
+		raw, err := tx.MarshalBinary()
+		return &signTxResult{Raw: raw}, err
+	}
+	tx := types.NewTransaction(uint64(args.Nonce), to, nil, uint64(args.Gas), args.GasPrice.ToInt(), args.Data)
+	stx, err := types.SignTx(tx, types.NewEIP155Signer(args.ChainID.ToInt()), c.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := stx.MarshalBinary()
+	return &signTxResult{Raw: raw}, err
+}
+
+func (c *testClef) owns(addr string) bool {
+	return common.HexToAddress(addr) == crypto.PubkeyToAddress(c.key.PublicKey)
+}
+
+func newTestRemoteSigner(t *testing.T) (*RemoteSigner, ethereum.Address) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("account", &testClef{key: key}))
+	t.Cleanup(srv.Stop)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	return NewRemoteSigner(rpc.DialInProc(srv), address), address
+}
+
+func TestRemoteSigner_Signature(t *testing.T) {
+	signer, address := newTestRemoteSigner(t)
+
+	signature, err := signer.Signature(signerData)
+	require.NoError(t, err)
+
+	recovered, err := signer.Recover(signature, signerData)
+	require.NoError(t, err)
+	assert.Equal(t, address, *recovered)
+}
+
+func TestRemoteSigner_SignTransaction(t *testing.T) {
+	signer, address := newTestRemoteSigner(t)
+
+	tx := &ethereum.Transaction{
+		Address:  clientContractAddress,
+		Nonce:    10,
+		Gas:      big.NewInt(100),
+		GasLimit: big.NewInt(1000),
+		Data:     clientCallData,
+		ChainID:  big.NewInt(mainnetChainID),
+	}
+	require.NoError(t, signer.SignTransaction(tx))
+
+	stx := tx.SignedTx.(*types.Transaction)
+	sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(mainnetChainID)), stx)
+	require.NoError(t, err)
+	assert.Equal(t, address, sender)
+	assert.Equal(t, uint64(10), stx.Nonce())
+	assert.Equal(t, big.NewInt(100), stx.GasPrice())
+	assert.Equal(t, clientCallData, stx.Data())
+}
+
+func TestRemoteSigner_SignTransaction_DynamicFee(t *testing.T) {
+	signer, address := newTestRemoteSigner(t)
+
+	tx := &ethereum.Transaction{
+		Type:        ethereum.DynamicFeeTxType,
+		Address:     clientContractAddress,
+		Nonce:       10,
+		MaxFee:      big.NewInt(200),
+		PriorityFee: big.NewInt(2),
+		GasLimit:    big.NewInt(1000),
+		Data:        clientCallData,
+		ChainID:     big.NewInt(mainnetChainID),
+	}
+	require.NoError(t, signer.SignTransaction(tx))
+
+	stx := tx.SignedTx.(*DynamicFeeTx)
+	assert.Equal(t, big.NewInt(200), stx.GasFeeCap)
+	assert.Equal(t, big.NewInt(2), stx.GasTipCap)
+
+	hash, err := stx.SigningHash()
+	require.NoError(t, err)
+	sig := append(append(common.LeftPadBytes(stx.R.Bytes(), 32), common.LeftPadBytes(stx.S.Bytes(), 32)...), byte(stx.V.Uint64()))
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	require.NoError(t, err)
+	assert.Equal(t, address, crypto.PubkeyToAddress(*pub))
+}
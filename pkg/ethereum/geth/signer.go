@@ -48,8 +48,13 @@ func (s *Signer) Address() ethereum.Address {
 	return s.account.address
 }
 
-// SignTransaction implements the ethereum.Signer interface.
+// SignTransaction implements the ethereum.Signer interface. Legacy
+// transactions are stored in the SignedTx field as *types.Transaction and
+// dynamic fee transactions as *DynamicFeeTx.
 func (s *Signer) SignTransaction(transaction *ethereum.Transaction) error {
+	if transaction.Type == ethereum.DynamicFeeTxType {
+		return s.signDynamicFeeTransaction(transaction)
+	}
 	tx := types.NewTransaction(
 		transaction.Nonce,
 		transaction.Address,
@@ -71,6 +76,36 @@ func (s *Signer) SignTransaction(transaction *ethereum.Transaction) error {
 	return nil
 }
 
+func (s *Signer) signDynamicFeeTransaction(transaction *ethereum.Transaction) error {
+	to := transaction.Address
+	tx := &DynamicFeeTx{
+		ChainID:   transaction.ChainID,
+		Nonce:     transaction.Nonce,
+		GasTipCap: transaction.PriorityFee,
+		GasFeeCap: transaction.MaxFee,
+		Gas:       transaction.GasLimit.Uint64(),
+		To:        &to,
+		Data:      transaction.Data,
+	}
+	payload, err := tx.SigningPayload()
+	if err != nil {
+		return err
+	}
+	// The SignDataWithPassphrase signs the Keccak256 hash of the payload:
+	signature, err := s.account.wallet.SignDataWithPassphrase(
+		*s.account.account,
+		s.account.passphrase,
+		"",
+		payload,
+	)
+	if err != nil {
+		return err
+	}
+	tx.setSignature(signature)
+	transaction.SignedTx = tx
+	return nil
+}
+
 // Signature implements the ethereum.Signer interface.
 func (s *Signer) Signature(data []byte) (ethereum.Signature, error) {
 	return Signature(s.account, data)
@@ -17,6 +17,7 @@ package mocks
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/stretchr/testify/mock"
 
@@ -46,3 +47,44 @@ func (c *Client) SendTransaction(ctx context.Context, transaction *ethereum.Tran
 	args := c.Called(ctx, transaction)
 	return args.Get(0).(*ethereum.Hash), args.Error(1)
 }
+
+func (c *Client) FillTransaction(ctx context.Context, transaction *ethereum.Transaction) error {
+	args := c.Called(ctx, transaction)
+	return args.Error(0)
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, hash ethereum.Hash) (*ethereum.Receipt, error) {
+	args := c.Called(ctx, hash)
+	return args.Get(0).(*ethereum.Receipt), args.Error(1)
+}
+
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	args := c.Called(ctx)
+	return args.Get(0).(*big.Int), args.Error(1)
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (*big.Int, error) {
+	args := c.Called(ctx)
+	return args.Get(0).(*big.Int), args.Error(1)
+}
+
+func (c *Client) CallAt(ctx context.Context, call ethereum.Call, block *big.Int) ([]byte, error) {
+	args := c.Called(ctx, call, block)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (c *Client) MultiCallAt(ctx context.Context, calls []ethereum.Call, block *big.Int) ([][]byte, error) {
+	args := c.Called(ctx, calls, block)
+	return args.Get(0).([][]byte), args.Error(1)
+}
+
+func (c *Client) StorageAt(
+	ctx context.Context,
+	address ethereum.Address,
+	key ethereum.Hash,
+	block *big.Int,
+) ([]byte, error) {
+
+	args := c.Called(ctx, address, key, block)
+	return args.Get(0).([]byte), args.Error(1)
+}
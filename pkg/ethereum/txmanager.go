@@ -0,0 +1,360 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/makerdao/oracle-suite/pkg/log"
+)
+
+const TxManagerLoggerTag = "TX_MANAGER"
+
+const (
+	// DefaultReplaceAfter is the default time after which a pending
+	// transaction is replaced with a higher fee.
+	DefaultReplaceAfter = 3 * time.Minute
+	// DefaultFeeBump is the default multiplier applied to the fees of
+	// a replaced transaction. Nodes require at least a 10% increase.
+	DefaultFeeBump = 1.125
+	// DefaultMaxReplacements is the default number of replacements after
+	// which the transaction is no longer tracked.
+	DefaultMaxReplacements = 5
+	// DefaultPollInterval is the default interval between receipt checks.
+	DefaultPollInterval = 15 * time.Second
+)
+
+// TxManagerConfig is the configuration for the TxManager.
+type TxManagerConfig struct {
+	// Client is used to send transactions and to query receipts. All
+	// transactions must be sent from the same account.
+	Client Client
+	// ReplaceAfter is the time after which a pending transaction is
+	// replaced. If zero, the DefaultReplaceAfter is used.
+	ReplaceAfter time.Duration
+	// FeeBump is the multiplier applied to the fees of a replaced
+	// transaction. If zero, the DefaultFeeBump is used.
+	FeeBump float64
+	// MaxReplacements is the maximum number of replacements of a single
+	// transaction. If zero, the DefaultMaxReplacements is used.
+	MaxReplacements int
+	// PollInterval is the interval between receipt checks. If zero,
+	// the DefaultPollInterval is used.
+	PollInterval time.Duration
+	// Logger is a current logger interface used by the TxManager.
+	Logger log.Logger
+}
+
+// TxManager is the Client which tracks transactions sent by a single
+// account. It assigns consecutive nonces, waits for receipts and replaces
+// transactions which are not mined in time with the same nonce and higher
+// fees, until the fees would exceed the fee caps of the transaction. Methods other than SendTransaction are passed to the wrapped client.
+type TxManager struct {
+	Client
+
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	replaceAfter    time.Duration
+	feeBump         float64
+	maxReplacements int
+	pollInterval    time.Duration
+	log             log.Logger
+
+	// nextNonce is the nonce for the next transaction, it is valid only
+	// if hasNonce is true.
+	nextNonce uint64
+	hasNonce  bool
+	// pending contains pending transactions by the hash of the first sent
+	// transaction. The hashes map contains hashes of all sent transactions,
+	// including replacements, mapped to the hash of the first transaction.
+	pending map[Hash]*pendingTx
+	hashes  map[Hash]Hash
+}
+
+type pendingTx struct {
+	tx           *Transaction
+	hashes       []Hash
+	sentAt       time.Time
+	replacements int
+}
+
+// NewTxManager returns a new TxManager instance.
+func NewTxManager(cfg TxManagerConfig) *TxManager {
+	if cfg.ReplaceAfter == 0 {
+		cfg.ReplaceAfter = DefaultReplaceAfter
+	}
+	if cfg.FeeBump == 0 {
+		cfg.FeeBump = DefaultFeeBump
+	}
+	if cfg.MaxReplacements == 0 {
+		cfg.MaxReplacements = DefaultMaxReplacements
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TxManager{
+		Client:          cfg.Client,
+		ctx:             ctx,
+		cancel:          cancel,
+		replaceAfter:    cfg.ReplaceAfter,
+		feeBump:         cfg.FeeBump,
+		maxReplacements: cfg.MaxReplacements,
+		pollInterval:    cfg.PollInterval,
+		log:             cfg.Logger.WithField("tag", TxManagerLoggerTag),
+		pending:         make(map[Hash]*pendingTx),
+		hashes:          make(map[Hash]Hash),
+	}
+}
+
+// Start starts the loop which checks pending transactions.
+func (m *TxManager) Start() error {
+	m.log.Info("Starting")
+	go m.loop()
+	return nil
+}
+
+// Stop stops the loop. Pending transactions are no longer tracked.
+func (m *TxManager) Stop() error {
+	defer m.log.Info("Stopped")
+	m.cancel()
+	return nil
+}
+
+// SendTransaction implements the Client interface. If the nonce is not
+// set, the next nonce of the account is used, even if previous transactions
+// are still pending.
+func (m *TxManager) SendTransaction(ctx context.Context, transaction *Transaction) (*Hash, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx := transaction.Copy()
+	autoNonce := tx.Nonce == 0 && !tx.NonceSet
+	if err := m.Client.FillTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	// The node may not know about our pending transactions, for example
+	// if they were sent to a different node:
+	if autoNonce && m.hasNonce && m.nextNonce > tx.Nonce {
+		tx.Nonce = m.nextNonce
+	}
+	// Replacements must use the same nonce, even if it is zero:
+	tx.NonceSet = true
+	hash, err := m.Client.SendTransaction(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.nextNonce = tx.Nonce + 1
+	m.hasNonce = true
+	m.pending[*hash] = &pendingTx{
+		tx:     tx,
+		hashes: []Hash{*hash},
+		sentAt: time.Now(),
+	}
+	m.hashes[*hash] = *hash
+	m.log.
+		WithFields(log.Fields{"tx": hash.String(), "nonce": tx.Nonce}).
+		Debug("Transaction sent")
+
+	return hash, nil
+}
+
+// Pending returns true if the transaction with the given hash, or any of
+// its replacements, is still pending.
+func (m *TxManager) Pending(hash Hash) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	first, ok := m.hashes[hash]
+	if !ok {
+		return false
+	}
+	_, ok = m.pending[first]
+	return ok
+}
+
+func (m *TxManager) loop() {
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkPending()
+		}
+	}
+}
+
+// checkPending checks receipts of all pending transactions and replaces
+// transactions which are pending for too long. RPC calls are made without
+// holding the lock, so SendTransaction and Pending are not blocked by slow
+// nodes.
+func (m *TxManager) checkPending() {
+	// Take a snapshot of pending transactions. Only this method modifies
+	// existing pending transactions and it is never called concurrently,
+	// so the snapshot stays valid until the results are applied.
+	m.mu.Lock()
+	snapshot := make(map[Hash]pendingTx, len(m.pending))
+	for first, p := range m.pending {
+		cp := *p
+		cp.hashes = append([]Hash(nil), p.hashes...)
+		snapshot[first] = cp
+	}
+	m.mu.Unlock()
+
+	for first, p := range snapshot {
+		fields := log.Fields{"tx": first.String(), "nonce": p.tx.Nonce}
+
+		receipt, err := m.receipt(p.hashes)
+		if err != nil {
+			m.log.WithFields(fields).WithError(err).Warn("Unable to fetch the transaction receipt")
+			continue
+		}
+		if receipt != nil {
+			fields["minedTx"] = receipt.TxHash.String()
+			fields["block"] = receipt.BlockNumber.String()
+			if receipt.Status == 0 {
+				m.log.WithFields(fields).Warn("Transaction reverted")
+			} else {
+				m.log.WithFields(fields).Info("Transaction mined")
+			}
+			m.mu.Lock()
+			m.remove(first)
+			m.mu.Unlock()
+			continue
+		}
+		if time.Since(p.sentAt) < m.replaceAfter {
+			continue
+		}
+		if p.replacements >= m.maxReplacements {
+			// The transaction may have been dropped or another transaction
+			// with the same nonce may have been mined. We stop tracking it
+			// and use the nonce from the node for the next transaction.
+			m.log.WithFields(fields).Warn("Transaction is not mined after all replacements, it is no longer tracked")
+			m.mu.Lock()
+			m.remove(first)
+			m.hasNonce = false
+			m.mu.Unlock()
+			continue
+		}
+		tx, ok := m.bumpFees(p.tx)
+		if !ok {
+			// Nodes reject replacements which do not increase fees enough,
+			// so there is no point in sending a replacement with capped
+			// fees. We give up the same way as after all replacements.
+			m.log.WithFields(fields).Warn("Transaction fees reached the cap, it is no longer replaced nor tracked")
+			m.mu.Lock()
+			m.remove(first)
+			m.hasNonce = false
+			m.mu.Unlock()
+			continue
+		}
+		m.replace(first, tx, fields)
+	}
+}
+
+// receipt returns the receipt of the pending transaction or any of its
+// replacements. If none of them is mined, nil is returned.
+func (m *TxManager) receipt(hashes []Hash) (*Receipt, error) {
+	for i := len(hashes) - 1; i >= 0; i-- {
+		r, err := m.Client.TransactionReceipt(m.ctx, hashes[i])
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// bumpFees returns a copy of the transaction with increased fees. It returns
+// false if the increased fees would exceed the fee caps of the transaction.
+func (m *TxManager) bumpFees(pendingTx *Transaction) (*Transaction, bool) {
+	tx := pendingTx.Copy()
+	tx.SignedTx = nil
+	if tx.Type == DynamicFeeTxType {
+		tx.MaxFee = bumpFee(tx.MaxFee, m.feeBump)
+		tx.PriorityFee = bumpFee(tx.PriorityFee, m.feeBump)
+		return tx, !exceedsCap(tx.MaxFee, tx.MaxFeeCap) && !exceedsCap(tx.PriorityFee, tx.PriorityFeeCap)
+	}
+	tx.Gas = bumpFee(tx.Gas, m.feeBump)
+	return tx, !exceedsCap(tx.Gas, tx.MaxFeeCap)
+}
+
+// replace sends the replacement of the pending transaction. The first is
+// the hash of the first sent transaction.
+func (m *TxManager) replace(first Hash, tx *Transaction, fields log.Fields) {
+	hash, err := m.Client.SendTransaction(m.ctx, tx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.pending[first]
+	if !ok {
+		return
+	}
+	// Even if the replacement fails, we wait another period before
+	// the next attempt:
+	p.sentAt = time.Now()
+	p.replacements++
+	if err != nil {
+		m.log.WithFields(fields).WithError(err).Warn("Unable to replace the transaction")
+		return
+	}
+	p.tx = tx
+	p.hashes = append(p.hashes, *hash)
+	m.hashes[*hash] = first
+	fields["replacementTx"] = hash.String()
+	m.log.WithFields(fields).Info("Transaction replaced")
+}
+
+func (m *TxManager) remove(first Hash) {
+	if p, ok := m.pending[first]; ok {
+		for _, h := range p.hashes {
+			delete(m.hashes, h)
+		}
+		delete(m.pending, first)
+	}
+}
+
+// exceedsCap returns true if the fee is greater than the cap. A nil cap
+// means there is no limit.
+func exceedsCap(fee, limit *big.Int) bool {
+	return fee != nil && limit != nil && fee.Cmp(limit) > 0
+}
+
+// bumpFee multiplies the fee by the given multiplier, rounding up. The
+// result is always greater than the given fee.
+func bumpFee(fee *big.Int, multiplier float64) *big.Int {
+	if fee == nil {
+		return nil
+	}
+	f, acc := new(big.Float).Mul(new(big.Float).SetInt(fee), big.NewFloat(multiplier)).Int(nil)
+	if acc == big.Below {
+		f.Add(f, big.NewInt(1))
+	}
+	if f.Cmp(fee) <= 0 {
+		f.Add(fee, big.NewInt(1))
+	}
+	return f
+}
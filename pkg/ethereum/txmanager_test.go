@@ -0,0 +1,253 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/log/null"
+)
+
+// fakeClient is a minimal Client which fills nonces and fees like a node
+// which does not know about pending transactions.
+type fakeClient struct {
+	mu       sync.Mutex
+	nonce    uint64
+	sent     []*Transaction
+	receipts map[Hash]*Receipt
+
+	// receiptCh, if not nil, blocks TransactionReceipt calls until it is
+	// closed.
+	receiptCh chan struct{}
+}
+
+func (c *fakeClient) Call(context.Context, Call) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) MultiCall(context.Context, []Call) ([][]byte, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) Storage(context.Context, Address, Hash) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) SendTransaction(ctx context.Context, transaction *Transaction) (*Hash, error) {
+	tx := transaction.Copy()
+	if err := c.FillTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, tx)
+	hash := Hash{byte(len(c.sent))}
+	return &hash, nil
+}
+
+func (c *fakeClient) FillTransaction(_ context.Context, tx *Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if tx.Nonce == 0 && !tx.NonceSet {
+		tx.Nonce = c.nonce
+	}
+	tx.NonceSet = true
+	if tx.Type == DynamicFeeTxType {
+		if tx.MaxFee == nil {
+			tx.MaxFee = big.NewInt(100)
+		}
+		if tx.PriorityFee == nil {
+			tx.PriorityFee = big.NewInt(10)
+		}
+	} else if tx.Gas == nil {
+		tx.Gas = big.NewInt(100)
+	}
+	return nil
+}
+
+func (c *fakeClient) TransactionReceipt(_ context.Context, hash Hash) (*Receipt, error) {
+	if c.receiptCh != nil {
+		<-c.receiptCh
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.receipts[hash], nil
+}
+
+func (c *fakeClient) GasPrice(context.Context) (*big.Int, error) {
+	return big.NewInt(100), nil
+}
+
+func (c *fakeClient) mine(hash Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.receipts[hash] = &Receipt{TxHash: hash, BlockNumber: big.NewInt(1), Status: 1}
+}
+
+func newTestTxManager(replaceAfter time.Duration) (*TxManager, *fakeClient) {
+	c := &fakeClient{nonce: 5, receipts: map[Hash]*Receipt{}}
+	m := NewTxManager(TxManagerConfig{
+		Client:          c,
+		ReplaceAfter:    replaceAfter,
+		MaxReplacements: 2,
+		Logger:          null.New(),
+	})
+	return m, c
+}
+
+func TestTxManager_SendTransaction(t *testing.T) {
+	m, c := newTestTxManager(time.Hour)
+
+	h1, err := m.SendTransaction(context.Background(), &Transaction{})
+	require.NoError(t, err)
+	h2, err := m.SendTransaction(context.Background(), &Transaction{})
+	require.NoError(t, err)
+
+	// The node returns the same pending nonce, so the manager must use
+	// the next one:
+	assert.Equal(t, uint64(5), c.sent[0].Nonce)
+	assert.Equal(t, uint64(6), c.sent[1].Nonce)
+	assert.True(t, m.Pending(*h1))
+	assert.True(t, m.Pending(*h2))
+
+	// Mined transactions are no longer pending:
+	c.mine(*h1)
+	m.checkPending()
+	assert.False(t, m.Pending(*h1))
+	assert.True(t, m.Pending(*h2))
+	assert.Len(t, c.sent, 2)
+}
+
+func TestTxManager_checkPending_NoLockDuringRPC(t *testing.T) {
+	m, c := newTestTxManager(time.Hour)
+
+	h1, err := m.SendTransaction(context.Background(), &Transaction{})
+	require.NoError(t, err)
+
+	// Receipts are fetched without holding the lock, so other methods can
+	// be used while the node is slow:
+	c.receiptCh = make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		m.checkPending()
+		close(done)
+	}()
+	_, err = m.SendTransaction(context.Background(), &Transaction{})
+	require.NoError(t, err)
+	assert.True(t, m.Pending(*h1))
+
+	c.mine(*h1)
+	close(c.receiptCh)
+	<-done
+	assert.False(t, m.Pending(*h1))
+}
+
+func TestTxManager_Replace(t *testing.T) {
+	m, c := newTestTxManager(time.Nanosecond)
+
+	h1, err := m.SendTransaction(context.Background(), &Transaction{Type: DynamicFeeTxType})
+	require.NoError(t, err)
+
+	// The transaction is not mined in time, so it should be replaced:
+	m.checkPending()
+	require.Len(t, c.sent, 2)
+	assert.Equal(t, c.sent[0].Nonce, c.sent[1].Nonce)
+	assert.Equal(t, big.NewInt(113), c.sent[1].MaxFee)
+	assert.Equal(t, big.NewInt(12), c.sent[1].PriorityFee)
+	h2 := Hash{2}
+	assert.True(t, m.Pending(*h1))
+	assert.True(t, m.Pending(h2))
+
+	// The replacement is mined:
+	c.mine(h2)
+	m.checkPending()
+	assert.False(t, m.Pending(*h1))
+	assert.False(t, m.Pending(h2))
+	assert.Len(t, c.sent, 2)
+}
+
+func TestTxManager_ReplaceZeroNonce(t *testing.T) {
+	m, c := newTestTxManager(time.Nanosecond)
+	c.nonce = 0
+
+	_, err := m.SendTransaction(context.Background(), &Transaction{})
+	require.NoError(t, err)
+
+	// The node already knows about the first transaction, but the
+	// replacement must still use its nonce:
+	c.nonce = 1
+	m.checkPending()
+	require.Len(t, c.sent, 2)
+	assert.Equal(t, uint64(0), c.sent[1].Nonce)
+}
+
+func TestTxManager_MaxReplacements(t *testing.T) {
+	m, c := newTestTxManager(time.Nanosecond)
+
+	h1, err := m.SendTransaction(context.Background(), &Transaction{})
+	require.NoError(t, err)
+
+	m.checkPending()
+	m.checkPending()
+	assert.Len(t, c.sent, 3)
+	assert.True(t, m.Pending(*h1))
+
+	// After the replacement limit is reached, the transaction is no longer
+	// tracked and the nonce from the node is used again:
+	m.checkPending()
+	assert.Len(t, c.sent, 3)
+	assert.False(t, m.Pending(*h1))
+
+	_, err = m.SendTransaction(context.Background(), &Transaction{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), c.sent[3].Nonce)
+}
+
+func TestTxManager_FeeCap(t *testing.T) {
+	m, c := newTestTxManager(time.Nanosecond)
+
+	h1, err := m.SendTransaction(context.Background(), &Transaction{MaxFeeCap: big.NewInt(120)})
+	require.NoError(t, err)
+
+	// The first replacement is within the cap:
+	m.checkPending()
+	require.Len(t, c.sent, 2)
+	assert.Equal(t, big.NewInt(113), c.sent[1].Gas)
+
+	// The next one would exceed the cap, so it is not sent and
+	// the transaction is no longer tracked:
+	m.checkPending()
+	assert.Len(t, c.sent, 2)
+	assert.False(t, m.Pending(*h1))
+
+	_, err = m.SendTransaction(context.Background(), &Transaction{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), c.sent[2].Nonce)
+}
+
+func Test_bumpFee(t *testing.T) {
+	assert.Equal(t, big.NewInt(113), bumpFee(big.NewInt(100), 1.125))
+	assert.Equal(t, big.NewInt(2), bumpFee(big.NewInt(1), 1.125))
+	assert.Equal(t, big.NewInt(1), bumpFee(big.NewInt(0), 1.125))
+	assert.Nil(t, bumpFee(nil, 1.125))
+}
@@ -0,0 +1,421 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	suite "github.com/makerdao/oracle-suite"
+	"github.com/makerdao/oracle-suite/pkg/config/shared"
+	"github.com/makerdao/oracle-suite/pkg/datastore"
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/ethereum/geth"
+	"github.com/makerdao/oracle-suite/pkg/feednode"
+	"github.com/makerdao/oracle-suite/pkg/ghost"
+	ghostConfig "github.com/makerdao/oracle-suite/pkg/ghost/config"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+	goferConfig "github.com/makerdao/oracle-suite/pkg/gofer/config"
+	"github.com/makerdao/oracle-suite/pkg/health"
+	"github.com/makerdao/oracle-suite/pkg/log"
+	"github.com/makerdao/oracle-suite/pkg/rpcsec"
+	"github.com/makerdao/oracle-suite/pkg/spire"
+	spireConfig "github.com/makerdao/oracle-suite/pkg/spire/config"
+	"github.com/makerdao/oracle-suite/pkg/stark"
+	"github.com/makerdao/oracle-suite/pkg/transport"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+	"github.com/makerdao/oracle-suite/pkg/transport/p2p"
+	"github.com/makerdao/oracle-suite/pkg/transport/p2p/crypto/ethkey"
+)
+
+var ErrFailedToLoadConfiguration = errors.New("failed to load feed node's configuration")
+var ErrFailedToReadPassphraseFile = shared.ErrFailedToReadPassphraseFile
+var ErrFailedToParsePrivKeySeed = shared.ErrFailedToParsePrivKeySeed
+var ErrFailedToReadStarkKeyFile = errors.New("failed to read the Stark key file")
+
+// Config is the configuration of the feed node. The ethereum, p2p and feeds
+// sections are shared by all services, the gofer section has the same
+// structure as the Gofer's config file.
+type Config struct {
+	Ethereum Ethereum           `json:"ethereum"`
+	P2P      P2P                `json:"p2p"`
+	Feeds    []string           `json:"feeds"`
+	Gofer    goferConfig.Config `json:"gofer"`
+	Ghost    Ghost              `json:"ghost"`
+	Spire    Spire              `json:"spire"`
+	Health   Health             `json:"health"`
+}
+
+type Ethereum struct {
+	From     string `json:"from"`
+	Keystore string `json:"keystore"`
+	Password string `json:"password"`
+	// RemoteSigner is the address of an external signer, like Clef, which
+	// holds the key of the "from" account. If set, the keystore and
+	// password are not used.
+	RemoteSigner string `json:"remoteSigner"`
+}
+
+type P2P struct {
+	PrivKeySeed      string   `json:"privKeySeed"`
+	ListenAddrs      []string `json:"listenAddrs"`
+	BootstrapAddrs   []string `json:"bootstrapAddrs"`
+	DirectPeersAddrs []string `json:"directPeersAddrs"`
+	BlockedAddrs     []string `json:"blockedAddrs"`
+	DisableDiscovery bool     `json:"disableDiscovery"`
+}
+
+type Ghost struct {
+	Interval int                `json:"interval"`
+	Workers  int                `json:"workers"`
+	Pairs    []ghostConfig.Pair `json:"pairs"`
+	Stark    Stark              `json:"stark"`
+}
+
+type Stark struct {
+	// KeyFile is the path to a file with the hex encoded Stark private key.
+	// If set, prices are signed with both the Ethereum and Stark keys.
+	KeyFile string `json:"keyFile"`
+}
+
+type Spire struct {
+	RPC   RPC      `json:"rpc"`
+	Pairs []string `json:"pairs"`
+	// Datastore has the same structure as the datastore section of
+	// the Spire's config file.
+	Datastore spireConfig.Datastore `json:"datastore"`
+}
+
+type RPC struct {
+	// Address is the TCP address of the agent, or the path to the Unix
+	// domain socket prefixed with "unix:".
+	Address string `json:"address"`
+	// Token is the bearer token required by the agent and sent by clients.
+	// If empty, the authentication is disabled.
+	Token string     `json:"token"`
+	TLS   rpcsec.TLS `json:"tls"`
+}
+
+type Health struct {
+	// Address is the address on which the /healthz and /readyz endpoints are
+	// served. If empty, the endpoints are disabled.
+	Address string `json:"address"`
+}
+
+type Dependencies struct {
+	Context context.Context
+	Logger  log.Logger
+}
+
+type Instances struct {
+	Gofer     gofer.Gofer
+	Signer    ethereum.Signer
+	Transport transport.Transport
+	Node      *feednode.Node
+	// Health is the server for the health endpoints, it is nil if
+	// the endpoints are disabled.
+	Health *health.Server
+}
+
+// Configure creates the Gofer instance, the signer and the transport once
+// and builds Ghost and the Spire agent on top of them.
+func (c *Config) Configure(deps Dependencies) (*Instances, error) {
+	// Gofer:
+	gof, err := c.Gofer.ConfigureGofer(deps.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
+
+	// Signer for the Ethereum account:
+	sig, err := c.configureSigner()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
+
+	// Feeder monitor:
+	pairs := c.datastorePairs()
+	mon := datastore.NewMonitor(datastore.MonitorConfig{
+		Window: time.Second * time.Duration(c.Spire.Datastore.MonitorWindow),
+		Pairs:  pairs,
+	})
+
+	// Transport:
+	tra, err := c.configureTransport(deps.Context, sig, mon, deps.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
+	shared := feednode.SharedTransport{Transport: tra}
+
+	// Ghost:
+	gho, err := c.configureGhost(gof, sig, shared, deps.Logger)
+	if err != nil {
+		_ = tra.Close()
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
+
+	// Datastore:
+	dat, err := c.configureDatastore(sig, shared, pairs, mon, deps.Logger)
+	if err != nil {
+		_ = tra.Close()
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
+
+	// Spire's RPC Agent:
+	tls, err := c.Spire.RPC.TLS.ServerConfig()
+	if err != nil {
+		_ = tra.Close()
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
+	network, address := rpcsec.ParseAddress(c.Spire.RPC.Address)
+	srv, err := spire.NewAgent(spire.AgentConfig{
+		Datastore: dat,
+		Monitor:   mon,
+		Transport: shared,
+		Signer:    sig,
+		Network:   network,
+		Address:   address,
+		Security:  rpcsec.ServerConfig{TLS: tls, Token: c.Spire.RPC.Token},
+		Logger:    deps.Logger,
+	})
+	if err != nil {
+		_ = tra.Close()
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
+
+	// Health endpoints:
+	checkers := map[string]health.Checker{
+		"ghost":     gho,
+		"agent":     srv,
+		"transport": tra,
+	}
+	if hc, ok := gof.(health.Checker); ok {
+		checkers["gofer"] = hc
+	}
+	hs := c.configureHealthServer(checkers, deps.Logger)
+
+	return &Instances{
+		Gofer:     gof,
+		Signer:    sig,
+		Transport: tra,
+		Node: feednode.NewNode(feednode.Config{
+			Ghost:     gho,
+			Agent:     srv,
+			Transport: tra,
+			Logger:    deps.Logger,
+		}),
+		Health: hs,
+	}, nil
+}
+
+func (c *Config) configureHealthServer(checkers map[string]health.Checker, l log.Logger) *health.Server {
+	if c.Health.Address == "" {
+		return nil
+	}
+	return health.NewServer(health.Config{
+		Checkers: checkers,
+		Network:  "tcp",
+		Address:  c.Health.Address,
+		Logger:   l,
+	})
+}
+
+func (c *Config) configureAccount() (*geth.Account, error) {
+	passphrase, err := shared.ReadAccountPassphrase(c.Ethereum.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := geth.NewAccount(
+		c.Ethereum.Keystore,
+		passphrase,
+		ethereum.HexToAddress(c.Ethereum.From),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (c *Config) configureSigner() (ethereum.Signer, error) {
+	if c.Ethereum.RemoteSigner != "" {
+		return geth.DialRemoteSigner(c.Ethereum.RemoteSigner, ethereum.HexToAddress(c.Ethereum.From))
+	}
+	a, err := c.configureAccount()
+	if err != nil {
+		return nil, err
+	}
+	return geth.NewSigner(a), nil
+}
+
+func (c *Config) configureTransport(
+	ctx context.Context,
+	s ethereum.Signer,
+	r p2p.PriceRejectionRecorder,
+	l log.Logger,
+) (*p2p.P2P, error) {
+
+	peerPrivKey, err := shared.GeneratePrivKey(c.P2P.PrivKeySeed)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := p2p.Config{
+		Context:          ctx,
+		PeerPrivKey:      peerPrivKey,
+		MessagePrivKey:   ethkey.NewPrivKey(s),
+		ListenAddrs:      c.P2P.ListenAddrs,
+		BootstrapAddrs:   c.P2P.BootstrapAddrs,
+		DirectPeersAddrs: c.P2P.DirectPeersAddrs,
+		BlockedAddrs:     c.P2P.BlockedAddrs,
+		Discovery:        !c.P2P.DisableDiscovery,
+		Signer:           s,
+		PriceRejections:  r,
+		Logger:           l,
+		AppName:          "feednode",
+		AppVersion:       suite.Version,
+	}
+	cfg.FeedersAddrs = []ethereum.Address{ethereum.HexToAddress(c.Ethereum.From)}
+	for _, feed := range c.Feeds {
+		cfg.FeedersAddrs = append(cfg.FeedersAddrs, ethereum.HexToAddress(feed))
+	}
+
+	p, err := p2p.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.Subscribe(messages.PriceMessageName, (*messages.Price)(nil))
+	if err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (c *Config) configureGhost(
+	g gofer.Gofer,
+	s ethereum.Signer,
+	t transport.Transport,
+	l log.Logger,
+) (*ghost.Ghost, error) {
+
+	ss, err := c.configureStarkSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := ghost.Config{
+		Gofer:       g,
+		Signer:      s,
+		StarkSigner: ss,
+		Transport:   t,
+		Logger:      l,
+		Interval:    time.Second * time.Duration(c.Ghost.Interval),
+		Workers:     c.Ghost.Workers,
+		Pairs:       nil,
+	}
+
+	for _, pair := range c.Ghost.Pairs {
+		cfg.Pairs = append(cfg.Pairs, &ghost.Pair{
+			AssetPair:    pair.Pair,
+			Spread:       pair.Spread,
+			Heartbeat:    time.Second * time.Duration(pair.Heartbeat),
+			MaxAge:       time.Second * time.Duration(pair.MaxAge),
+			MinSources:   pair.MinSources,
+			MaxMove:      pair.MaxMove,
+			MaxMoveTicks: pair.MaxMoveTicks,
+		})
+	}
+
+	return ghost.NewGhost(cfg)
+}
+
+func (c *Config) configureDatastore(
+	s ethereum.Signer,
+	t transport.Transport,
+	p map[string]*datastore.Pair,
+	m *datastore.Monitor,
+	l log.Logger,
+) (*datastore.Datastore, error) {
+
+	cfg := datastore.Config{
+		Signer:            s,
+		Transport:         t,
+		Pairs:             p,
+		StorageExpiration: time.Second * time.Duration(c.Spire.Datastore.Expiration),
+		History: datastore.HistoryConfig{
+			Size:     c.Spire.Datastore.HistorySize,
+			Duration: time.Second * time.Duration(c.Spire.Datastore.HistoryDuration),
+		},
+		Monitor: m,
+		Logger:  l,
+	}
+	if c.Spire.Datastore.Path != "" {
+		storage, err := datastore.NewFileStorage(c.Spire.Datastore.Path)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Storage = storage
+	}
+
+	return datastore.NewDatastore(cfg), nil
+}
+
+// datastorePairs returns the asset pairs collected by the Spire agent with
+// the list of feeds from which prices are accepted. As in the transport,
+// prices broadcast by the node's own Ghost are always accepted.
+func (c *Config) datastorePairs() map[string]*datastore.Pair {
+	from := ethereum.HexToAddress(c.Ethereum.From)
+	feeds := []ethereum.Address{from}
+	for _, feed := range c.Feeds {
+		if addr := ethereum.HexToAddress(feed); addr != from {
+			feeds = append(feeds, addr)
+		}
+	}
+
+	pairs := make(map[string]*datastore.Pair)
+	for _, pair := range c.Spire.Pairs {
+		pairs[pair] = &datastore.Pair{Feeds: feeds}
+	}
+	return pairs
+}
+
+// configureStarkSigner returns nil if the Stark key file is not set.
+func (c *Config) configureStarkSigner() (stark.Signer, error) {
+	if c.Ghost.Stark.KeyFile == "" {
+		return nil, nil
+	}
+	b, err := ioutil.ReadFile(c.Ghost.Stark.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrFailedToReadStarkKeyFile, err)
+	}
+	key, ok := new(big.Int).SetString(strings.TrimPrefix(strings.TrimSpace(string(b)), "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("%v: invalid hex number", ErrFailedToReadStarkKeyFile)
+	}
+	sig, err := stark.NewSigner(key)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrFailedToReadStarkKeyFile, err)
+	}
+	return sig, nil
+}
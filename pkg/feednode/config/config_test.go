@@ -0,0 +1,41 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+)
+
+func TestConfig_datastorePairs(t *testing.T) {
+	from := "0x2d800d93b065ce011af83f316cef9f0d005b0aa4"
+	feed := "0x9d800d93b065ce011af83f316cef9f0d005b0aa4"
+	config := Config{
+		Ethereum: Ethereum{From: from},
+		Feeds:    []string{feed, from},
+		Spire:    Spire{Pairs: []string{"AB", "CD"}},
+	}
+
+	// The node's own address is accepted even if it is not listed in feeds:
+	pairs := config.datastorePairs()
+	assert.Len(t, pairs, 2)
+	for _, pair := range pairs {
+		assert.Equal(t, []ethereum.Address{ethereum.HexToAddress(from), ethereum.HexToAddress(feed)}, pair.Feeds)
+	}
+}
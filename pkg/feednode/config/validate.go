@@ -0,0 +1,123 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"github.com/makerdao/oracle-suite/pkg/config/shared"
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/ghost"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/log/null"
+)
+
+// Validate checks the configuration and returns validation.Errors with all
+// problems found, or nil if the configuration is valid. Ghost's pairs are
+// checked against Gofer's price models only if the gofer section is valid.
+func (c *Config) Validate() error {
+	var errs validation.Errors
+
+	goferErr := validation.Prefix(c.Gofer.Validate(), "gofer")
+
+	shared.ValidateEthereum(&errs, c.Ethereum.From, c.Ethereum.Password)
+	shared.ValidateP2P(&errs, c.P2P.PrivKeySeed, shared.P2PAddrs{
+		ListenAddrs:      c.P2P.ListenAddrs,
+		BootstrapAddrs:   c.P2P.BootstrapAddrs,
+		DirectPeersAddrs: c.P2P.DirectPeersAddrs,
+		BlockedAddrs:     c.P2P.BlockedAddrs,
+	})
+
+	for i, feed := range c.Feeds {
+		if !ethereum.IsHexAddress(feed) {
+			errs.Add(validation.Pointer("feeds", i), "invalid feed address %s", feed)
+		}
+	}
+
+	if c.Ghost.Interval <= 0 {
+		errs.Add(validation.Pointer("ghost", "interval"), "interval must be greater than zero")
+	}
+	if c.Ghost.Workers < 0 {
+		errs.Add(validation.Pointer("ghost", "workers"), "workers must not be negative")
+	}
+	for i, pair := range c.Ghost.Pairs {
+		if pair.Spread < 0 {
+			errs.Add(validation.Pointer("ghost", "pairs", i, "spread"), "spread must not be negative")
+		}
+		if pair.Heartbeat < 0 {
+			errs.Add(validation.Pointer("ghost", "pairs", i, "heartbeat"), "heartbeat must not be negative")
+		}
+		if pair.MaxAge < 0 {
+			errs.Add(validation.Pointer("ghost", "pairs", i, "maxAge"), "maxAge must not be negative")
+		}
+		if pair.MinSources < 0 {
+			errs.Add(validation.Pointer("ghost", "pairs", i, "minSources"), "minSources must not be negative")
+		}
+		if pair.MaxMove < 0 {
+			errs.Add(validation.Pointer("ghost", "pairs", i, "maxMove"), "maxMove must not be negative")
+		}
+		if pair.MaxMoveTicks < 0 {
+			errs.Add(validation.Pointer("ghost", "pairs", i, "maxMoveTicks"), "maxMoveTicks must not be negative")
+		}
+	}
+	if _, err := c.configureStarkSigner(); err != nil {
+		errs.Add(validation.Pointer("ghost", "stark", "keyFile"), "%s", err)
+	}
+	if goferErr == nil {
+		c.validateGhostPairs(&errs)
+	}
+
+	if c.Spire.RPC.Address == "" {
+		errs.Add(validation.Pointer("spire", "rpc", "address"), "RPC address is required")
+	}
+	c.Spire.RPC.TLS.ValidateAgent(&errs, "spire", "rpc", "tls")
+	for i, pair := range c.Spire.Pairs {
+		if pair == "" {
+			errs.Add(validation.Pointer("spire", "pairs", i), "pair name must not be empty")
+		}
+	}
+	c.Spire.Datastore.Validate(&errs, "spire", "datastore")
+
+	return validation.Join(goferErr, errs.Err())
+}
+
+func (c *Config) validateGhostPairs(errs *validation.Errors) {
+	gof, err := c.Gofer.ConfigureGofer(null.New())
+	if err != nil {
+		errs.Add(validation.Pointer("gofer"), "%s", err)
+		return
+	}
+	goferPairs, err := gof.Pairs()
+	if err != nil {
+		errs.Add(validation.Pointer("ghost", "pairs"), "unable to fetch pairs from Gofer: %s", err)
+		return
+	}
+	for i, pair := range c.Ghost.Pairs {
+		if !hasPair(goferPairs, pair.Pair) {
+			errs.Add(validation.Pointer("ghost", "pairs", i), "%s", ghost.ErrUnableToFindAsset{AssetName: pair.Pair})
+		}
+	}
+}
+
+// hasPair checks if the list contains a pair with the given name. Names are
+// written without a separator, the same way as in Ghost's config.
+func hasPair(pairs []gofer.Pair, name string) bool {
+	for _, p := range pairs {
+		if p.Base+p.Quote == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+	ghostConfig "github.com/makerdao/oracle-suite/pkg/ghost/config"
+	goferConfig "github.com/makerdao/oracle-suite/pkg/gofer/config"
+	spireConfig "github.com/makerdao/oracle-suite/pkg/spire/config"
+)
+
+func testGoferConfig() goferConfig.Config {
+	return goferConfig.Config{
+		PriceModels: map[string]goferConfig.PriceModel{
+			"A/B": {
+				Method:  "median",
+				Sources: [][]goferConfig.Source{{{Origin: "binance", Pair: "A/B"}}},
+			},
+		},
+	}
+}
+
+func TestConfig_Validate_ValidConfig(t *testing.T) {
+	config := Config{
+		Ethereum: Ethereum{From: "0x2d800d93b065ce011af83f316cef9f0d005b0aa4"},
+		Feeds:    []string{"0x2d800d93b065ce011af83f316cef9f0d005b0aa4"},
+		Gofer:    testGoferConfig(),
+		Ghost:    Ghost{Interval: 60, Pairs: []ghostConfig.Pair{{Pair: "AB"}}},
+		Spire:    Spire{RPC: RPC{Address: "127.0.0.1:9100"}, Pairs: []string{"AB"}},
+	}
+
+	assert.NoError(t, config.Validate())
+}
+
+func TestConfig_Validate_InvalidConfig(t *testing.T) {
+	config := Config{
+		Ethereum: Ethereum{From: "0x2d800d93b065ce011af83f316cef9f0d005b0aa4"},
+		Gofer:    testGoferConfig(),
+		Ghost: Ghost{
+			Interval: 0,
+			Pairs:    []ghostConfig.Pair{{Pair: "AB", Spread: -1}, {Pair: "CD"}},
+			Stark:    Stark{KeyFile: "/nonexistent/stark.key"},
+		},
+		Spire: Spire{Pairs: []string{""}, Datastore: spireConfig.Datastore{HistorySize: -1}},
+	}
+
+	var errs validation.Errors
+	require.True(t, errors.As(config.Validate(), &errs))
+
+	var pointers []string
+	for _, e := range errs {
+		pointers = append(pointers, e.Pointer)
+	}
+	assert.Equal(t, []string{
+		"/ghost/interval",
+		"/ghost/pairs/0/spread",
+		"/ghost/stark/keyFile",
+		"/ghost/pairs/1",
+		"/spire/rpc/address",
+		"/spire/pairs/0",
+		"/spire/datastore/historySize",
+	}, pointers)
+}
+
+func TestConfig_Validate_InvalidGoferConfig(t *testing.T) {
+	gof := testGoferConfig()
+	gof.PriceModels["A/B"] = goferConfig.PriceModel{Method: "mean"}
+	config := Config{
+		Ethereum: Ethereum{From: "0x2d800d93b065ce011af83f316cef9f0d005b0aa4"},
+		Gofer:    gof,
+		Ghost:    Ghost{Interval: 60, Pairs: []ghostConfig.Pair{{Pair: "CD"}}},
+		Spire:    Spire{RPC: RPC{Address: "127.0.0.1:9100"}},
+	}
+
+	var errs validation.Errors
+	require.True(t, errors.As(config.Validate(), &errs))
+
+	// Ghost's pairs are not checked if the gofer section is invalid:
+	for _, e := range errs {
+		assert.Contains(t, e.Pointer, "/gofer/priceModels/A~1B")
+	}
+}
@@ -0,0 +1,104 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package feednode
+
+import (
+	"github.com/makerdao/oracle-suite/pkg/ghost"
+	"github.com/makerdao/oracle-suite/pkg/log"
+	"github.com/makerdao/oracle-suite/pkg/spire"
+	"github.com/makerdao/oracle-suite/pkg/transport"
+)
+
+const LoggerTag = "FEED_NODE"
+
+// SharedTransport wraps a transport which is shared between multiple
+// services. Services unsubscribe from their topics when they are stopped,
+// which would break other services subscribed to the same topics, so
+// the Unsubscribe method does nothing here. The underlying transport is
+// closed by the Node.
+type SharedTransport struct {
+	transport.Transport
+}
+
+// Unsubscribe implements the transport.Transport interface.
+func (SharedTransport) Unsubscribe(string) error {
+	return nil
+}
+
+// Node runs Ghost and the Spire agent in a single process. Both services
+// use the same Gofer instance, signer and transport.
+type Node struct {
+	ghost     *ghost.Ghost
+	agent     *spire.Agent
+	transport transport.Transport
+	log       log.Logger
+}
+
+type Config struct {
+	// Ghost broadcasts prices from the Gofer instance.
+	Ghost *ghost.Ghost
+	// Agent is the Spire agent which collects prices from the network.
+	Agent *spire.Agent
+	// Transport is the transport used by Ghost and the Spire agent. It
+	// will be closed when the Node is stopped.
+	Transport transport.Transport
+	Logger    log.Logger
+}
+
+func NewNode(cfg Config) *Node {
+	return &Node{
+		ghost:     cfg.Ghost,
+		agent:     cfg.Agent,
+		transport: cfg.Transport,
+		log:       cfg.Logger.WithField("tag", LoggerTag),
+	}
+}
+
+// Start starts the Spire agent and then Ghost, so prices broadcast by
+// the node are also collected by its own datastore.
+func (n *Node) Start() error {
+	n.log.Infof("Starting")
+
+	err := n.agent.Start()
+	if err != nil {
+		return err
+	}
+
+	err = n.ghost.Start()
+	if err != nil {
+		n.agent.Stop()
+		return err
+	}
+
+	return nil
+}
+
+// Stop stops all services in the reverse order and closes the transport.
+func (n *Node) Stop() {
+	defer n.log.Infof("Stopped")
+
+	err := n.ghost.Stop()
+	if err != nil {
+		n.log.WithError(err).Error("Unable to stop Ghost")
+	}
+
+	n.agent.Stop()
+
+	err = n.transport.Close()
+	if err != nil {
+		n.log.WithError(err).Error("Unable to close transport")
+	}
+}
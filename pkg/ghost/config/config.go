@@ -16,25 +16,25 @@
 package config
 
 import (
-	"bytes"
 	"context"
-	"crypto/ed25519"
-	"crypto/rand"
-	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math/big"
 	"strings"
 	"time"
 
-	"github.com/libp2p/go-libp2p-core/crypto"
-
 	suite "github.com/makerdao/oracle-suite"
+	"github.com/makerdao/oracle-suite/pkg/config/shared"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
 	"github.com/makerdao/oracle-suite/pkg/ethereum/geth"
 	"github.com/makerdao/oracle-suite/pkg/ghost"
 	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
+	"github.com/makerdao/oracle-suite/pkg/stark"
 	"github.com/makerdao/oracle-suite/pkg/transport"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
 	"github.com/makerdao/oracle-suite/pkg/transport/p2p"
@@ -42,21 +42,29 @@ import (
 )
 
 var ErrFailedToLoadConfiguration = errors.New("failed to load Ghost's configuration")
-var ErrFailedToReadPassphraseFile = errors.New("failed to read the ethereum password file")
-var ErrFailedToParsePrivKeySeed = errors.New("failed to parse the privKeySeed field")
+var ErrFailedToReadPassphraseFile = shared.ErrFailedToReadPassphraseFile
+var ErrFailedToParsePrivKeySeed = shared.ErrFailedToParsePrivKeySeed
+var ErrFailedToReadStarkKeyFile = errors.New("failed to read the Stark key file")
 
 type Config struct {
 	Ethereum Ethereum `json:"ethereum"`
 	P2P      P2P      `json:"p2p"`
 	Options  Options  `json:"options"`
 	Feeds    []string `json:"feeds"`
-	Pairs    []string `json:"pairs"`
+	Pairs    []Pair   `json:"pairs"`
+	Stark    Stark    `json:"stark"`
+	Journal  Journal  `json:"journal"`
+	Health   Health   `json:"health"`
 }
 
 type Ethereum struct {
 	From     string `json:"from"`
 	Keystore string `json:"keystore"`
 	Password string `json:"password"`
+	// RemoteSigner is the address of an external signer, like Clef, which
+	// holds the key of the "from" account. If set, the keystore and
+	// password are not used.
+	RemoteSigner string `json:"remoteSigner"`
 }
 
 type P2P struct {
@@ -70,51 +78,144 @@ type P2P struct {
 
 type Options struct {
 	Interval int `json:"interval"`
+	// Workers is the number of prices signed concurrently. If zero,
+	// ghost.DefaultWorkers is used.
+	Workers int `json:"workers"`
+}
+
+// Pair is the configuration of a single pair. In the config file, a pair may
+// be given as an object or just as a name, in which case the price is
+// broadcast on every interval.
+type Pair struct {
+	// Pair is the name of asset pair, e.g. ETHUSD.
+	Pair string `json:"pair"`
+	// Spread is the minimum spread, in percent, between the last broadcast
+	// price and the current price required to broadcast a new price.
+	Spread float64 `json:"spread"`
+	// Heartbeat is the number of seconds after which the price is broadcast
+	// again even if it did not change.
+	Heartbeat int64 `json:"heartbeat"`
+	// MaxAge is the maximum age of a price in seconds. Older prices are not
+	// signed.
+	MaxAge int64 `json:"maxAge"`
+	// MinSources is the minimum number of origins which must contribute to
+	// a price.
+	MinSources int `json:"minSources"`
+	// MaxMove is the maximum difference, in percent, between the last
+	// broadcast price and the current price.
+	MaxMove float64 `json:"maxMove"`
+	// MaxMoveTicks is the number of consecutive ticks required to accept
+	// a price which moved more than MaxMove.
+	MaxMoveTicks int `json:"maxMoveTicks"`
+}
+
+func (p *Pair) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*p = Pair{Pair: name}
+		return nil
+	}
+	type pair Pair
+	return json.Unmarshal(b, (*pair)(p))
+}
+
+type Stark struct {
+	// KeyFile is the path to a file with the hex encoded Stark private key.
+	// If set, prices are signed with both the Ethereum and Stark keys.
+	KeyFile string `json:"keyFile"`
+}
+
+type Journal struct {
+	// Path is the path to the file in which every broadcast price is
+	// recorded. If empty, the journal is disabled.
+	Path string `json:"path"`
+}
+
+type Health struct {
+	// Address is the address on which the /healthz and /readyz endpoints are
+	// served. If empty, the endpoints are disabled.
+	Address string `json:"address"`
 }
 
 type Dependencies struct {
 	Context context.Context
 	Gofer   gofer.Gofer
 	Logger  log.Logger
+	// DryRun, if not nil, enables the dry-run mode in which messages are
+	// written to DryRun instead of being broadcast. The transport is not
+	// created in this mode.
+	DryRun io.Writer
 }
 
 type Instances struct {
-	Signer    ethereum.Signer
+	Signer ethereum.Signer
+	// Transport is nil in the dry-run mode.
 	Transport transport.Transport
 	Ghost     *ghost.Ghost
+	// Health is the server for the health endpoints, it is nil if
+	// the endpoints are disabled.
+	Health *health.Server
 }
 
 func (c *Config) Configure(deps Dependencies) (*Instances, error) {
-	// Create wallet for given account and keystore:
-	acc, err := c.configureAccount()
+	// Signer for the Ethereum account:
+	sig, err := c.configureSigner()
 	if err != nil {
 		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
 	}
 
-	// Create new signer instance:
-	sig := c.configureSigner(acc)
-
-	// Transport:
-	tra, err := c.configureTransport(deps.Context, sig, deps.Logger)
-	if err != nil {
-		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	// Transport, it is not used in the dry-run mode:
+	var tra *p2p.P2P
+	if deps.DryRun == nil {
+		tra, err = c.configureTransport(deps.Context, sig, deps.Logger)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+		}
 	}
 
 	// Create and configure Ghost:
-	gho, err := c.configureGhost(deps.Gofer, sig, tra, deps.Logger)
+	gho, err := c.configureGhost(deps.Gofer, sig, tra, deps.DryRun, deps.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
 	}
 
-	return &Instances{
-		Signer:    sig,
-		Transport: tra,
-		Ghost:     gho,
-	}, nil
+	// Health endpoints:
+	checkers := map[string]health.Checker{
+		"ghost": gho,
+	}
+	if tra != nil {
+		checkers["transport"] = tra
+	}
+	if hc, ok := deps.Gofer.(health.Checker); ok {
+		checkers["gofer"] = hc
+	}
+	hs := c.configureHealthServer(checkers, deps.Logger)
+
+	ins := &Instances{
+		Signer: sig,
+		Ghost:  gho,
+		Health: hs,
+	}
+	if tra != nil {
+		ins.Transport = tra
+	}
+	return ins, nil
+}
+
+func (c *Config) configureHealthServer(checkers map[string]health.Checker, l log.Logger) *health.Server {
+	if c.Health.Address == "" {
+		return nil
+	}
+	return health.NewServer(health.Config{
+		Checkers: checkers,
+		Network:  "tcp",
+		Address:  c.Health.Address,
+		Logger:   l,
+	})
 }
 
 func (c *Config) configureAccount() (*geth.Account, error) {
-	passphrase, err := c.readAccountPassphrase(c.Ethereum.Password)
+	passphrase, err := shared.ReadAccountPassphrase(c.Ethereum.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -131,12 +232,19 @@ func (c *Config) configureAccount() (*geth.Account, error) {
 	return a, nil
 }
 
-func (c *Config) configureSigner(a *geth.Account) ethereum.Signer {
-	return geth.NewSigner(a)
+func (c *Config) configureSigner() (ethereum.Signer, error) {
+	if c.Ethereum.RemoteSigner != "" {
+		return geth.DialRemoteSigner(c.Ethereum.RemoteSigner, ethereum.HexToAddress(c.Ethereum.From))
+	}
+	a, err := c.configureAccount()
+	if err != nil {
+		return nil, err
+	}
+	return geth.NewSigner(a), nil
 }
 
-func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l log.Logger) (transport.Transport, error) {
-	peerPrivKey, err := c.generatePrivKey()
+func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l log.Logger) (*p2p.P2P, error) {
+	peerPrivKey, err := shared.GeneratePrivKey(c.P2P.PrivKeySeed)
 	if err != nil {
 		return nil, err
 	}
@@ -177,54 +285,69 @@ func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l lo
 func (c *Config) configureGhost(
 	g gofer.Gofer,
 	s ethereum.Signer,
-	t transport.Transport,
+	t *p2p.P2P,
+	dryRun io.Writer,
 	l log.Logger,
 ) (*ghost.Ghost, error) {
 
+	ss, err := c.configureStarkSigner()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := ghost.Config{
-		Gofer:     g,
-		Signer:    s,
-		Transport: t,
-		Logger:    l,
-		Interval:  time.Second * time.Duration(c.Options.Interval),
-		Pairs:     nil,
+		Gofer:       g,
+		Signer:      s,
+		StarkSigner: ss,
+		DryRun:      dryRun,
+		Logger:      l,
+		Interval:    time.Second * time.Duration(c.Options.Interval),
+		Workers:     c.Options.Workers,
+		Pairs:       nil,
+	}
+
+	// A nil *p2p.P2P must not be assigned to the interface:
+	if t != nil {
+		cfg.Transport = t
+	}
+	if c.Journal.Path != "" {
+		cfg.Journal, err = ghost.NewFileJournal(c.Journal.Path)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	for _, name := range c.Pairs {
+	for _, pair := range c.Pairs {
 		cfg.Pairs = append(cfg.Pairs, &ghost.Pair{
-			AssetPair: name,
+			AssetPair:    pair.Pair,
+			Spread:       pair.Spread,
+			Heartbeat:    time.Second * time.Duration(pair.Heartbeat),
+			MaxAge:       time.Second * time.Duration(pair.MaxAge),
+			MinSources:   pair.MinSources,
+			MaxMove:      pair.MaxMove,
+			MaxMoveTicks: pair.MaxMoveTicks,
 		})
 	}
 
 	return ghost.NewGhost(cfg)
 }
 
-func (c *Config) generatePrivKey() (crypto.PrivKey, error) {
-	seedReader := rand.Reader
-	if len(c.P2P.PrivKeySeed) != 0 {
-		seed, err := hex.DecodeString(c.P2P.PrivKeySeed)
-		if err != nil {
-			return nil, fmt.Errorf("%v: %v", ErrFailedToParsePrivKeySeed, err)
-		}
-		if len(seed) != ed25519.SeedSize {
-			return nil, fmt.Errorf("%v: seed must be 32 bytes", ErrFailedToParsePrivKeySeed)
-		}
-		seedReader = bytes.NewReader(seed)
+// configureStarkSigner returns nil if the Stark key file is not set.
+func (c *Config) configureStarkSigner() (stark.Signer, error) {
+	if c.Stark.KeyFile == "" {
+		return nil, nil
 	}
-	privKey, _, err := crypto.GenerateEd25519Key(seedReader)
+	b, err := ioutil.ReadFile(c.Stark.KeyFile)
 	if err != nil {
-		return nil, fmt.Errorf("%v: %v", ErrFailedToParsePrivKeySeed, err)
+		return nil, fmt.Errorf("%v: %v", ErrFailedToReadStarkKeyFile, err)
 	}
-	return privKey, nil
-}
-
-func (c *Config) readAccountPassphrase(path string) (string, error) {
-	if path == "" {
-		return "", nil
+	key, ok := new(big.Int).SetString(strings.TrimPrefix(strings.TrimSpace(string(b)), "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("%v: invalid hex number", ErrFailedToReadStarkKeyFile)
 	}
-	passphraseFile, err := ioutil.ReadFile(path)
+	sig, err := stark.NewSigner(key)
 	if err != nil {
-		return "", fmt.Errorf("%v: %v", ErrFailedToReadPassphraseFile, err)
+		return nil, fmt.Errorf("%v: %v", ErrFailedToReadStarkKeyFile, err)
 	}
-	return strings.TrimSuffix(string(passphraseFile), "\n"), nil
+	return sig, nil
 }
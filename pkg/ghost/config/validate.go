@@ -0,0 +1,100 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"github.com/makerdao/oracle-suite/pkg/config/shared"
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/ghost"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+)
+
+// Validate checks the configuration and returns validation.Errors with all
+// problems found, or nil if the configuration is valid. If the Gofer instance
+// is not nil, then it is also checked if every pair has a matching price
+// model.
+func (c *Config) Validate(gof gofer.Gofer) error {
+	var errs validation.Errors
+
+	shared.ValidateEthereum(&errs, c.Ethereum.From, c.Ethereum.Password)
+	shared.ValidateP2P(&errs, c.P2P.PrivKeySeed, shared.P2PAddrs{
+		ListenAddrs:      c.P2P.ListenAddrs,
+		BootstrapAddrs:   c.P2P.BootstrapAddrs,
+		DirectPeersAddrs: c.P2P.DirectPeersAddrs,
+		BlockedAddrs:     c.P2P.BlockedAddrs,
+	})
+
+	if c.Options.Interval <= 0 {
+		errs.Add(validation.Pointer("options", "interval"), "interval must be greater than zero")
+	}
+	if c.Options.Workers < 0 {
+		errs.Add(validation.Pointer("options", "workers"), "workers must not be negative")
+	}
+	for i, feed := range c.Feeds {
+		if !ethereum.IsHexAddress(feed) {
+			errs.Add(validation.Pointer("feeds", i), "invalid feed address %s", feed)
+		}
+	}
+
+	if _, err := c.configureStarkSigner(); err != nil {
+		errs.Add(validation.Pointer("stark", "keyFile"), "%s", err)
+	}
+
+	var goferPairs []gofer.Pair
+	if gof != nil {
+		var err error
+		if goferPairs, err = gof.Pairs(); err != nil {
+			errs.Add(validation.Pointer("pairs"), "unable to fetch pairs from Gofer: %s", err)
+			gof = nil
+		}
+	}
+	for i, pair := range c.Pairs {
+		if pair.Spread < 0 {
+			errs.Add(validation.Pointer("pairs", i, "spread"), "spread must not be negative")
+		}
+		if pair.Heartbeat < 0 {
+			errs.Add(validation.Pointer("pairs", i, "heartbeat"), "heartbeat must not be negative")
+		}
+		if pair.MaxAge < 0 {
+			errs.Add(validation.Pointer("pairs", i, "maxAge"), "maxAge must not be negative")
+		}
+		if pair.MinSources < 0 {
+			errs.Add(validation.Pointer("pairs", i, "minSources"), "minSources must not be negative")
+		}
+		if pair.MaxMove < 0 {
+			errs.Add(validation.Pointer("pairs", i, "maxMove"), "maxMove must not be negative")
+		}
+		if pair.MaxMoveTicks < 0 {
+			errs.Add(validation.Pointer("pairs", i, "maxMoveTicks"), "maxMoveTicks must not be negative")
+		}
+		if gof == nil {
+			continue
+		}
+		found := false
+		for _, goferPair := range goferPairs {
+			if goferPair.Base+goferPair.Quote == pair.Pair {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs.Add(validation.Pointer("pairs", i), "%s", ghost.ErrUnableToFindAsset{AssetName: pair.Pair})
+		}
+	}
+
+	return errs.Err()
+}
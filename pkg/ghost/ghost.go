@@ -16,22 +16,33 @@
 package ghost
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"sync"
 	"time"
 
 	"github.com/makerdao/oracle-suite/internal/gofer/marshal"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
 	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
 	"github.com/makerdao/oracle-suite/pkg/oracle"
+	"github.com/makerdao/oracle-suite/pkg/stark"
 	"github.com/makerdao/oracle-suite/pkg/transport"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
 )
 
 const LoggerTag = "GHOST"
 
+// DefaultWorkers is the default number of prices signed concurrently.
+const DefaultWorkers = 4
+
 type ErrUnableToFindAsset struct {
 	AssetName string
 }
@@ -41,13 +52,42 @@ func (e ErrUnableToFindAsset) Error() string {
 }
 
 type Ghost struct {
-	gofer     gofer.Gofer
-	signer    ethereum.Signer
-	transport transport.Transport
-	interval  time.Duration
-	pairs     map[gofer.Pair]*Pair
-	log       log.Logger
-	doneCh    chan struct{}
+	mu sync.RWMutex
+
+	gofer       gofer.Gofer
+	signer      ethereum.Signer
+	starkSigner stark.Signer
+	transport   transport.Transport
+	interval    time.Duration
+	pairs       map[gofer.Pair]*Pair
+	workers     int
+	dryRun      io.Writer
+	journal     Journal
+	log         log.Logger
+	doneCh      chan struct{}
+
+	// wg is used to wait for the broadcaster loop, so the journal is not
+	// closed while prices are still being broadcast.
+	wg sync.WaitGroup
+
+	// dryRunMu guards writes to the dryRun writer.
+	dryRunMu sync.Mutex
+
+	// lastBroadcast contains the last successfully broadcast price for
+	// each pair.
+	lastBroadcast map[gofer.Pair]broadcastRecord
+
+	// moves contains price moves rejected by the maxMove guard, which are
+	// not confirmed yet.
+	moves map[gofer.Pair]*pendingMove
+}
+
+// broadcastRecord describes the last price broadcast for a pair.
+type broadcastRecord struct {
+	// Time is the time of the broadcast.
+	Time time.Time
+	// Val is the broadcast price.
+	Val *big.Int
 }
 
 type Config struct {
@@ -57,11 +97,25 @@ type Config struct {
 	// Signer is an instance of the ethereum.Signer which will be used to
 	// sign prices.
 	Signer ethereum.Signer
+	// StarkSigner is an optional signer used to add a Stark signature to
+	// prices. If nil, prices are signed only with the Ethereum signature.
+	StarkSigner stark.Signer
 	// Transport is a implementation of transport used to send prices to
-	// relayers.
+	// relayers. It may be nil in the dry-run mode.
 	Transport transport.Transport
+	// DryRun, if not nil, enables the dry-run mode. In this mode, prices are
+	// fetched and signed as usual, but messages are written to DryRun as
+	// JSON lines instead of being sent to the network.
+	DryRun io.Writer
+	// Journal is an optional journal in which every broadcast price is
+	// recorded. If the journal implements io.Closer, it is closed when Ghost
+	// is stopped.
+	Journal Journal
 	// Interval describes how often we should send prices to the network.
 	Interval time.Duration
+	// Workers is the maximum number of prices signed and broadcast
+	// concurrently. If zero, DefaultWorkers is used.
+	Workers int
 	// Logger is a current logger interface used by the Ghost. The Logger
 	// helps to monitor asynchronous processes.
 	Logger log.Logger
@@ -72,17 +126,49 @@ type Config struct {
 type Pair struct {
 	// AssetPair is the name of asset pair, e.g. ETHUSD.
 	AssetPair string
+	// Spread is the minimum spread, in percent, between the last broadcast
+	// price and the current price required to broadcast a new price.
+	Spread float64
+	// Heartbeat is the maximum time after which the price is broadcast
+	// again even if it did not change. If zero, the price is broadcast on
+	// every interval.
+	Heartbeat time.Duration
+	// MaxAge is the maximum age of a price. Older prices are not signed.
+	// If zero, the age is not checked.
+	MaxAge time.Duration
+	// MinSources is the minimum number of origins which must contribute
+	// to a price. If zero, the number of origins is not checked.
+	MinSources int
+	// MaxMove is the maximum difference, in percent, between the last
+	// broadcast price and the current price. Prices which moved more are not
+	// signed until the move is confirmed by MaxMoveTicks consecutive ticks.
+	// If zero, the difference is not checked.
+	MaxMove float64
+	// MaxMoveTicks is the number of consecutive ticks, which differ by no
+	// more than MaxMove from each other, required to accept a price which
+	// moved more than MaxMove. If zero, DefaultMaxMoveTicks is used.
+	MaxMoveTicks int
 }
 
 func NewGhost(config Config) (*Ghost, error) {
 	g := &Ghost{
-		gofer:     config.Gofer,
-		signer:    config.Signer,
-		transport: config.Transport,
-		interval:  config.Interval,
-		pairs:     make(map[gofer.Pair]*Pair),
-		log:       config.Logger.WithField("tag", LoggerTag),
-		doneCh:    make(chan struct{}),
+		gofer:       config.Gofer,
+		signer:      config.Signer,
+		starkSigner: config.StarkSigner,
+		transport:   config.Transport,
+		interval:    config.Interval,
+		workers:     config.Workers,
+		dryRun:      config.DryRun,
+		journal:     config.Journal,
+		pairs:       make(map[gofer.Pair]*Pair),
+		log:         config.Logger.WithField("tag", LoggerTag),
+		doneCh:      make(chan struct{}),
+
+		lastBroadcast: make(map[gofer.Pair]broadcastRecord),
+		moves:         make(map[gofer.Pair]*pendingMove),
+	}
+	if g.workers <= 0 {
+		g.workers = DefaultWorkers
 	}
 
 	// Unfortunately, the Gofer stores pairs in the AAA/BBB format but Ghost
@@ -126,6 +212,15 @@ func (g *Ghost) Stop() error {
 	defer g.log.Infof("Stopped")
 
 	close(g.doneCh)
+	g.wg.Wait()
+	if c, ok := g.journal.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			g.log.WithError(err).Warn("Unable to close journal")
+		}
+	}
+	if g.transport == nil {
+		return nil
+	}
 	err := g.transport.Unsubscribe(messages.PriceMessageName)
 	if err != nil {
 		return err
@@ -134,43 +229,240 @@ func (g *Ghost) Stop() error {
 	return nil
 }
 
-// broadcast sends price for single pair to the network. This method uses
-// current price from the Gofer so it must be updated beforehand.
-func (g *Ghost) broadcast(goferPair gofer.Pair) error {
+// HealthStatus implements the health.Checker interface. Ghost is ready if
+// a price for at least one pair was broadcast during the last two intervals.
+func (g *Ghost) HealthStatus() health.Status {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ready := false
+	lastBroadcast := make(map[string]interface{}, len(g.pairs))
+	for goferPair, pair := range g.pairs {
+		b, ok := g.lastBroadcast[goferPair]
+		if !ok {
+			lastBroadcast[pair.AssetPair] = nil
+			continue
+		}
+		lastBroadcast[pair.AssetPair] = b.Time
+		// Prices are not broadcast if they did not change, so the heartbeat
+		// is also taken into account:
+		if time.Since(b.Time) <= 2*g.interval+pair.Heartbeat {
+			ready = true
+		}
+	}
+	return health.Status{
+		Ready:   ready,
+		Details: map[string]interface{}{"lastBroadcast": lastBroadcast},
+	}
+}
+
+// broadcast sends price for single pair to the network. The tick is the
+// price fetched from the Gofer during the cycle with the given ID. It'll
+// return the broadcast price or nil if there is no need to broadcast
+// the price, because it did not change enough since the last broadcast and
+// the heartbeat did not elapse.
+func (g *Ghost) broadcast(cycleID string, goferPair gofer.Pair, tick *gofer.Price) (*oracle.Price, error) {
 	var err error
 
 	pair := g.pairs[goferPair]
-	tick, err := g.gofer.Price(goferPair)
-	if err != nil {
-		return err
+	if tick == nil {
+		return nil, errors.New("price is missing in the Gofer response")
 	}
 	if tick.Error != "" {
-		return errors.New(tick.Error)
+		return nil, errors.New(tick.Error)
 	}
 
 	// Create price:
 	price := &oracle.Price{Wat: pair.AssetPair, Age: tick.Time}
 	price.SetFloat64Price(tick.Price)
 
+	// Check if the price should be broadcast:
+	g.mu.RLock()
+	last, ok := g.lastBroadcast[goferPair]
+	g.mu.RUnlock()
+	if ok {
+		spread := calcSpread(last.Val, price.Val)
+		isExpired := !last.Time.Add(pair.Heartbeat).After(time.Now())
+		isStale := spread >= pair.Spread
+
+		g.log.
+			WithFields(log.Fields{
+				"cycleID":       cycleID,
+				"assetPair":     pair.AssetPair,
+				"expired":       isExpired,
+				"stale":         isStale,
+				"heartbeat":     pair.Heartbeat.String(),
+				"spread":        pair.Spread,
+				"currentSpread": spread,
+			}).
+			Debug("Checking if price should be broadcast")
+
+		if !isExpired && !isStale {
+			// The price is close to the last broadcast one, so a move
+			// rejected earlier did not last:
+			g.mu.Lock()
+			delete(g.moves, goferPair)
+			g.mu.Unlock()
+			return nil, nil
+		}
+	}
+
+	// Check if the price is safe to sign:
+	var lastRecord *broadcastRecord
+	if ok {
+		lastRecord = &last
+	}
+	g.mu.Lock()
+	move, err := checkGuards(pair, tick, price.Val, lastRecord, g.moves[goferPair], time.Now())
+	if move != nil {
+		g.moves[goferPair] = move
+	} else {
+		delete(g.moves, goferPair)
+	}
+	g.mu.Unlock()
+	if err != nil {
+		if e, ok := err.(ErrPriceRejected); ok {
+			rejectedPrices.WithLabelValues(pair.AssetPair, e.Guard).Inc()
+		}
+		return nil, err
+	}
+
 	// Sign price:
 	err = price.Sign(g.signer)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if g.starkSigner != nil {
+		err = price.SignStark(g.starkSigner)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Broadcast price to P2P network:
-	message, err := createPriceMessage(price, tick)
+	message, err := createPriceMessage(cycleID, price, tick)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if g.dryRun != nil {
+		err = g.writeDryRun(message)
+	} else {
+		err = g.transport.Broadcast(messages.PriceMessageName, message)
 	}
-	err = g.transport.Broadcast(messages.PriceMessageName, message)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// Prices written in the dry-run mode were not sent to the network, so
+	// they must not affect the spread, heartbeat and guard checks:
+	now := time.Now()
+	if g.dryRun == nil {
+		g.mu.Lock()
+		g.lastBroadcast[goferPair] = broadcastRecord{Time: now, Val: price.Val}
+		g.mu.Unlock()
+	}
+
+	g.record(JournalEntry{
+		Time:      now,
+		Pair:      pair.AssetPair,
+		Val:       price.Val.String(),
+		Age:       price.Age.Unix(),
+		TraceHash: hex.EncodeToString(ethereum.SHA3Hash(message.Trace)),
+		CycleID:   cycleID,
+		DryRun:    g.dryRun != nil,
+	})
+
+	return price, nil
+}
+
+// writeDryRun writes the message to the dry-run writer as a single line.
+func (g *Ghost) writeDryRun(message *messages.Price) error {
+	b, err := message.Marshall()
+	if err != nil {
+		return err
+	}
+	g.dryRunMu.Lock()
+	defer g.dryRunMu.Unlock()
+	_, err = g.dryRun.Write(append(b, '\n'))
 	return err
 }
 
+// record adds the entry to the journal, if it is configured. Errors are
+// only logged, because the price has already been broadcast.
+func (g *Ghost) record(entry JournalEntry) {
+	if g.journal == nil {
+		return
+	}
+	err := g.journal.Record(entry)
+	if err != nil {
+		g.log.
+			WithFields(log.Fields{"cycleID": entry.CycleID, "assetPair": entry.Pair}).
+			WithError(err).
+			Warn("Unable to record broadcast in journal")
+	}
+}
+
+// broadcastCycle fetches prices for all pairs with a single Gofer call, so
+// all prices in the cycle are calculated from the same state of the Gofer,
+// and then signs and broadcasts them using a pool of workers. Signing may be
+// slow, especially with high KDF, so this is why the prices are signed
+// concurrently.
+func (g *Ghost) broadcastCycle() {
+	cycleID := newCycleID()
+
+	var goferPairs []gofer.Pair
+	for goferPair := range g.pairs {
+		goferPairs = append(goferPairs, goferPair)
+	}
+	ticks, err := g.gofer.Prices(goferPairs...)
+	if err != nil {
+		g.log.
+			WithFields(log.Fields{"cycleID": cycleID}).
+			WithError(err).
+			Warn("Unable to fetch prices")
+		return
+	}
+
+	ch := make(chan gofer.Pair)
+	wg := sync.WaitGroup{}
+	for i := 0; i < g.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for assetPair := range ch {
+				price, err := g.broadcast(cycleID, assetPair, ticks[assetPair])
+				fields := log.Fields{"cycleID": cycleID, "assetPair": assetPair}
+				var rejected ErrPriceRejected
+				switch {
+				case errors.As(err, &rejected):
+					g.log.
+						WithFields(fields).
+						WithFields(log.Fields{"guard": rejected.Guard, "reason": rejected.Reason}).
+						Warn("Price rejected by guard")
+				case err != nil:
+					g.log.
+						WithFields(fields).
+						WithError(err).
+						Warn("Unable to broadcast price")
+				case price == nil:
+					g.log.
+						WithFields(fields).
+						Info("Price did not change, broadcast skipped")
+				default:
+					g.log.
+						WithFields(fields).
+						Info("Price broadcast")
+				}
+			}
+		}()
+	}
+	for _, goferPair := range goferPairs {
+		ch <- goferPair
+	}
+	close(ch)
+	wg.Wait()
+}
+
 // broadcasterLoop creates a asynchronous loop which fetches prices from exchanges and then
 // sends them to the network at a specified interval.
 func (g *Ghost) broadcasterLoop() error {
@@ -179,53 +471,84 @@ func (g *Ghost) broadcasterLoop() error {
 	}
 
 	ticker := time.NewTicker(g.interval)
-	wg := sync.WaitGroup{}
+	g.wg.Add(1)
 	go func() {
+		defer g.wg.Done()
 		for {
 			select {
 			case <-g.doneCh:
 				ticker.Stop()
 				return
 			case <-ticker.C:
-				// TODO: fetch all prices before broadcast is called
-
-				// Send prices to the network:
-				//
-				// Signing may be slow, especially with high KDF so this is why
-				// we're using goroutines here.
-				wg.Add(1)
-				go func() {
-					for assetPair := range g.pairs {
-						err := g.broadcast(assetPair)
-						if err != nil {
-							g.log.
-								WithFields(log.Fields{"assetPair": assetPair}).
-								WithError(err).
-								Warn("Unable to broadcast price")
-						} else {
-							g.log.
-								WithFields(log.Fields{"assetPair": assetPair}).
-								Info("Price broadcast")
-						}
-					}
-					wg.Done()
-				}()
+				// Ticks are dropped by the ticker if the cycle takes longer
+				// than the interval, so cycles never overlap.
+				start := time.Now()
+				g.broadcastCycle()
+				if d := time.Since(start); d > g.interval {
+					g.log.
+						WithField("duration", d.String()).
+						Warn("Broadcast cycle took longer than the interval")
+				}
 			}
-			wg.Wait()
 		}
 	}()
 
 	return nil
 }
 
-func createPriceMessage(price *oracle.Price, tick *gofer.Price) (*messages.Price, error) {
+// createPriceMessage creates a message with the price and the trace of its
+// calculation. The cycle ID is added to the trace, so prices broadcast in
+// the same cycle can be matched.
+func createPriceMessage(cycleID string, price *oracle.Price, tick *gofer.Price) (*messages.Price, error) {
 	trace, err := marshal.Marshall(marshal.JSON, tick)
 	if err != nil {
 		return nil, err
 	}
 
+	// The trace is a list of prices, the cycle ID is added to each of them:
+	var items []map[string]json.RawMessage
+	err = json.Unmarshal(trace, &items)
+	if err != nil {
+		return nil, err
+	}
+	id, err := json.Marshal(cycleID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item["cycleID"] = id
+	}
+	trace, err = json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
 	return &messages.Price{
 		Price: price,
 		Trace: trace,
 	}, nil
 }
+
+// newCycleID returns a random ID for a broadcast cycle.
+func newCycleID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// calcSpread calculates the spread between the old and new price in percent.
+func calcSpread(oldPrice, newPrice *big.Int) float64 {
+	if oldPrice == nil || oldPrice.Sign() == 0 {
+		return math.Inf(1)
+	}
+
+	oldPriceF := new(big.Float).SetInt(oldPrice)
+	newPriceF := new(big.Float).SetInt(newPrice)
+
+	x := new(big.Float).Sub(newPriceF, oldPriceF)
+	x = new(big.Float).Quo(x, oldPriceF)
+	x = new(big.Float).Mul(x, big.NewFloat(100))
+	xf, _ := x.Float64()
+
+	return math.Abs(xf)
+}
@@ -0,0 +1,402 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ghost
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	ethereumMocks "github.com/makerdao/oracle-suite/pkg/ethereum/mocks"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+	goferMocks "github.com/makerdao/oracle-suite/pkg/gofer/mocks"
+	"github.com/makerdao/oracle-suite/pkg/log/null"
+	"github.com/makerdao/oracle-suite/pkg/stark"
+	"github.com/makerdao/oracle-suite/pkg/transport/local"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+var testPair = gofer.Pair{Base: "AAA", Quote: "BBB"}
+var testPair2 = gofer.Pair{Base: "CCC", Quote: "DDD"}
+
+func newTestGhost(t *testing.T, pairs ...*Pair) (*Ghost, *goferMocks.Gofer, *local.Local) {
+	gof := &goferMocks.Gofer{}
+	sig := &ethereumMocks.Signer{}
+	tra := local.New(10)
+	require.NoError(t, tra.Subscribe(messages.PriceMessageName, (*messages.Price)(nil)))
+
+	gof.On("Pairs").Return([]gofer.Pair{testPair, testPair2}, nil)
+	sig.On("Signature", mock.Anything).Return(ethereum.Signature{}, nil)
+
+	g, err := NewGhost(Config{
+		Gofer:     gof,
+		Signer:    sig,
+		Transport: tra,
+		Interval:  time.Minute,
+		Logger:    null.New(),
+		Pairs:     pairs,
+	})
+	require.NoError(t, err)
+	return g, gof, tra
+}
+
+func testTick(price float64) *gofer.Price {
+	return &gofer.Price{Pair: testPair, Price: price, Time: time.Now()}
+}
+
+func TestGhost_broadcast_Spread(t *testing.T) {
+	g, _, _ := newTestGhost(t, &Pair{AssetPair: "AAABBB", Spread: 1, Heartbeat: time.Hour})
+
+	// The first price is always broadcast:
+	tick := testTick(100)
+	price, err := g.broadcast("", testPair, tick)
+	require.NoError(t, err)
+	assert.NotNil(t, price)
+
+	// Spread is lower than 1%:
+	tick = testTick(100.5)
+	price, err = g.broadcast("", testPair, tick)
+	require.NoError(t, err)
+	assert.Nil(t, price)
+
+	// Spread is higher than 1%:
+	tick = testTick(101.5)
+	price, err = g.broadcast("", testPair, tick)
+	require.NoError(t, err)
+	assert.NotNil(t, price)
+	assert.Equal(t, 101.5, price.Float64Price())
+}
+
+func TestGhost_broadcast_Heartbeat(t *testing.T) {
+	g, _, _ := newTestGhost(t, &Pair{AssetPair: "AAABBB", Spread: 1, Heartbeat: time.Hour})
+
+	tick := testTick(100)
+	price, err := g.broadcast("", testPair, tick)
+	require.NoError(t, err)
+	assert.NotNil(t, price)
+
+	price, err = g.broadcast("", testPair, tick)
+	require.NoError(t, err)
+	assert.Nil(t, price)
+
+	// Simulate that the heartbeat elapsed:
+	last := g.lastBroadcast[testPair]
+	last.Time = last.Time.Add(-time.Hour)
+	g.lastBroadcast[testPair] = last
+
+	price, err = g.broadcast("", testPair, tick)
+	require.NoError(t, err)
+	assert.NotNil(t, price)
+}
+
+func TestGhost_broadcast_NoSpreadAndHeartbeat(t *testing.T) {
+	g, _, _ := newTestGhost(t, &Pair{AssetPair: "AAABBB"})
+
+	// Without spread and heartbeat, prices are broadcast every time:
+	tick := testTick(100)
+	for i := 0; i < 3; i++ {
+		price, err := g.broadcast("", testPair, tick)
+		require.NoError(t, err)
+		assert.NotNil(t, price)
+	}
+}
+
+func TestGhost_broadcast_StarkSignature(t *testing.T) {
+	g, _, _ := newTestGhost(t, &Pair{AssetPair: "AAABBB"})
+	s, err := stark.NewSigner(stark.GrindKey([]byte("seed")))
+	require.NoError(t, err)
+	g.starkSigner = s
+
+	tick := testTick(100)
+	price, err := g.broadcast("", testPair, tick)
+	require.NoError(t, err)
+	require.NotNil(t, price)
+	assert.Equal(t, s.PublicKey().Bytes(), price.StarkPK)
+	assert.NoError(t, price.VerifyStark())
+}
+
+func TestGhost_broadcast_MaxMoveLasting(t *testing.T) {
+	g, _, _ := newTestGhost(t, &Pair{AssetPair: "AAABBB", Spread: 1, MaxMove: 10, MaxMoveTicks: 3})
+
+	price, err := g.broadcast("", testPair, testTick(100))
+	require.NoError(t, err)
+	require.NotNil(t, price)
+
+	// The price moved by 50% and stays there, so it is accepted on the third
+	// consecutive tick:
+	for i := 0; i < 2; i++ {
+		_, err = g.broadcast("", testPair, testTick(150+float64(i)))
+		var e ErrPriceRejected
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, GuardMaxMove, e.Guard)
+	}
+	price, err = g.broadcast("", testPair, testTick(152))
+	require.NoError(t, err)
+	require.NotNil(t, price)
+	assert.Equal(t, price.Val, g.lastBroadcast[testPair].Val)
+
+	// The new price is the reference for the following ticks:
+	price, err = g.broadcast("", testPair, testTick(160))
+	require.NoError(t, err)
+	require.NotNil(t, price)
+}
+
+func TestGhost_broadcast_MaxMoveSpike(t *testing.T) {
+	g, _, _ := newTestGhost(t, &Pair{AssetPair: "AAABBB", Spread: 1, Heartbeat: time.Hour, MaxMove: 10, MaxMoveTicks: 2})
+
+	price, err := g.broadcast("", testPair, testTick(100))
+	require.NoError(t, err)
+	require.NotNil(t, price)
+
+	// Ticks which are not consistent with each other are always rejected:
+	for _, p := range []float64{150, 50, 150, 50} {
+		_, err = g.broadcast("", testPair, testTick(p))
+		var e ErrPriceRejected
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, GuardMaxMove, e.Guard)
+	}
+
+	// A tick close to the last broadcast price resets the move:
+	price, err = g.broadcast("", testPair, testTick(100))
+	require.NoError(t, err)
+	assert.Nil(t, price)
+	_, err = g.broadcast("", testPair, testTick(50))
+	assert.Error(t, err)
+}
+
+func TestGhost_broadcastCycle(t *testing.T) {
+	g, gof, tra := newTestGhost(t, &Pair{AssetPair: "AAABBB"}, &Pair{AssetPair: "CCCDDD"})
+
+	// Prices for all pairs must be fetched with a single call:
+	gof.On("Prices", mock.Anything, mock.Anything).Return(map[gofer.Pair]*gofer.Price{
+		testPair:  {Pair: testPair, Price: 100, Time: time.Now()},
+		testPair2: {Pair: testPair2, Price: 200, Time: time.Now()},
+	}, nil).Once()
+
+	g.broadcastCycle()
+	gof.AssertExpectations(t)
+
+	// Both messages must have the same cycle ID in the trace:
+	var cycleIDs []string
+	wats := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		msg := <-tra.WaitFor(messages.PriceMessageName)
+		require.NoError(t, msg.Error)
+		price := msg.Message.(*messages.Price)
+		var trace []struct {
+			CycleID string `json:"cycleID"`
+		}
+		require.NoError(t, json.Unmarshal(price.Trace, &trace))
+		require.Len(t, trace, 1)
+		cycleIDs = append(cycleIDs, trace[0].CycleID)
+		wats[price.Price.Wat] = true
+	}
+	assert.NotEmpty(t, cycleIDs[0])
+	assert.Equal(t, cycleIDs[0], cycleIDs[1])
+	assert.Equal(t, map[string]bool{"AAABBB": true, "CCCDDD": true}, wats)
+}
+
+type testJournal []JournalEntry
+
+func (j *testJournal) Record(entry JournalEntry) error {
+	*j = append(*j, entry)
+	return nil
+}
+
+// closingJournal is a journal which remembers if an entry was recorded after
+// the journal was closed.
+type closingJournal struct {
+	mu                sync.Mutex
+	closed            bool
+	recorded          int
+	recordAfterClosed bool
+}
+
+func (j *closingJournal) Record(JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.recorded++
+	j.recordAfterClosed = j.recordAfterClosed || j.closed
+	return nil
+}
+
+func (j *closingJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.closed = true
+	return nil
+}
+
+func TestGhost_Stop(t *testing.T) {
+	g, gof, _ := newTestGhost(t, &Pair{AssetPair: "AAABBB"})
+	jou := &closingJournal{}
+	g.journal = jou
+	g.interval = 10 * time.Millisecond
+
+	// The cycle is still running when Ghost is stopped:
+	started := make(chan struct{})
+	gof.On("Prices", mock.Anything).Return(map[gofer.Pair]*gofer.Price{
+		testPair: {Pair: testPair, Price: 100, Time: time.Now()},
+	}, nil).Run(func(mock.Arguments) {
+		select {
+		case <-started:
+		default:
+			close(started)
+		}
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	require.NoError(t, g.Start())
+	<-started
+	require.NoError(t, g.Stop())
+
+	// The journal must be closed after the cycle is finished:
+	jou.mu.Lock()
+	defer jou.mu.Unlock()
+	assert.True(t, jou.closed)
+	assert.NotZero(t, jou.recorded)
+	assert.False(t, jou.recordAfterClosed)
+}
+
+func TestGhost_broadcast_DryRun(t *testing.T) {
+	g, _, tra := newTestGhost(t, &Pair{AssetPair: "AAABBB"})
+	out := &bytes.Buffer{}
+	jou := &testJournal{}
+	g.dryRun = out
+	g.journal = jou
+
+	price, err := g.broadcast("cycle", testPair, testTick(100))
+	require.NoError(t, err)
+	require.NotNil(t, price)
+
+	// The message must be written to the output instead of the transport:
+	msg := &messages.Price{}
+	require.NoError(t, msg.Unmarshall(bytes.TrimSpace(out.Bytes())))
+	assert.Equal(t, "AAABBB", msg.Price.Wat)
+	assert.Equal(t, price.Val, msg.Price.Val)
+	select {
+	case <-tra.WaitFor(messages.PriceMessageName):
+		assert.Fail(t, "message must not be broadcast in the dry-run mode")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// The broadcast must be recorded in the journal:
+	require.Len(t, *jou, 1)
+	assert.Equal(t, "AAABBB", (*jou)[0].Pair)
+	assert.Equal(t, price.Val.String(), (*jou)[0].Val)
+	assert.Equal(t, price.Age.Unix(), (*jou)[0].Age)
+	assert.Equal(t, hex.EncodeToString(ethereum.SHA3Hash(msg.Trace)), (*jou)[0].TraceHash)
+	assert.Equal(t, "cycle", (*jou)[0].CycleID)
+	assert.True(t, (*jou)[0].DryRun)
+
+	// The price was not broadcast, so it must not be used as the last one:
+	assert.NotContains(t, g.lastBroadcast, testPair)
+}
+
+func TestGhost_broadcast_Guards(t *testing.T) {
+	origin := func(name string, err string) *gofer.Price {
+		return &gofer.Price{Type: "origin", Parameters: map[string]string{"origin": name}, Error: err}
+	}
+	tests := []struct {
+		name  string
+		pair  *Pair
+		last  float64 // last broadcast price, zero if none
+		tick  *gofer.Price
+		guard string // expected guard, empty if the price must be accepted
+	}{
+		{
+			name:  "maxAge-ok",
+			pair:  &Pair{AssetPair: "AAABBB", MaxAge: time.Minute},
+			tick:  &gofer.Price{Price: 100, Time: time.Now().Add(-30 * time.Second)},
+			guard: "",
+		},
+		{
+			name:  "maxAge-rejected",
+			pair:  &Pair{AssetPair: "AAABBB", MaxAge: time.Minute},
+			tick:  &gofer.Price{Price: 100, Time: time.Now().Add(-2 * time.Minute)},
+			guard: GuardMaxAge,
+		},
+		{
+			name: "minSources-ok",
+			pair: &Pair{AssetPair: "AAABBB", MinSources: 2},
+			tick: &gofer.Price{Price: 100, Time: time.Now(), Type: "aggregator", Prices: []*gofer.Price{
+				origin("a", ""), origin("b", ""), origin("c", "failed"),
+			}},
+			guard: "",
+		},
+		{
+			name: "minSources-rejected",
+			pair: &Pair{AssetPair: "AAABBB", MinSources: 2},
+			tick: &gofer.Price{Price: 100, Time: time.Now(), Type: "aggregator", Prices: []*gofer.Price{
+				origin("a", ""), origin("a", ""), origin("c", "failed"),
+			}},
+			guard: GuardMinSources,
+		},
+		{
+			name:  "maxMove-ok",
+			pair:  &Pair{AssetPair: "AAABBB", MaxMove: 10},
+			last:  100,
+			tick:  &gofer.Price{Price: 105, Time: time.Now()},
+			guard: "",
+		},
+		{
+			name:  "maxMove-rejected",
+			pair:  &Pair{AssetPair: "AAABBB", MaxMove: 10},
+			last:  100,
+			tick:  &gofer.Price{Price: 120, Time: time.Now()},
+			guard: GuardMaxMove,
+		},
+		{
+			name:  "maxMove-first-price",
+			pair:  &Pair{AssetPair: "AAABBB", MaxMove: 10},
+			tick:  &gofer.Price{Price: 120, Time: time.Now()},
+			guard: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _, _ := newTestGhost(t, tt.pair)
+			if tt.last != 0 {
+				_, err := g.broadcast("", testPair, testTick(tt.last))
+				require.NoError(t, err)
+			}
+
+			rejected := testutil.ToFloat64(rejectedPrices.WithLabelValues("AAABBB", tt.guard))
+			price, err := g.broadcast("", testPair, tt.tick)
+			if tt.guard == "" {
+				require.NoError(t, err)
+				assert.NotNil(t, price)
+				return
+			}
+
+			var e ErrPriceRejected
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, tt.guard, e.Guard)
+			assert.Nil(t, price)
+			assert.Equal(t, rejected+1, testutil.ToFloat64(rejectedPrices.WithLabelValues("AAABBB", tt.guard)))
+		})
+	}
+}
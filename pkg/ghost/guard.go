@@ -0,0 +1,156 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ghost
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+)
+
+// Names of guards which may reject a price before it is signed.
+const (
+	GuardMaxAge     = "maxAge"
+	GuardMinSources = "minSources"
+	GuardMaxMove    = "maxMove"
+)
+
+// DefaultMaxMoveTicks is the default number of consecutive ticks required to
+// accept a price rejected by the maxMove guard.
+const DefaultMaxMoveTicks = 3
+
+// ErrPriceRejected is returned when a price is rejected by one of
+// the guards.
+type ErrPriceRejected struct {
+	// Guard is the name of the guard which rejected the price.
+	Guard string
+	// Reason describes why the price was rejected.
+	Reason string
+}
+
+func (e ErrPriceRejected) Error() string {
+	return fmt.Sprintf("price rejected by the %s guard: %s", e.Guard, e.Reason)
+}
+
+var rejectedPrices = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ghost",
+		Name:      "rejected_prices_total",
+		Help:      "Number of prices rejected by guards before signing.",
+	},
+	[]string{"pair", "guard"},
+)
+
+func init() {
+	prometheus.MustRegister(rejectedPrices)
+}
+
+// pendingMove describes a price move rejected by the maxMove guard, which
+// has not been confirmed yet.
+type pendingMove struct {
+	// Val is the price of the first tick after the move.
+	Val *big.Int
+	// Ticks is the number of consecutive ticks which confirmed the move.
+	Ticks int
+}
+
+// checkGuards verifies the price against the pair's guards. The last
+// broadcast price is nil if no price was broadcast yet. The move is the
+// unconfirmed move rejected by the maxMove guard on previous ticks, or nil
+// if there is none. It returns the unconfirmed move after this tick.
+func checkGuards(
+	pair *Pair,
+	tick *gofer.Price,
+	val *big.Int,
+	last *broadcastRecord,
+	move *pendingMove,
+	now time.Time,
+) (*pendingMove, error) {
+
+	if pair.MaxAge > 0 {
+		if age := now.Sub(tick.Time); age > pair.MaxAge {
+			return move, ErrPriceRejected{
+				Guard:  GuardMaxAge,
+				Reason: fmt.Sprintf("price is %s old, maximum is %s", age.Round(time.Second), pair.MaxAge),
+			}
+		}
+	}
+	if pair.MinSources > 0 {
+		if n := countSources(tick); n < pair.MinSources {
+			return move, ErrPriceRejected{
+				Guard:  GuardMinSources,
+				Reason: fmt.Sprintf("price is based on %d origins, minimum is %d", n, pair.MinSources),
+			}
+		}
+	}
+	if pair.MaxMove > 0 && last != nil {
+		if diff := calcSpread(last.Val, val); diff > pair.MaxMove {
+			return checkMove(pair, val, diff, move)
+		}
+	}
+	return nil, nil
+}
+
+// checkMove is called for a price which moved more than allowed by the
+// maxMove guard. The price is rejected unless the move lasts: once
+// pair.MaxMoveTicks consecutive ticks stay within MaxMove of the first
+// price after the move, the price is accepted. Otherwise, a single real
+// move would block the pair forever.
+func checkMove(pair *Pair, val *big.Int, diff float64, move *pendingMove) (*pendingMove, error) {
+	if move == nil || calcSpread(move.Val, val) > pair.MaxMove {
+		move = &pendingMove{Val: val}
+	}
+	move.Ticks++
+	ticks := pair.MaxMoveTicks
+	if ticks <= 0 {
+		ticks = DefaultMaxMoveTicks
+	}
+	if move.Ticks >= ticks {
+		return nil, nil
+	}
+	return move, ErrPriceRejected{
+		Guard: GuardMaxMove,
+		Reason: fmt.Sprintf(
+			"price moved by %.2f%% since the last broadcast, maximum is %.2f%%, confirmed by %d of %d ticks",
+			diff, pair.MaxMove, move.Ticks, ticks,
+		),
+	}
+}
+
+// countSources returns the number of distinct origins which successfully
+// provided a price used to calculate the given price.
+func countSources(tick *gofer.Price) int {
+	origins := map[string]struct{}{}
+	var walk func(p *gofer.Price)
+	walk = func(p *gofer.Price) {
+		if p.Error != "" {
+			return
+		}
+		if p.Type == "origin" {
+			origins[p.Parameters["origin"]] = struct{}{}
+			return
+		}
+		for _, c := range p.Prices {
+			walk(c)
+		}
+	}
+	walk(tick)
+	return len(origins)
+}
@@ -0,0 +1,122 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ghost
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// JournalEntry describes a single price broadcast by Ghost.
+type JournalEntry struct {
+	// Time is the time of the broadcast.
+	Time time.Time `json:"time"`
+	// Pair is the name of asset pair, e.g. ETHUSD.
+	Pair string `json:"pair"`
+	// Val is the broadcast price multiplied by oracle.PriceMultiplier.
+	Val string `json:"val"`
+	// Age is the time when the price was obtained, as a Unix timestamp.
+	Age int64 `json:"age"`
+	// TraceHash is the hex encoded Keccak-256 hash of the trace sent with
+	// the price.
+	TraceHash string `json:"traceHash"`
+	// CycleID is the ID of the broadcast cycle.
+	CycleID string `json:"cycleID"`
+	// DryRun is true if the price was not sent to the network.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Journal records every price broadcast by Ghost.
+type Journal interface {
+	Record(entry JournalEntry) error
+}
+
+// FileJournal is a Journal which appends entries to a file, one JSON object
+// per line.
+type FileJournal struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileJournal opens the journal file. The file is created if it does not
+// exist, otherwise new entries are appended to it.
+func NewFileJournal(path string) (*FileJournal, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileJournal{f: f}, nil
+}
+
+// Record implements the Journal interface.
+func (j *FileJournal) Record(entry JournalEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.f.Write(append(b, '\n'))
+	return err
+}
+
+// Close closes the journal file.
+func (j *FileJournal) Close() error {
+	return j.f.Close()
+}
+
+// ReadJournal reads all entries written by the FileJournal.
+func ReadJournal(r io.Reader) ([]JournalEntry, error) {
+	var entries []JournalEntry
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		var e JournalEntry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindJournalEntry returns the last price broadcast for the pair before or
+// at the given time, that is, the price which was the most recent one at
+// that time. Prices recorded in the dry-run mode were never sent to
+// the network, so they are skipped unless includeDryRun is true. It returns
+// false if there is no such entry.
+func FindJournalEntry(entries []JournalEntry, pair string, at time.Time, includeDryRun bool) (JournalEntry, bool) {
+	var found JournalEntry
+	ok := false
+	for _, e := range entries {
+		if e.Pair != pair || e.Time.After(at) || (e.DryRun && !includeDryRun) {
+			continue
+		}
+		if !ok || !e.Time.Before(found.Time) {
+			found, ok = e, true
+		}
+	}
+	return found, ok
+}
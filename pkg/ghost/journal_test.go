@@ -0,0 +1,84 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ghost
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	t0 := time.Unix(1600000000, 0).UTC()
+	entries := []JournalEntry{
+		{Time: t0, Pair: "AAABBB", Val: "1", Age: 1, TraceHash: "aa", CycleID: "a"},
+		{Time: t0.Add(time.Minute), Pair: "CCCDDD", Val: "2", Age: 2, TraceHash: "bb", CycleID: "a"},
+		{Time: t0.Add(2 * time.Minute), Pair: "AAABBB", Val: "3", Age: 3, TraceHash: "cc", CycleID: "b"},
+	}
+
+	// Entries must be appended to the existing file:
+	for _, e := range entries {
+		j, err := NewFileJournal(path)
+		require.NoError(t, err)
+		require.NoError(t, j.Record(e))
+		require.NoError(t, j.Close())
+	}
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	read, err := ReadJournal(f)
+	require.NoError(t, err)
+	assert.Equal(t, entries, read)
+}
+
+func TestFindJournalEntry(t *testing.T) {
+	t0 := time.Unix(1600000000, 0)
+	entries := []JournalEntry{
+		{Time: t0, Pair: "AAABBB", Val: "1"},
+		{Time: t0.Add(time.Minute), Pair: "CCCDDD", Val: "2"},
+		{Time: t0.Add(2 * time.Minute), Pair: "AAABBB", Val: "3"},
+		{Time: t0.Add(3 * time.Minute), Pair: "AAABBB", Val: "4", DryRun: true},
+	}
+
+	_, ok := FindJournalEntry(entries, "AAABBB", t0.Add(-time.Second), false)
+	assert.False(t, ok)
+
+	e, ok := FindJournalEntry(entries, "AAABBB", t0.Add(90*time.Second), false)
+	assert.True(t, ok)
+	assert.Equal(t, "1", e.Val)
+
+	e, ok = FindJournalEntry(entries, "AAABBB", t0.Add(2*time.Minute), false)
+	assert.True(t, ok)
+	assert.Equal(t, "3", e.Val)
+
+	_, ok = FindJournalEntry(entries, "EEEFFF", t0.Add(time.Hour), false)
+	assert.False(t, ok)
+
+	// Dry-run entries are skipped unless requested:
+	e, ok = FindJournalEntry(entries, "AAABBB", t0.Add(time.Hour), false)
+	assert.True(t, ok)
+	assert.Equal(t, "3", e.Val)
+
+	e, ok = FindJournalEntry(entries, "AAABBB", t0.Add(time.Hour), true)
+	assert.True(t, ok)
+	assert.Equal(t, "4", e.Val)
+}
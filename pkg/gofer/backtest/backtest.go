@@ -0,0 +1,181 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backtest
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/gofer/graph"
+	"github.com/makerdao/oracle-suite/pkg/gofer/graph/feeder"
+	"github.com/makerdao/oracle-suite/pkg/gofer/graph/nodes"
+)
+
+var ErrNoData = errors.New("there are no historical prices to replay")
+var ErrInvalidInterval = errors.New("the interval between ticks must be greater than zero")
+
+// Tick is a price calculated by a price model at the given point in time.
+type Tick struct {
+	Time  time.Time
+	Price *gofer.Price
+}
+
+// Stats summarizes the backtest results for a single asset pair.
+type Stats struct {
+	Pair gofer.Pair
+	// Ticks is the number of evaluated ticks.
+	Ticks int
+	// Failures is the number of ticks for which the price model returned
+	// an error.
+	Failures int
+	// QuorumFailures is the number of ticks for which there were not enough
+	// sources to calculate the median price.
+	QuorumFailures int
+}
+
+// QuorumFailureRate returns the fraction of ticks for which there were not
+// enough sources to calculate the price.
+func (s *Stats) QuorumFailureRate() float64 {
+	if s.Ticks == 0 {
+		return 0
+	}
+	return float64(s.QuorumFailures) / float64(s.Ticks)
+}
+
+// Result contains the price series and the summary of a backtest.
+type Result struct {
+	Ticks []Tick
+	Stats []*Stats
+}
+
+// Backtest replays historical origin prices through price models. On each
+// tick, origin nodes are fed in the same way as the feeder.Feeder would do
+// it at that time, so the TTLs and the minimum number of sources defined in
+// the price models are taken into account.
+type Backtest struct {
+	graphs   map[gofer.Pair]nodes.Aggregator
+	data     *Data
+	interval time.Duration
+	now      time.Time
+}
+
+// NewBacktest returns a new Backtest instance. The interval defines how
+// often prices are evaluated and it must be greater than zero.
+func NewBacktest(g map[gofer.Pair]nodes.Aggregator, d *Data, interval time.Duration) (*Backtest, error) {
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	return &Backtest{
+		graphs:   g,
+		data:     d,
+		interval: interval,
+	}, nil
+}
+
+// Run evaluates price models for the given pairs on every tick between
+// the oldest and the newest historical price. If no pairs are specified,
+// all pairs are evaluated.
+func (b *Backtest) Run(pairs ...gofer.Pair) (*Result, error) {
+	from, to := b.data.Range()
+	if from.IsZero() {
+		return nil, ErrNoData
+	}
+	if len(pairs) == 0 {
+		for p := range b.graphs {
+			pairs = append(pairs, p)
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].String() < pairs[j].String()
+	})
+
+	var ns []nodes.Node
+	for _, p := range pairs {
+		n, ok := b.graphs[p]
+		if !ok {
+			return nil, graph.ErrPairNotFound{Pair: p}
+		}
+		ns = append(ns, n)
+	}
+	feedables := b.feedables(ns)
+
+	res := &Result{}
+	stats := make(map[gofer.Pair]*Stats)
+	for _, p := range pairs {
+		stats[p] = &Stats{Pair: p}
+		res.Stats = append(res.Stats, stats[p])
+	}
+
+	gof := graph.NewGofer(b.graphs, nil)
+	for b.now = from; !b.now.After(to); b.now = b.now.Add(b.interval) {
+		b.feed(feedables)
+		for _, p := range pairs {
+			price, err := gof.Price(p)
+			if err != nil {
+				return nil, err
+			}
+			s := stats[p]
+			s.Ticks++
+			if err := b.graphs[p].Price().Error; err != nil {
+				s.Failures++
+				if errors.As(err, &nodes.ErrNotEnoughSources{}) {
+					s.QuorumFailures++
+				}
+			}
+			res.Ticks = append(res.Ticks, Tick{Time: b.now, Price: price})
+		}
+	}
+
+	return res, nil
+}
+
+// feedables returns all origin nodes of the given graphs and replaces their
+// clocks, so prices are considered expired according to the tick time.
+func (b *Backtest) feedables(ns []nodes.Node) []feeder.Feedable {
+	var fs []feeder.Feedable
+	nodes.Walk(func(n nodes.Node) {
+		if on, ok := n.(*nodes.OriginNode); ok {
+			on.SetClock(func() time.Time { return b.now })
+		}
+		if f, ok := n.(feeder.Feedable); ok {
+			fs = append(fs, f)
+		}
+	}, ns...)
+	return fs
+}
+
+// feed updates prices of the origin nodes using the same rules as
+// the feeder.Feeder: the price is updated only if it is older than MinTTL,
+// and a price with an error replaces the previous one only if the
+// previous one is expired.
+func (b *Backtest) feed(fs []feeder.Feedable) {
+	for _, f := range fs {
+		current := f.Price()
+		if !current.Time.IsZero() && b.now.Sub(current.Time) < f.MinTTL() {
+			continue
+		}
+		price, ok := b.data.Latest(f.OriginPair(), b.now)
+		if !ok || (!current.Time.IsZero() && !price.Time.After(current.Time)) {
+			continue
+		}
+		if price.Error != nil && !f.Expired() {
+			continue
+		}
+		_ = f.Ingest(price)
+	}
+}
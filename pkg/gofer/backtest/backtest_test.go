@@ -0,0 +1,142 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backtest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/gofer/graph/nodes"
+)
+
+var (
+	testPair = gofer.Pair{Base: "A", Quote: "B"}
+	testTime = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func testGraph(minSources int) map[gofer.Pair]nodes.Aggregator {
+	root := nodes.NewMedianAggregatorNode(testPair, minSources)
+	for _, o := range []string{"a", "b", "c"} {
+		op := nodes.OriginPair{Origin: o, Pair: testPair}
+		root.AddChild(nodes.NewOriginNode(op, time.Minute, 2*time.Minute))
+	}
+	return map[gofer.Pair]nodes.Aggregator{testPair: root}
+}
+
+func testPrice(origin string, price float64, t time.Time) nodes.OriginPrice {
+	return nodes.OriginPrice{
+		PairPrice: nodes.PairPrice{
+			Pair:  testPair,
+			Price: price,
+			Time:  t,
+		},
+		Origin: origin,
+	}
+}
+
+func TestReadData(t *testing.T) {
+	ndjson := `
+{"type":"aggregator","base":"A","quote":"B","price":11,"ts":"2021-01-01T00:00:00Z","params":{"method":"median"},"prices":[{"type":"origin","base":"A","quote":"B","price":10,"ts":"2021-01-01T00:00:00Z","params":{"origin":"a"}},{"type":"origin","base":"A","quote":"B","price":12,"ts":"2021-01-01T00:00:00Z","params":{"origin":"b"},"error":"err"}]}
+[{"type":"origin","base":"A","quote":"B","price":13,"ts":"2021-01-01T00:01:00Z","params":{"origin":"a"}}]
+`
+	d, err := ReadData(strings.NewReader(ndjson))
+	require.NoError(t, err)
+
+	from, to := d.Range()
+	assert.Equal(t, testTime, from)
+	assert.Equal(t, testTime.Add(time.Minute), to)
+
+	p, ok := d.Latest(nodes.OriginPair{Origin: "a", Pair: testPair}, testTime.Add(30*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 10.0, p.Price)
+
+	p, ok = d.Latest(nodes.OriginPair{Origin: "a", Pair: testPair}, testTime.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, 13.0, p.Price)
+
+	p, ok = d.Latest(nodes.OriginPair{Origin: "b", Pair: testPair}, testTime)
+	assert.True(t, ok)
+	assert.EqualError(t, p.Error, "err")
+
+	_, ok = d.Latest(nodes.OriginPair{Origin: "a", Pair: testPair}, testTime.Add(-time.Second))
+	assert.False(t, ok)
+}
+
+func TestReadData_Invalid(t *testing.T) {
+	_, err := ReadData(strings.NewReader("{"))
+	assert.Error(t, err)
+}
+
+func TestBacktest_Run(t *testing.T) {
+	d := NewData()
+	d.Add(testPrice("a", 10, testTime))
+	d.Add(testPrice("b", 20, testTime))
+	d.Add(testPrice("c", 30, testTime))
+	// Only the "a" origin is updated, prices from "b" and "c" will expire
+	// after two minutes:
+	d.Add(testPrice("a", 11, testTime.Add(time.Minute)))
+	d.Add(testPrice("a", 12, testTime.Add(2*time.Minute)))
+	d.Add(testPrice("a", 13, testTime.Add(3*time.Minute)))
+
+	bt, err := NewBacktest(testGraph(2), d, time.Minute)
+	require.NoError(t, err)
+	res, err := bt.Run()
+	require.NoError(t, err)
+	require.Len(t, res.Ticks, 4)
+	require.Len(t, res.Stats, 1)
+
+	assert.Equal(t, testTime, res.Ticks[0].Time)
+	assert.Equal(t, 20.0, res.Ticks[0].Price.Price)
+	assert.Empty(t, res.Ticks[0].Price.Error)
+	assert.Equal(t, 20.0, res.Ticks[1].Price.Price)
+	assert.Equal(t, 20.0, res.Ticks[2].Price.Price)
+	assert.NotEmpty(t, res.Ticks[3].Price.Error)
+
+	assert.Equal(t, testPair, res.Stats[0].Pair)
+	assert.Equal(t, 4, res.Stats[0].Ticks)
+	assert.Equal(t, 1, res.Stats[0].Failures)
+	assert.Equal(t, 1, res.Stats[0].QuorumFailures)
+	assert.Equal(t, 0.25, res.Stats[0].QuorumFailureRate())
+}
+
+func TestBacktest_Run_NoData(t *testing.T) {
+	bt, err := NewBacktest(testGraph(1), NewData(), time.Minute)
+	require.NoError(t, err)
+	_, err = bt.Run()
+	assert.Equal(t, ErrNoData, err)
+}
+
+func TestBacktest_Run_UnknownPair(t *testing.T) {
+	d := NewData()
+	d.Add(testPrice("a", 10, testTime))
+
+	bt, err := NewBacktest(testGraph(1), d, time.Minute)
+	require.NoError(t, err)
+	_, err = bt.Run(gofer.Pair{Base: "X", Quote: "Y"})
+	assert.Error(t, err)
+}
+
+func TestNewBacktest_InvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		_, err := NewBacktest(testGraph(1), NewData(), interval)
+		assert.Equal(t, ErrInvalidInterval, err)
+	}
+}
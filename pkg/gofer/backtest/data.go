@@ -0,0 +1,158 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/gofer/graph/nodes"
+)
+
+// Data is a history of origin prices used to replay price models.
+type Data struct {
+	prices map[nodes.OriginPair][]nodes.OriginPrice
+}
+
+// NewData returns a new, empty Data instance.
+func NewData() *Data {
+	return &Data{prices: make(map[nodes.OriginPair][]nodes.OriginPrice)}
+}
+
+// ReadData reads historical prices from the reader. The data is expected to
+// be in the same format as the one returned by the "gofer price" command with
+// the json or ndjson format. Origin prices are extracted from all price
+// trees, so both aggregator and origin prices may be provided.
+func ReadData(r io.Reader) (*Data, error) {
+	d := NewData()
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("unable to read historical prices: %w", err)
+		}
+		var items []jsonPrice
+		if len(raw) > 0 && raw[0] == '[' {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, fmt.Errorf("unable to read historical prices: %w", err)
+			}
+		} else {
+			var item jsonPrice
+			if err := json.Unmarshal(raw, &item); err != nil {
+				return nil, fmt.Errorf("unable to read historical prices: %w", err)
+			}
+			items = append(items, item)
+		}
+		for _, item := range items {
+			d.addJSONPrice(item)
+		}
+	}
+	return d, nil
+}
+
+// Add adds an origin price to the history. Prices with the same origin, pair
+// and time are added only once.
+func (d *Data) Add(price nodes.OriginPrice) {
+	op := nodes.OriginPair{Origin: price.Origin, Pair: price.Pair}
+	ps := d.prices[op]
+	i := sort.Search(len(ps), func(i int) bool {
+		return !ps[i].Time.Before(price.Time)
+	})
+	if i < len(ps) && ps[i].Time.Equal(price.Time) {
+		return
+	}
+	ps = append(ps, nodes.OriginPrice{})
+	copy(ps[i+1:], ps[i:])
+	ps[i] = price
+	d.prices[op] = ps
+}
+
+// Latest returns the most recent price for the given origin pair that is
+// not newer than t. The second return value is false if there is no such
+// price.
+func (d *Data) Latest(op nodes.OriginPair, t time.Time) (nodes.OriginPrice, bool) {
+	ps := d.prices[op]
+	i := sort.Search(len(ps), func(i int) bool {
+		return ps[i].Time.After(t)
+	})
+	if i == 0 {
+		return nodes.OriginPrice{}, false
+	}
+	return ps[i-1], true
+}
+
+// Range returns the time of the oldest and the newest price in the history.
+func (d *Data) Range() (from, to time.Time) {
+	for _, ps := range d.prices {
+		if len(ps) == 0 {
+			continue
+		}
+		if from.IsZero() || ps[0].Time.Before(from) {
+			from = ps[0].Time
+		}
+		if to.IsZero() || ps[len(ps)-1].Time.After(to) {
+			to = ps[len(ps)-1].Time
+		}
+	}
+	return from, to
+}
+
+func (d *Data) addJSONPrice(p jsonPrice) {
+	if p.Type == "origin" {
+		var err error
+		if p.Error != "" {
+			err = errors.New(p.Error)
+		}
+		d.Add(nodes.OriginPrice{
+			PairPrice: nodes.PairPrice{
+				Pair:      gofer.Pair{Base: p.Base, Quote: p.Quote},
+				Price:     p.Price,
+				Bid:       p.Bid,
+				Ask:       p.Ask,
+				Volume24h: p.Volume24h,
+				Time:      p.Timestamp,
+			},
+			Origin: p.Parameters["origin"],
+			Error:  err,
+		})
+	}
+	for _, c := range p.Prices {
+		d.addJSONPrice(c)
+	}
+}
+
+type jsonPrice struct {
+	Type       string            `json:"type"`
+	Base       string            `json:"base"`
+	Quote      string            `json:"quote"`
+	Price      float64           `json:"price"`
+	Bid        float64           `json:"bid"`
+	Ask        float64           `json:"ask"`
+	Volume24h  float64           `json:"vol24h"`
+	Timestamp  time.Time         `json:"ts"`
+	Parameters map[string]string `json:"params"`
+	Prices     []jsonPrice       `json:"prices"`
+	Error      string            `json:"error"`
+}
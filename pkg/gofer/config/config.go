@@ -26,12 +26,15 @@ import (
 	"github.com/makerdao/oracle-suite/internal/query"
 
 	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/gofer/backtest"
 	"github.com/makerdao/oracle-suite/pkg/gofer/graph"
 	"github.com/makerdao/oracle-suite/pkg/gofer/graph/feeder"
 	"github.com/makerdao/oracle-suite/pkg/gofer/graph/nodes"
 	"github.com/makerdao/oracle-suite/pkg/gofer/origins"
 	"github.com/makerdao/oracle-suite/pkg/gofer/rpc"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
+	"github.com/makerdao/oracle-suite/pkg/rpcsec"
 )
 
 const defaultTTL = 60 * time.Second
@@ -64,9 +67,22 @@ type Config struct {
 	RPC         RPC                   `json:"rpc"`
 	Origins     map[string]Origin     `json:"origins"`
 	PriceModels map[string]PriceModel `json:"priceModels"`
+	Health      Health                `json:"health"`
 }
 
 type RPC struct {
+	// Address is the TCP address of the agent, or the path to the Unix
+	// domain socket prefixed with "unix:".
+	Address string `json:"address"`
+	// Token is the bearer token required by the agent and sent by clients.
+	// If empty, the authentication is disabled.
+	Token string     `json:"token"`
+	TLS   rpcsec.TLS `json:"tls"`
+}
+
+type Health struct {
+	// Address is the address on which the /healthz and /readyz endpoints are
+	// served. If empty, the endpoints are disabled.
 	Address string `json:"address"`
 }
 
@@ -125,13 +141,19 @@ func (c *Config) ConfigureRPCAgent(logger log.Logger) (*rpc.Agent, error) {
 	if err != nil {
 		return nil, err
 	}
+	tls, err := c.RPC.TLS.ServerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load TLS configuration: %w", err)
+	}
 	fed := feeder.NewFeeder(originSet, logger)
 	gof := graph.NewAsyncGofer(gra, fed)
+	network, address := rpcsec.ParseAddress(c.RPC.Address)
 	srv, err := rpc.NewAgent(rpc.AgentConfig{
-		Gofer:   gof,
-		Network: "tcp",
-		Address: c.RPC.Address,
-		Logger:  logger,
+		Gofer:    gof,
+		Network:  network,
+		Address:  address,
+		Security: rpcsec.ServerConfig{TLS: tls, Token: c.RPC.Token},
+		Logger:   logger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize rpc agent: %w", err)
@@ -139,9 +161,39 @@ func (c *Config) ConfigureRPCAgent(logger log.Logger) (*rpc.Agent, error) {
 	return srv, nil
 }
 
+// ConfigureHealthServer returns the server for the health endpoints which
+// reports the status of the given agent. It returns nil if the endpoints are
+// disabled.
+func (c *Config) ConfigureHealthServer(agent *rpc.Agent, l log.Logger) *health.Server {
+	if c.Health.Address == "" {
+		return nil
+	}
+	return health.NewServer(health.Config{
+		Checkers: map[string]health.Checker{"agent": agent},
+		Network:  "tcp",
+		Address:  c.Health.Address,
+		Logger:   l,
+	})
+}
+
+// ConfigureBacktest returns a new backtest.Backtest instance which replays
+// given historical prices through configured price models.
+func (c *Config) ConfigureBacktest(data *backtest.Data, interval time.Duration) (*backtest.Backtest, error) {
+	gra, err := c.buildGraphs()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load price models: %w", err)
+	}
+	return backtest.NewBacktest(gra, data, interval)
+}
+
 // ConfigureRPCClient returns a new rpc.RPC instance.
 func (c *Config) ConfigureRPCClient(l log.Logger) (*rpc.Gofer, error) {
-	return rpc.NewGofer("tcp", c.RPC.Address), nil
+	tls, err := c.RPC.TLS.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load TLS configuration: %w", err)
+	}
+	network, address := rpcsec.ParseAddress(c.RPC.Address)
+	return rpc.NewSecureGofer(network, address, rpcsec.ClientConfig{TLS: tls, Token: c.RPC.Token}), nil
 }
 
 func (c *Config) buildOrigins() (*origins.Set, error) {
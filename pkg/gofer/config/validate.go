@@ -0,0 +1,146 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+)
+
+// Validate checks the configuration and returns validation.Errors with all
+// problems found, or nil if the configuration is valid.
+func (c *Config) Validate() error {
+	var errs validation.Errors
+
+	c.validateOrigins(&errs)
+	c.validatePriceModels(&errs)
+	// The same section is used by the agent and by its clients:
+	c.RPC.TLS.ValidateAgent(&errs, "rpc", "tls")
+	c.RPC.TLS.ValidateClient(&errs, "rpc", "tls")
+
+	// Other problems may prevent graphs from being built, so cyclic
+	// references are checked only if everything else is correct.
+	if len(errs) == 0 {
+		if _, err := c.buildGraphs(); err != nil {
+			var cycleErr ErrCyclicReference
+			if errors.As(err, &cycleErr) {
+				errs.Add(validation.Pointer("priceModels", cycleErr.Pair.String()), "%s", err)
+			} else {
+				errs.Add(validation.Pointer("priceModels"), "%s", err)
+			}
+		}
+	}
+
+	return errs.Err()
+}
+
+func (c *Config) validateOrigins(errs *validation.Errors) {
+	var names []string
+	for name := range c.Origins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		origin := c.Origins[name]
+		_, err := NewHandler(origin.Type, nil, origin.Params)
+		if err != nil {
+			errs.Add(validation.Pointer("origins", name), "unable to initialize the %s origin: %s", origin.Type, err)
+		}
+	}
+}
+
+func (c *Config) validatePriceModels(errs *validation.Errors) {
+	originSet := DefaultOriginSet(nil)
+	for name := range c.Origins {
+		originSet.SetHandler(name, nil)
+	}
+	handlers := originSet.Handlers()
+
+	var names []string
+	models := map[gofer.Pair]struct{}{}
+	for name := range c.PriceModels {
+		names = append(names, name)
+		if pair, err := gofer.NewPair(name); err == nil {
+			models[pair] = struct{}{}
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		model := c.PriceModels[name]
+		if _, err := gofer.NewPair(name); err != nil {
+			errs.Add(validation.Pointer("priceModels", name), "%s", err)
+		}
+
+		switch model.Method {
+		case "median":
+			var params MedianPriceModel
+			if model.Params != nil {
+				if err := json.Unmarshal(model.Params, &params); err != nil {
+					errs.Add(validation.Pointer("priceModels", name, "params"), "%s", err)
+				}
+			}
+			if params.MinSourceSuccess > len(model.Sources) {
+				errs.Add(
+					validation.Pointer("priceModels", name, "params", "minimumSuccessfulSources"),
+					"the minimum number of successful sources is %d but only %d sources are defined",
+					params.MinSourceSuccess,
+					len(model.Sources),
+				)
+			}
+		default:
+			errs.Add(validation.Pointer("priceModels", name, "method"), "unknown method %s", model.Method)
+		}
+
+		if len(model.Sources) == 0 {
+			errs.Add(validation.Pointer("priceModels", name, "sources"), "at least one source is required")
+		}
+		for i, sources := range model.Sources {
+			if len(sources) == 0 {
+				errs.Add(validation.Pointer("priceModels", name, "sources", i), "at least one pair is required")
+			}
+			for j, source := range sources {
+				sourcePair, err := gofer.NewPair(source.Pair)
+				if err != nil {
+					errs.Add(validation.Pointer("priceModels", name, "sources", i, j, "pair"), "%s", err)
+					continue
+				}
+				if source.Origin == "." {
+					if _, ok := models[sourcePair]; !ok {
+						errs.Add(
+							validation.Pointer("priceModels", name, "sources", i, j, "pair"),
+							"unable to find price model for the %s pair",
+							sourcePair,
+						)
+					}
+					continue
+				}
+				if _, ok := handlers[source.Origin]; !ok {
+					errs.Add(
+						validation.Pointer("priceModels", name, "sources", i, j, "origin"),
+						"unknown origin %s",
+						source.Origin,
+					)
+				}
+			}
+		}
+	}
+}
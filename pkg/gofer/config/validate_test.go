@@ -0,0 +1,108 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+)
+
+func TestConfig_Validate_ValidConfig(t *testing.T) {
+	config := Config{
+		Origins: map[string]Origin{
+			"oxr": {Type: "openexchangerates", Params: []byte(`{"apiKey": "key"}`)},
+		},
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method: "median",
+				Sources: [][]Source{
+					{{Origin: "binance", Pair: "A/B"}},
+					{{Origin: "oxr", Pair: "A/C"}, {Origin: ".", Pair: "C/B"}},
+				},
+				Params: []byte(`{"minimumSuccessfulSources": 2}`),
+			},
+			"C/B": {
+				Method:  "median",
+				Sources: [][]Source{{{Origin: "kraken", Pair: "C/B"}}},
+			},
+		},
+	}
+
+	assert.NoError(t, config.Validate())
+}
+
+func TestConfig_Validate_InvalidConfig(t *testing.T) {
+	config := Config{
+		Origins: map[string]Origin{
+			"cmc": {Type: "coinmarketcap"},
+		},
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method: "median",
+				Sources: [][]Source{
+					{{Origin: "foo", Pair: "A/B"}},
+					{{Origin: ".", Pair: "X/Y"}},
+				},
+				Params: []byte(`{"minimumSuccessfulSources": 3}`),
+			},
+			"C/D": {
+				Method:  "mean",
+				Sources: [][]Source{{{Origin: "binance", Pair: "CD"}}},
+			},
+		},
+	}
+
+	var errs validation.Errors
+	require.True(t, errors.As(config.Validate(), &errs))
+
+	var pointers []string
+	for _, e := range errs {
+		pointers = append(pointers, e.Pointer)
+	}
+	assert.Equal(t, []string{
+		"/origins/cmc",
+		"/priceModels/A~1B/params/minimumSuccessfulSources",
+		"/priceModels/A~1B/sources/0/0/origin",
+		"/priceModels/A~1B/sources/1/0/pair",
+		"/priceModels/C~1D/method",
+		"/priceModels/C~1D/sources/0/0/pair",
+	}, pointers)
+}
+
+func TestConfig_Validate_CyclicReference(t *testing.T) {
+	config := Config{
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method:  "median",
+				Sources: [][]Source{{{Origin: ".", Pair: "B/A"}}},
+			},
+			"B/A": {
+				Method:  "median",
+				Sources: [][]Source{{{Origin: ".", Pair: "A/B"}}},
+			},
+		},
+	}
+
+	var errs validation.Errors
+	require.True(t, errors.As(config.Validate(), &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/priceModels/A~1B", errs[0].Pointer)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/makerdao/oracle-suite/pkg/gofer"
 	"github.com/makerdao/oracle-suite/pkg/gofer/graph/feeder"
 	"github.com/makerdao/oracle-suite/pkg/gofer/graph/nodes"
+	"github.com/makerdao/oracle-suite/pkg/health"
 )
 
 // AsyncGofer implements the gofer.Gofer interface. It works just like Graph
@@ -47,3 +48,8 @@ func (a *AsyncGofer) Stop() error {
 	a.feeder.Stop()
 	return nil
 }
+
+// HealthStatus implements the health.Checker interface.
+func (a *AsyncGofer) HealthStatus() health.Status {
+	return a.feeder.HealthStatus()
+}
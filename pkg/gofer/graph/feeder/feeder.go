@@ -16,6 +16,7 @@
 package feeder
 
 import (
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -23,6 +24,7 @@ import (
 	"github.com/makerdao/oracle-suite/pkg/gofer"
 	"github.com/makerdao/oracle-suite/pkg/gofer/graph/nodes"
 	"github.com/makerdao/oracle-suite/pkg/gofer/origins"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
 )
 
@@ -66,9 +68,16 @@ type Feedable interface {
 
 // Feeder sets prices from origins to the Feedable nodes.
 type Feeder struct {
+	mu     sync.RWMutex
 	set    *origins.Set
 	log    log.Logger
 	doneCh chan bool
+
+	// Fields used to report the health status:
+	interval            time.Duration
+	lastCycle           time.Time
+	lastSuccessfulCycle time.Time
+	lastWarnings        int
 }
 
 // NewFeeder creates new Feeder instance.
@@ -83,7 +92,31 @@ func NewFeeder(set *origins.Set, log log.Logger) *Feeder {
 // Feed sets Prices to Feedable nodes. This method takes list of root nodes
 // and sets Prices to all of their children that implement the Feedable interface.
 func (f *Feeder) Feed(ns ...nodes.Node) Warnings {
-	return f.fetchPricesAndFeedThemToFeedableNodes(f.findFeedableNodes(ns, time.Now()))
+	warns := f.fetchPricesAndFeedThemToFeedableNodes(f.findFeedableNodes(ns, time.Now()))
+	f.recordCycle(warns)
+	return warns
+}
+
+// HealthStatus implements the health.Checker interface. The Feeder is ready
+// after the first update cycle. If the Feeder was started with the Start
+// method, it is also required that the last cycle was not missed.
+// A cycle is considered successful if there were no warnings.
+func (f *Feeder) HealthStatus() health.Status {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	ready := !f.lastCycle.IsZero()
+	if f.interval > 0 {
+		ready = ready && time.Since(f.lastCycle) <= 2*f.interval
+	}
+	return health.Status{
+		Ready: ready,
+		Details: map[string]interface{}{
+			"lastCycle":           f.lastCycle,
+			"lastSuccessfulCycle": f.lastSuccessfulCycle,
+			"lastWarnings":        f.lastWarnings,
+		},
+	}
 }
 
 // Start starts a goroutine which updates prices as often as the lowest TTL is.
@@ -96,11 +129,16 @@ func (f *Feeder) Start(ns ...nodes.Node) error {
 	}
 	f.log.WithField("interval", gcdTTL.String()).Infof("Update interval (GCD of all TTLs)")
 
+	f.mu.Lock()
+	f.interval = gcdTTL
+	f.mu.Unlock()
+
 	feed := func() {
 		// We have to add gcdTTL to the current time because we want
 		// to find all nodes that will expire before the next tick.
 		t := time.Now().Add(gcdTTL)
 		warns := f.fetchPricesAndFeedThemToFeedableNodes(f.findFeedableNodes(ns, t))
+		f.recordCycle(warns)
 		if len(warns.List) > 0 {
 			f.log.WithError(warns.ToError()).Warn("Unable to feed some nodes")
 		}
@@ -130,6 +168,19 @@ func (f *Feeder) Stop() {
 	f.doneCh <- true
 }
 
+// recordCycle updates the information about the last update cycle used to
+// report the health status.
+func (f *Feeder) recordCycle(warns Warnings) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastCycle = time.Now()
+	f.lastWarnings = len(warns.List)
+	if len(warns.List) == 0 {
+		f.lastSuccessfulCycle = f.lastCycle
+	}
+}
+
 // findFeedableNodes returns a list of children nodes from given root nodes
 // which implement Feedable interface, and their price is expired according
 // to the time from the t arg.
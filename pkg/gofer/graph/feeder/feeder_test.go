@@ -354,6 +354,21 @@ func TestFeeder_Feed_BetweenTTLs(t *testing.T) {
 	assert.Equal(t, 11.0, o.Price().Volume24h)
 }
 
+func TestFeeder_HealthStatus(t *testing.T) {
+	f := NewFeeder(originsSetMock(nil, 0, false), null.New())
+
+	// Not ready before the first cycle:
+	assert.False(t, f.HealthStatus().Ready)
+
+	warns := f.Feed()
+	assert.Len(t, warns.List, 0)
+
+	s := f.HealthStatus()
+	assert.True(t, s.Ready)
+	assert.Equal(t, 0, s.Details["lastWarnings"])
+	assert.Equal(t, s.Details["lastCycle"], s.Details["lastSuccessfulCycle"])
+}
+
 func Test_getGCDTTL(t *testing.T) {
 	p := gofer.Pair{Base: "A", Quote: "B"}
 	root := nodes.NewMedianAggregatorNode(p, 1)
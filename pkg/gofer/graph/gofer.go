@@ -23,6 +23,7 @@ import (
 	"github.com/makerdao/oracle-suite/pkg/gofer"
 	"github.com/makerdao/oracle-suite/pkg/gofer/graph/feeder"
 	"github.com/makerdao/oracle-suite/pkg/gofer/graph/nodes"
+	"github.com/makerdao/oracle-suite/pkg/health"
 )
 
 type ErrPairNotFound struct {
@@ -47,6 +48,15 @@ func NewGofer(g map[gofer.Pair]nodes.Aggregator, f *feeder.Feeder) *Gofer {
 	return &Gofer{graphs: g, feeder: f}
 }
 
+// HealthStatus implements the health.Checker interface. It returns
+// the Feeder's status, or the ready status if prices are updated externally.
+func (g *Gofer) HealthStatus() health.Status {
+	if g.feeder == nil {
+		return health.Status{Ready: true}
+	}
+	return g.feeder.HealthStatus()
+}
+
 // Models implements the gofer.Gofer interface.
 func (g *Gofer) Models(pairs ...gofer.Pair) (map[gofer.Pair]*gofer.Model, error) {
 	ns, err := g.findNodes(pairs...)
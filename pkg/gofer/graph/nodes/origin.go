@@ -71,6 +71,7 @@ type OriginNode struct {
 	price      OriginPrice
 	minTTL     time.Duration
 	maxTTL     time.Duration
+	now        func() time.Time
 }
 
 func NewOriginNode(originPair OriginPair, minTTL time.Duration, maxTTL time.Duration) *OriginNode {
@@ -80,9 +81,20 @@ func NewOriginNode(originPair OriginPair, minTTL time.Duration, maxTTL time.Dura
 		originPair: originPair,
 		minTTL:     minTTL,
 		maxTTL:     maxTTL,
+		now:        time.Now,
 	}
 }
 
+// SetClock replaces the function used to get the current time while
+// checking if the price is expired. By default, time.Now is used. It allows
+// to evaluate prices at a different point in time, e.g. during backtesting.
+func (n *OriginNode) SetClock(now func() time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.now = now
+}
+
 // OriginPair implements the Feedable interface.
 func (n *OriginNode) OriginPair() OriginPair {
 	return n.originPair
@@ -127,7 +139,7 @@ func (n *OriginNode) MaxTTL() time.Duration {
 
 // Expired implements the Feedable interface.
 func (n *OriginNode) Expired() bool {
-	return n.price.Time.Before(time.Now().Add(-1 * n.MaxTTL()))
+	return n.price.Time.Before(n.now().Add(-1 * n.MaxTTL()))
 }
 
 // Price implements the Feedable interface.
@@ -197,3 +197,36 @@ func TestOriginNode_Price_Expired(t *testing.T) {
 
 	assert.True(t, errors.As(price.Error, &ErrPriceTTLExpired{}))
 }
+
+func TestOriginNode_Price_Clock(t *testing.T) {
+	op := OriginPair{
+		Origin: "foo",
+		Pair:   gofer.Pair{Base: "A", Quote: "B"},
+	}
+
+	ts := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	ot := OriginPrice{
+		PairPrice: PairPrice{
+			Pair:      gofer.Pair{Base: "A", Quote: "B"},
+			Price:     10,
+			Bid:       10,
+			Ask:       10,
+			Volume24h: 10,
+			Time:      ts,
+		},
+		Origin: "foo",
+		Error:  nil,
+	}
+
+	now := ts.Add(5 * time.Second)
+	o := NewOriginNode(op, originTestTTL, originTestTTL)
+	o.SetClock(func() time.Time { return now })
+	_ = o.Ingest(ot)
+
+	assert.False(t, o.Expired())
+	assert.NoError(t, o.Price().Error)
+
+	now = ts.Add(20 * time.Second)
+	assert.True(t, o.Expired())
+	assert.True(t, errors.As(o.Price().Error, &ErrPriceTTLExpired{}))
+}
@@ -22,7 +22,9 @@ import (
 	"net/rpc"
 
 	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
+	"github.com/makerdao/oracle-suite/pkg/rpcsec"
 )
 
 const AgentLoggerTag = "GOFER_AGENT"
@@ -37,7 +39,9 @@ type AgentConfig struct {
 	Network string
 	// Address is used for the rpc.Listener function.
 	Address string
-	Logger  log.Logger
+	// Security configures TLS and the authentication of clients.
+	Security rpcsec.ServerConfig
+	Logger   log.Logger
 }
 
 // Agent creates and manages an RPC server for remote Gofer calls.
@@ -48,6 +52,7 @@ type Agent struct {
 	gofer    gofer.Gofer
 	network  string
 	address  string
+	security rpcsec.ServerConfig
 	log      log.Logger
 }
 
@@ -58,11 +63,12 @@ func NewAgent(cfg AgentConfig) (*Agent, error) {
 			gofer: cfg.Gofer,
 			log:   cfg.Logger.WithField("tag", AgentLoggerTag),
 		},
-		rpc:     rpc.NewServer(),
-		gofer:   cfg.Gofer,
-		network: cfg.Network,
-		address: cfg.Address,
-		log:     cfg.Logger.WithField("tag", AgentLoggerTag),
+		rpc:      rpc.NewServer(),
+		gofer:    cfg.Gofer,
+		network:  cfg.Network,
+		address:  cfg.Address,
+		security: cfg.Security,
+		log:      cfg.Logger.WithField("tag", AgentLoggerTag),
 	}
 	err := server.rpc.Register(server.api)
 	if err != nil {
@@ -84,13 +90,13 @@ func (s *Agent) Start() error {
 		}
 	}
 
-	s.listener, err = net.Listen(s.network, s.address)
+	s.listener, err = rpcsec.Listen(s.network, s.address, s.security.TLS)
 	if err != nil {
 		return err
 	}
 
 	go func() {
-		err := http.Serve(s.listener, nil)
+		err := http.Serve(s.listener, rpcsec.Handler(http.DefaultServeMux, s.security.Token))
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.log.WithError(err).Error("RPC server crashed")
 		}
@@ -99,6 +105,15 @@ func (s *Agent) Start() error {
 	return nil
 }
 
+// HealthStatus implements the health.Checker interface. It returns the Gofer's
+// status if the Gofer instance implements the health.Checker interface.
+func (s *Agent) HealthStatus() health.Status {
+	if hc, ok := s.gofer.(health.Checker); ok {
+		return hc.HealthStatus()
+	}
+	return health.Status{Ready: true}
+}
+
 // Stop stops the RPC server.
 func (s *Agent) Stop() {
 	defer s.log.Infof("Stopped")
@@ -19,14 +19,16 @@ import (
 	"net/rpc"
 
 	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/rpcsec"
 )
 
 // Gofer implements the gofer.Gofer interface. It uses a remote RPC server
 // to fetch prices and models.
 type Gofer struct {
-	rpc     *rpc.Client
-	network string
-	address string
+	rpc      *rpc.Client
+	network  string
+	address  string
+	security rpcsec.ClientConfig
 }
 
 // Agent returns a new Gofer instance.
@@ -37,9 +39,19 @@ func NewGofer(network, address string) *Gofer {
 	}
 }
 
+// NewSecureGofer returns a new Gofer instance which connects to the agent
+// using the given TLS and authentication configuration.
+func NewSecureGofer(network, address string, security rpcsec.ClientConfig) *Gofer {
+	return &Gofer{
+		network:  network,
+		address:  address,
+		security: security,
+	}
+}
+
 // Start implements the gofer.StartableGofer interface.
 func (c *Gofer) Start() error {
-	client, err := rpc.DialHTTP(c.network, c.address)
+	client, err := rpcsec.DialHTTP(c.network, c.address, c.security)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,187 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/makerdao/oracle-suite/pkg/log"
+)
+
+const LoggerTag = "HEALTH"
+
+// Status describes the state of a single component.
+type Status struct {
+	// Ready is true if the component is able to do its work.
+	Ready bool `json:"ready"`
+	// Details contains additional information about the component. The
+	// content is different for each component.
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Checker is implemented by components which are able to report their
+// status.
+type Checker interface {
+	HealthStatus() Status
+}
+
+// CheckerFunc is an adapter to allow the use of ordinary functions as
+// a Checker.
+type CheckerFunc func() Status
+
+// HealthStatus implements the Checker interface.
+func (f CheckerFunc) HealthStatus() Status {
+	return f()
+}
+
+// Report is the response returned by the health endpoints.
+type Report struct {
+	// Ready is true only if all components are ready.
+	Ready      bool              `json:"ready"`
+	Components map[string]Status `json:"components"`
+}
+
+// Check collects statuses from all checkers.
+func Check(checkers map[string]Checker) Report {
+	r := Report{Ready: true, Components: make(map[string]Status, len(checkers))}
+	var names []string
+	for name := range checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := checkers[name].HealthStatus()
+		r.Components[name] = s
+		r.Ready = r.Ready && s.Ready
+	}
+	return r
+}
+
+// Merge combines statuses from multiple checkers into a single status. It is
+// used by components which consist of other components. The merged status
+// is ready only if all checkers are ready, details of each checker are stored
+// under its name.
+func Merge(checkers map[string]Checker) Status {
+	r := Check(checkers)
+	s := Status{Ready: r.Ready, Details: make(map[string]interface{}, len(r.Components))}
+	for name, c := range r.Components {
+		s.Details[name] = c
+	}
+	return s
+}
+
+// Server serves the /healthz, /readyz and /metrics endpoints.
+//
+// The /healthz endpoint always responds with the 200 status code as long as
+// the process is able to handle requests. The /readyz endpoint responds with
+// the 200 status code if all components are ready, otherwise with the 503
+// status code. Both endpoints return the Report with statuses of all
+// components. The /metrics endpoint exposes metrics registered in
+// the default Prometheus registry.
+type Server struct {
+	checkers map[string]Checker
+	server   *http.Server
+	listener net.Listener
+	network  string
+	address  string
+	log      log.Logger
+}
+
+type Config struct {
+	// Checkers is the list of components to check, the key is the name of
+	// the component used in the report.
+	Checkers map[string]Checker
+	// Network is used for the net.Listen function.
+	Network string
+	// Address is used for the net.Listen function.
+	Address string
+	Logger  log.Logger
+}
+
+// NewServer returns a new Server instance.
+func NewServer(cfg Config) *Server {
+	s := &Server{
+		checkers: cfg.Checkers,
+		network:  cfg.Network,
+		address:  cfg.Address,
+		log:      cfg.Logger.WithField("tag", LoggerTag),
+	}
+	s.server = &http.Server{Handler: s.Handler()}
+	return s
+}
+
+// Handler returns the HTTP handler for the health and metrics endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(rw http.ResponseWriter, _ *http.Request) {
+		s.writeReport(rw, Check(s.checkers), http.StatusOK)
+	})
+	mux.HandleFunc("/readyz", func(rw http.ResponseWriter, _ *http.Request) {
+		r := Check(s.checkers)
+		if r.Ready {
+			s.writeReport(rw, r, http.StatusOK)
+		} else {
+			s.writeReport(rw, r, http.StatusServiceUnavailable)
+		}
+	})
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
+
+// Start starts the HTTP server.
+func (s *Server) Start() error {
+	s.log.Infof("Starting")
+	var err error
+
+	s.listener, err = net.Listen(s.network, s.address)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		err := s.server.Serve(s.listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.WithError(err).Error("Health server crashed")
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the HTTP server.
+func (s *Server) Stop() {
+	defer s.log.Infof("Stopped")
+
+	err := s.server.Close()
+	if err != nil {
+		s.log.WithError(err).Error("Unable to close health server")
+	}
+}
+
+func (s *Server) writeReport(rw http.ResponseWriter, r Report, code int) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	err := json.NewEncoder(rw).Encode(r)
+	if err != nil {
+		s.log.WithError(err).Warn("Unable to write health report")
+	}
+}
@@ -0,0 +1,91 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/log/null"
+)
+
+func status(ready bool) Checker {
+	return CheckerFunc(func() Status {
+		return Status{Ready: ready, Details: map[string]interface{}{"a": 1}}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	r := Check(map[string]Checker{"a": status(true), "b": status(false)})
+	assert.False(t, r.Ready)
+	assert.True(t, r.Components["a"].Ready)
+	assert.False(t, r.Components["b"].Ready)
+
+	r = Check(map[string]Checker{"a": status(true)})
+	assert.True(t, r.Ready)
+}
+
+func TestMerge(t *testing.T) {
+	s := Merge(map[string]Checker{"a": status(true), "b": status(false)})
+	assert.False(t, s.Ready)
+	assert.Equal(t, Status{Ready: false, Details: map[string]interface{}{"a": 1}}, s.Details["b"])
+
+	s = Merge(map[string]Checker{"a": status(true)})
+	assert.True(t, s.Ready)
+}
+
+func TestServer_Handler(t *testing.T) {
+	tests := []struct {
+		path     string
+		checkers map[string]Checker
+		code     int
+		ready    bool
+	}{
+		{path: "/healthz", checkers: map[string]Checker{"a": status(true)}, code: http.StatusOK, ready: true},
+		{path: "/healthz", checkers: map[string]Checker{"a": status(false)}, code: http.StatusOK, ready: false},
+		{path: "/readyz", checkers: map[string]Checker{"a": status(true)}, code: http.StatusOK, ready: true},
+		{path: "/readyz", checkers: map[string]Checker{"a": status(false)}, code: http.StatusServiceUnavailable, ready: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			s := NewServer(Config{Checkers: tt.checkers, Logger: null.New()})
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var r Report
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+			assert.Equal(t, tt.ready, r.Ready)
+			assert.Contains(t, r.Components, "a")
+		})
+	}
+}
+
+func TestServer_Handler_Metrics(t *testing.T) {
+	s := NewServer(Config{Logger: null.New()})
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "go_goroutines")
+}
@@ -25,27 +25,46 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	ethereumGeth "github.com/makerdao/oracle-suite/pkg/ethereum/geth"
 	"github.com/makerdao/oracle-suite/pkg/oracle"
 )
 
 var ErrStorageQueryFailed = errors.New("oracle contract storage query failed")
 
-// TODO: make it configurable
-const gasLimit = 200000
 const maxReadRetries = 3
 const delayBetweenReadRetries = 5 * time.Second
 
+// TxOptions configures transactions sent to the Median contract.
+type TxOptions struct {
+	// DynamicFees enables EIP-1559 dynamic fee transactions.
+	DynamicFees bool
+	// MaxFeeCap is the upper limit for the max fee per gas, or for the gas
+	// price in case of legacy transactions. If nil, there is no limit.
+	MaxFeeCap *big.Int
+	// PriorityFeeCap is the upper limit for the priority fee per gas.
+	// If nil, there is no limit.
+	PriorityFeeCap *big.Int
+}
+
 // Median implements the oracle.Median interface using go-ethereum packages.
 type Median struct {
-	ethereum ethereum.Client
-	address  ethereum.Address
+	ethereum  ethereum.Client
+	address   ethereum.Address
+	txOptions TxOptions
 }
 
 // NewMedian creates the new Median instance.
 func NewMedian(ethereum ethereum.Client, address ethereum.Address) *Median {
+	return NewMedianWithOptions(ethereum, address, TxOptions{})
+}
+
+// NewMedianWithOptions creates the new Median instance which sends
+// transactions using the given options.
+func NewMedianWithOptions(ethereum ethereum.Client, address ethereum.Address, opts TxOptions) *Median {
 	return &Median{
-		ethereum: ethereum,
-		address:  address,
+		ethereum:  ethereum,
+		address:   address,
+		txOptions: opts,
 	}
 }
 
@@ -142,6 +161,23 @@ func (m *Median) Feeds(ctx context.Context) ([]ethereum.Address, error) {
 
 // Poke implements the oracle.Median interface.
 func (m *Median) Poke(ctx context.Context, prices []*oracle.Price, simulateBeforeRun bool) (*ethereum.Hash, error) {
+	args := pokeArgs(prices)
+	if simulateBeforeRun {
+		if _, err := m.read(ctx, "poke", args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.write(ctx, "poke", args...)
+}
+
+// PokeCalldata implements the oracle.Median interface.
+func (m *Median) PokeCalldata(prices []*oracle.Price) ([]byte, error) {
+	return medianABI.Pack("poke", pokeArgs(prices)...)
+}
+
+// pokeArgs returns arguments for the contract's poke method.
+func pokeArgs(prices []*oracle.Price) []interface{} {
 	// It's important to send prices in correct order, otherwise contract will fail:
 	sort.Slice(prices, func(i, j int) bool {
 		return prices[i].Val.Cmp(prices[j].Val) < 0
@@ -163,13 +199,7 @@ func (m *Median) Poke(ctx context.Context, prices []*oracle.Price, simulateBefor
 		s = append(s, arg.S)
 	}
 
-	if simulateBeforeRun {
-		if _, err := m.read(ctx, "poke", val, age, v, r, s); err != nil {
-			return nil, err
-		}
-	}
-
-	return m.write(ctx, "poke", val, age, v, r, s)
+	return []interface{}{val, age, v, r, s}
 }
 
 // Lift implements the oracle.Median interface.
@@ -229,23 +259,33 @@ func (m *Median) write(ctx context.Context, method string, args ...interface{})
 		return nil, err
 	}
 
-	return m.ethereum.SendTransaction(ctx, &ethereum.Transaction{
-		Address:  m.address,
-		GasLimit: new(big.Int).SetUint64(gasLimit),
-		Data:     cd,
-	})
+	return m.ethereum.SendTransaction(ctx, m.txOptions.transaction(m.address, cd))
 }
 
+// transaction creates a transaction using the options. The gas limit is
+// not set, so it will be estimated by the client.
+func (o TxOptions) transaction(address ethereum.Address, data []byte) *ethereum.Transaction {
+	tx := &ethereum.Transaction{
+		Address:        address,
+		MaxFeeCap:      o.MaxFeeCap,
+		PriorityFeeCap: o.PriorityFeeCap,
+		Data:           data,
+	}
+	if o.DynamicFees {
+		tx.Type = ethereum.DynamicFeeTxType
+	}
+	return tx
+}
+
+// retry calls f until it succeeds, but no more than maxRetries times. EVM
+// reverts are not retried because they are not temporary.
 func retry(maxRetries int, delay time.Duration, f func() error) error {
 	for i := 0; ; i++ {
 		err := f()
-		if err != nil {
+		var revert ethereumGeth.ErrRevert
+		if err == nil || errors.As(err, &revert) || i >= (maxRetries-1) {
 			return err
 		}
-		if i >= (maxRetries - 1) {
-			break
-		}
 		time.Sleep(delay)
 	}
-	return nil
 }
@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	ethereumGeth "github.com/makerdao/oracle-suite/pkg/ethereum/geth"
 	"github.com/makerdao/oracle-suite/pkg/ethereum/mocks"
 	"github.com/makerdao/oracle-suite/pkg/oracle"
 )
@@ -162,7 +164,65 @@ func TestMedian_Poke(t *testing.T) {
 
 	assert.Equal(t, a, tx.Address)
 	assert.Equal(t, (*big.Int)(nil), tx.Gas)
-	assert.Equal(t, big.NewInt(gasLimit), tx.GasLimit)
+	assert.Equal(t, (*big.Int)(nil), tx.GasLimit)
+	assert.Equal(t, ethereum.LegacyTxType, tx.Type)
 	assert.Equal(t, uint64(0), tx.Nonce)
 	assert.Equal(t, cd, hex.EncodeToString(tx.Data))
+
+	// PokeCalldata must return the same calldata as sent by Poke:
+	data, err := m.PokeCalldata([]*oracle.Price{p3, p2, p1})
+	assert.NoError(t, err)
+	assert.Equal(t, cd, hex.EncodeToString(data))
+}
+
+func TestMedian_SetBar_TxOptions(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	m := NewMedianWithOptions(c, a, TxOptions{
+		DynamicFees:    true,
+		MaxFeeCap:      big.NewInt(100),
+		PriorityFeeCap: big.NewInt(10),
+	})
+
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&ethereum.Hash{}, nil)
+
+	_, err := m.SetBar(context.Background(), big.NewInt(13), false)
+	assert.NoError(t, err)
+
+	// Verify generated transaction:
+	tx := c.Calls[0].Arguments.Get(1).(*ethereum.Transaction)
+	assert.Equal(t, ethereum.DynamicFeeTxType, tx.Type)
+	assert.Equal(t, big.NewInt(100), tx.MaxFeeCap)
+	assert.Equal(t, big.NewInt(10), tx.PriorityFeeCap)
+	assert.Equal(t, (*big.Int)(nil), tx.MaxFee)
+	assert.Equal(t, (*big.Int)(nil), tx.PriorityFee)
+	assert.Equal(t, (*big.Int)(nil), tx.GasLimit)
+}
+
+func Test_retry(t *testing.T) {
+	errTemporary := errors.New("temporary error")
+	errRevert := ethereumGeth.ErrRevert{Message: "revert"}
+	tests := []struct {
+		name  string
+		errs  []error
+		err   error
+		calls int
+	}{
+		{name: "success", errs: []error{nil}, err: nil, calls: 1},
+		{name: "temporary error", errs: []error{errTemporary, errTemporary, nil}, err: nil, calls: 3},
+		{name: "persistent error", errs: []error{errTemporary, errTemporary, errTemporary}, err: errTemporary, calls: 3},
+		{name: "revert", errs: []error{errRevert}, err: errRevert, calls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retry(3, 0, func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
 }
@@ -0,0 +1,134 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+)
+
+// OSM implements the oracle.OSM interface using go-ethereum packages.
+type OSM struct {
+	ethereum  ethereum.Client
+	address   ethereum.Address
+	txOptions TxOptions
+}
+
+// NewOSM creates the new OSM instance.
+func NewOSM(ethereum ethereum.Client, address ethereum.Address) *OSM {
+	return NewOSMWithOptions(ethereum, address, TxOptions{})
+}
+
+// NewOSMWithOptions creates the new OSM instance which sends transactions
+// using the given options.
+func NewOSMWithOptions(ethereum ethereum.Client, address ethereum.Address, opts TxOptions) *OSM {
+	return &OSM{
+		ethereum:  ethereum,
+		address:   address,
+		txOptions: opts,
+	}
+}
+
+// Peek implements the oracle.OSM interface.
+func (o *OSM) Peek(ctx context.Context) (*big.Int, bool, error) {
+	return o.price(ctx, "peek")
+}
+
+// Peep implements the oracle.OSM interface.
+func (o *OSM) Peep(ctx context.Context) (*big.Int, bool, error) {
+	return o.price(ctx, "peep")
+}
+
+// Zzz implements the oracle.OSM interface.
+func (o *OSM) Zzz(ctx context.Context) (time.Time, error) {
+	r, err := o.read(ctx, "zzz")
+	if err != nil {
+		return time.Unix(0, 0), err
+	}
+
+	return time.Unix(int64(r[0].(uint64)), 0), nil
+}
+
+// Hop implements the oracle.OSM interface.
+func (o *OSM) Hop(ctx context.Context) (time.Duration, error) {
+	r, err := o.read(ctx, "hop")
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(r[0].(uint16)) * time.Second, nil
+}
+
+// Pass implements the oracle.OSM interface.
+func (o *OSM) Pass(ctx context.Context) (bool, error) {
+	r, err := o.read(ctx, "pass")
+	if err != nil {
+		return false, err
+	}
+
+	return r[0].(bool), nil
+}
+
+// Poke implements the oracle.OSM interface.
+func (o *OSM) Poke(ctx context.Context, simulateBeforeRun bool) (*ethereum.Hash, error) {
+	if simulateBeforeRun {
+		if _, err := o.read(ctx, "poke"); err != nil {
+			return nil, err
+		}
+	}
+
+	return o.write(ctx, "poke")
+}
+
+func (o *OSM) price(ctx context.Context, method string) (*big.Int, bool, error) {
+	r, err := o.read(ctx, method)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b := r[0].([32]byte)
+	return new(big.Int).SetBytes(b[:]), r[1].(bool), nil
+}
+
+func (o *OSM) read(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	cd, err := osmABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = retry(maxReadRetries, delayBetweenReadRetries, func() error {
+		data, err = o.ethereum.Call(ctx, ethereum.Call{Address: o.address, Data: cd})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return osmABI.Unpack(method, data)
+}
+
+func (o *OSM) write(ctx context.Context, method string, args ...interface{}) (*ethereum.Hash, error) {
+	cd, err := osmABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.ethereum.SendTransaction(ctx, o.txOptions.transaction(o.address, cd))
+}
@@ -0,0 +1,159 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	ethereumGeth "github.com/makerdao/oracle-suite/pkg/ethereum/geth"
+	"github.com/makerdao/oracle-suite/pkg/ethereum/mocks"
+)
+
+func TestOSM_Peek(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	// Call Peek function:
+	bts := make([]byte, 64)
+	big.NewInt(123456).FillBytes(bts[:32])
+	bts[63] = 1
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: hexToBytes("59e02dd7")}).Return(bts, nil)
+	val, has, err := o.Peek(context.Background())
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(123456), val)
+	assert.True(t, has)
+}
+
+func TestOSM_Peep(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	// Call Peep function:
+	bts := make([]byte, 64)
+	big.NewInt(42).FillBytes(bts[:32])
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: hexToBytes("0e5a6c70")}).Return(bts, nil)
+	val, has, err := o.Peep(context.Background())
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), val)
+	assert.False(t, has)
+}
+
+func TestOSM_Zzz(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	// Call Zzz function:
+	bts := make([]byte, 32)
+	big.NewInt(1600000000).FillBytes(bts)
+	c.On("Call", mock.Anything, mock.Anything).Return(bts, nil)
+	zzz, err := o.Zzz(context.Background())
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1600000000), zzz.Unix())
+}
+
+func TestOSM_Hop(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	// Call Hop function:
+	bts := make([]byte, 32)
+	big.NewInt(3600).FillBytes(bts)
+	c.On("Call", mock.Anything, mock.Anything).Return(bts, nil)
+	hop, err := o.Hop(context.Background())
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, hop)
+}
+
+func TestOSM_Pass(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	// Call Pass function:
+	bts := make([]byte, 32)
+	bts[31] = 1
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: hexToBytes("a7a1ed72")}).Return(bts, nil)
+	pass, err := o.Pass(context.Background())
+
+	// Verify:
+	assert.NoError(t, err)
+	assert.True(t, pass)
+}
+
+func TestOSM_Poke(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSMWithOptions(c, a, TxOptions{DynamicFees: true})
+
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: hexToBytes("18178358")}).Return([]byte{}, nil)
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&ethereum.Hash{}, nil)
+
+	// Call Poke function:
+	_, err := o.Poke(context.Background(), true)
+	assert.NoError(t, err)
+
+	// Verify generated transaction:
+	tx := c.Calls[1].Arguments.Get(1).(*ethereum.Transaction)
+	assert.Equal(t, a, tx.Address)
+	assert.Equal(t, ethereum.DynamicFeeTxType, tx.Type)
+	assert.Equal(t, "18178358", hex.EncodeToString(tx.Data))
+}
+
+func TestOSM_Poke_SimulationFailed(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	o := NewOSM(c, a)
+
+	c.On("Call", mock.Anything, mock.Anything).Return([]byte{}, ethereumGeth.ErrRevert{Message: "OSM/not-passed"})
+
+	// Call Poke function:
+	_, err := o.Poke(context.Background(), true)
+	assert.Error(t, err)
+	c.AssertNumberOfCalls(t, "Call", 1) // reverts are not retried
+	c.AssertNotCalled(t, "SendTransaction", mock.Anything, mock.Anything)
+}
+
+func hexToBytes(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
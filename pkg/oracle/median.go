@@ -49,6 +49,9 @@ type Median interface {
 	// to true, then transaction will be simulated on the EVM before actual
 	// transaction will be send.
 	Poke(ctx context.Context, prices []*Price, simulateBeforeRun bool) (*ethereum.Hash, error)
+	// PokeCalldata returns the calldata of the transaction which would be
+	// sent by the Poke method for the given prices. No transaction is sent.
+	PokeCalldata(prices []*Price) ([]byte, error)
 	// Lift sends transaction to the smart contract which invokes contract's
 	// lift method, which sends  adds given addresses to the feeders list (orcls).
 	// If you set simulateBeforeRun to true, then transaction will be simulated
@@ -21,17 +21,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"strings"
 	"time"
 
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
 	"github.com/makerdao/oracle-suite/pkg/log"
+	"github.com/makerdao/oracle-suite/pkg/stark"
 )
 
 const PriceMultiplier = 1e18
 
+// StarkOracleName is the name of the oracle used in the StarkEx price hash.
+const StarkOracleName = "Maker"
+
 var ErrPriceNotSet = errors.New("unable to sign a price because the price is not set")
+var ErrStarkSignatureNotSet = errors.New("price does not have a Stark signature")
+var ErrInvalidStarkSignature = errors.New("invalid Stark signature")
+var ErrInvalidStarkPrice = errors.New("price cannot be represented in the StarkEx price format")
 var ErrUnmarshallingFailure = errors.New("unable to unmarshal given JSON")
 
 func errUnmarshalling(s string, err error) error {
@@ -107,6 +115,59 @@ func (p *Price) Sign(signer ethereum.Signer) error {
 	return nil
 }
 
+// SignStark signs the price using the StarkEx oracle price format and
+// stores the signature and the signer's public key in the StarkR, StarkS
+// and StarkPK fields.
+func (p *Price) SignStark(signer stark.Signer) error {
+	if p.Val == nil {
+		return ErrPriceNotSet
+	}
+
+	hash, err := p.starkHash()
+	if err != nil {
+		return err
+	}
+
+	r, s, err := signer.Sign(hash)
+	if err != nil {
+		return err
+	}
+
+	p.StarkR = r.Bytes()
+	p.StarkS = s.Bytes()
+	p.StarkPK = signer.PublicKey().Bytes()
+
+	return nil
+}
+
+// VerifyStark verifies the Stark signature against the public key stored
+// in the StarkPK field.
+func (p *Price) VerifyStark() error {
+	if len(p.StarkR) == 0 || len(p.StarkS) == 0 || len(p.StarkPK) == 0 {
+		return ErrStarkSignatureNotSet
+	}
+	if p.Val == nil {
+		return ErrPriceNotSet
+	}
+
+	hash, err := p.starkHash()
+	if err != nil {
+		return err
+	}
+
+	ok := stark.Verify(
+		hash,
+		new(big.Int).SetBytes(p.StarkR),
+		new(big.Int).SetBytes(p.StarkS),
+		new(big.Int).SetBytes(p.StarkPK),
+	)
+	if !ok {
+		return ErrInvalidStarkSignature
+	}
+
+	return nil
+}
+
 func (p *Price) Signature() ethereum.Signature {
 	return ethereum.SignatureFromVRS(p.V, p.R, p.S)
 }
@@ -239,3 +300,36 @@ func (p *Price) hash() []byte {
 
 	return ethereum.SHA3Hash(hash)
 }
+
+// starkHash calculates the hash of the price in the StarkEx oracle format:
+//
+//	w1 = asset name (128 bits) | oracle name (40 bits)
+//	w2 = price (120 bits) | timestamp (32 bits)
+//	hash = pedersen(w1, w2)
+//
+// The asset name is right-padded with zeros.
+func (p *Price) starkHash() (*big.Int, error) {
+	if len(p.Wat) > 16 {
+		return nil, fmt.Errorf("%w: asset name is longer than 16 bytes", ErrInvalidStarkPrice)
+	}
+	if p.Val.Sign() < 0 || p.Val.BitLen() > 120 {
+		return nil, fmt.Errorf("%w: price does not fit in 120 bits", ErrInvalidStarkPrice)
+	}
+	age := p.Age.Unix()
+	if age < 0 || age > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: timestamp does not fit in 32 bits", ErrInvalidStarkPrice)
+	}
+
+	// Asset name and oracle name:
+	wat := make([]byte, 16)
+	copy(wat, p.Wat)
+	w1 := new(big.Int).SetBytes(wat)
+	w1.Lsh(w1, 40)
+	w1.Or(w1, new(big.Int).SetBytes([]byte(StarkOracleName)))
+
+	// Price and timestamp:
+	w2 := new(big.Int).Lsh(p.Val, 32)
+	w2.Or(w2, big.NewInt(age))
+
+	return stark.PedersenHash(w1, w2)
+}
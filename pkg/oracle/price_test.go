@@ -18,7 +18,9 @@ package oracle
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"math"
+	"math/big"
 	"testing"
 	"time"
 
@@ -26,6 +28,7 @@ import (
 
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
 	"github.com/makerdao/oracle-suite/pkg/ethereum/mocks"
+	"github.com/makerdao/oracle-suite/pkg/stark"
 )
 
 // Hash for the AAABBB asset pair, with the price set to 42 and the age to 1605371361:
@@ -102,6 +105,48 @@ func TestPrice_Sign_NoPrice(t *testing.T) {
 	assert.Equal(t, ErrPriceNotSet, err)
 }
 
+func TestPrice_starkHash(t *testing.T) {
+	// Example from the StarkEx documentation:
+	val, _ := new(big.Int).SetString("11512340000000000000000", 10)
+	p := &Price{Wat: "BTCUSD", Val: val, Age: time.Unix(1577836800, 0)}
+
+	hash, err := p.starkHash()
+	assert.NoError(t, err)
+	assert.Equal(t, "3e4113feb6c403cb0c954e5c09d239bf88fedb075220270f44173ac3cd41858", hash.Text(16))
+}
+
+func TestPrice_SignStark(t *testing.T) {
+	s, err := stark.NewSigner(stark.GrindKey([]byte("seed")))
+	assert.NoError(t, err)
+	p := &Price{Wat: "AAABBB"}
+	p.Age = time.Unix(1605371361, 0)
+	p.SetFloat64Price(42)
+
+	assert.Equal(t, ErrStarkSignatureNotSet, p.VerifyStark())
+
+	err = p.SignStark(s)
+	assert.NoError(t, err)
+	assert.Equal(t, s.PublicKey().Bytes(), p.StarkPK)
+	assert.NoError(t, p.VerifyStark())
+
+	// Signature must not be valid for a different price:
+	p.SetFloat64Price(43)
+	assert.Equal(t, ErrInvalidStarkSignature, p.VerifyStark())
+}
+
+func TestPrice_SignStark_InvalidPrice(t *testing.T) {
+	s, err := stark.NewSigner(stark.GrindKey([]byte("seed")))
+	assert.NoError(t, err)
+
+	err = (&Price{Wat: "AAABBB"}).SignStark(s)
+	assert.Equal(t, ErrPriceNotSet, err)
+
+	p := &Price{Wat: "AAABBBCCCDDDEEEFFF", Age: time.Unix(1605371361, 0)}
+	p.SetFloat64Price(42)
+	err = p.SignStark(s)
+	assert.True(t, errors.Is(err, ErrInvalidStarkPrice))
+}
+
 func TestPrice_Marshall(t *testing.T) {
 	p := &Price{Wat: "AAABBB"}
 	p.Age = time.Unix(1605371361, 0)
@@ -0,0 +1,184 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package rpcsec secures net/rpc servers served over HTTP, like the Gofer
+// and Spire agents. It adds TLS with optional client certificates, bearer
+// token authentication and support for Unix domain sockets.
+package rpcsec
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+	"os"
+	"strings"
+)
+
+// unixPrefix is the prefix of addresses of Unix domain sockets.
+const unixPrefix = "unix:"
+
+// connected is the response sent by the net/rpc server after a successful
+// CONNECT request.
+const connected = "200 Connected to Go RPC"
+
+var ErrUnauthorized = errors.New("unauthorized")
+var ErrAddressInUse = errors.New("address already in use")
+
+// ServerConfig configures the security of an RPC server.
+type ServerConfig struct {
+	// TLS is the TLS configuration of the server. If nil, TLS is disabled.
+	TLS *tls.Config
+	// Token is the bearer token required from clients. If empty,
+	// the authentication is disabled.
+	Token string
+}
+
+// ClientConfig configures the security of an RPC client.
+type ClientConfig struct {
+	// TLS is the TLS configuration of the client. If nil, TLS is disabled.
+	TLS *tls.Config
+	// Token is the bearer token sent to the server. If empty, no token is
+	// sent.
+	Token string
+}
+
+// ParseAddress returns the network and the address for the given address.
+// Addresses prefixed with "unix:" are Unix domain socket paths, all other
+// addresses are TCP addresses.
+func ParseAddress(addr string) (network string, address string) {
+	if strings.HasPrefix(addr, unixPrefix) {
+		return "unix", strings.TrimPrefix(addr, unixPrefix)
+	}
+	return "tcp", addr
+}
+
+// Listen announces on the local network address. If tlsConfig is not nil,
+// accepted connections are wrapped in TLS. A stale Unix domain socket file
+// left by a previous process is removed before listening, and the new one
+// is accessible only by the owner and the group. If another process is
+// still listening on the socket, ErrAddressInUse is returned.
+func Listen(network, address string, tlsConfig *tls.Config) (net.Listener, error) {
+	if network == "unix" {
+		if err := removeStaleSocket(address); err != nil {
+			return nil, err
+		}
+	}
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	if network == "unix" {
+		if err := os.Chmod(address, 0660); err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+	}
+	if tlsConfig != nil {
+		l = tls.NewListener(l, tlsConfig)
+	}
+	return l, nil
+}
+
+// removeStaleSocket removes the Unix domain socket file if nothing listens
+// on it.
+func removeStaleSocket(address string) error {
+	fi, err := os.Stat(address)
+	if err != nil || fi.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	if conn, err := net.Dial("unix", address); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("%w: %s", ErrAddressInUse, address)
+	}
+	return os.Remove(address)
+}
+
+// Handler returns the handler which requires the bearer token before
+// passing requests to the given handler. If the token is empty, the handler
+// is returned unchanged.
+func Handler(h http.Handler, token string) http.Handler {
+	if token == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Authorized(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, ErrUnauthorized.Error(), http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Authorized checks if the request contains the given bearer token.
+func Authorized(r *http.Request, token string) bool {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, prefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, prefix)), []byte(token)) == 1
+}
+
+// DialHTTP connects to a net/rpc server served over HTTP at the default
+// RPC path. It works like rpc.DialHTTP, but it supports TLS and sends
+// the bearer token.
+func DialHTTP(network, address string, cfg ClientConfig) (*rpc.Client, error) {
+	var conn net.Conn
+	var err error
+	if cfg.TLS != nil {
+		conn, err = tls.Dial(network, address, cfg.TLS)
+	} else {
+		conn, err = net.Dial(network, address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req := "CONNECT " + rpc.DefaultRPCPath + " HTTP/1.0\n"
+	if cfg.Token != "" {
+		req += "Authorization: Bearer " + cfg.Token + "\n"
+	}
+	if _, err := io.WriteString(conn, req+"\n"); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	// Require a successful HTTP response before switching to RPC protocol:
+	resp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: "CONNECT"})
+	if err == nil && resp.Status == connected {
+		return rpc.NewClient(conn), nil
+	}
+	if err == nil {
+		if resp.StatusCode == http.StatusUnauthorized {
+			err = ErrUnauthorized
+		} else {
+			err = fmt.Errorf("unexpected HTTP response: %s", resp.Status)
+		}
+	}
+	_ = conn.Close()
+	return nil, &net.OpError{
+		Op:   "dial-http",
+		Net:  network + " " + address,
+		Addr: nil,
+		Err:  err,
+	}
+}
@@ -0,0 +1,230 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsec
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/rpc"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+)
+
+type Echo struct{}
+
+func (Echo) Echo(arg string, resp *string) error {
+	*resp = arg
+	return nil
+}
+
+func startServer(t *testing.T, network, address string, cfg ServerConfig) net.Listener {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.Register(Echo{}))
+	l, err := Listen(network, address, cfg.TLS)
+	require.NoError(t, err)
+	go func() { _ = http.Serve(l, Handler(srv, cfg.Token)) }()
+	t.Cleanup(func() { _ = l.Close() })
+	return l
+}
+
+func call(network, address string, cfg ClientConfig) error {
+	c, err := DialHTTP(network, address, cfg)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	var resp string
+	if err := c.Call("Echo.Echo", "ping", &resp); err != nil {
+		return err
+	}
+	if resp != "ping" {
+		return errors.New("unexpected response")
+	}
+	return nil
+}
+
+func TestParseAddress(t *testing.T) {
+	network, address := ParseAddress("127.0.0.1:8080")
+	assert.Equal(t, "tcp", network)
+	assert.Equal(t, "127.0.0.1:8080", address)
+
+	network, address = ParseAddress("unix:/var/run/agent.sock")
+	assert.Equal(t, "unix", network)
+	assert.Equal(t, "/var/run/agent.sock", address)
+}
+
+func TestToken(t *testing.T) {
+	l := startServer(t, "tcp", "127.0.0.1:0", ServerConfig{Token: "secret"})
+
+	assert.NoError(t, call("tcp", l.Addr().String(), ClientConfig{Token: "secret"}))
+	assert.True(t, errors.Is(call("tcp", l.Addr().String(), ClientConfig{Token: "invalid"}), ErrUnauthorized))
+	assert.True(t, errors.Is(call("tcp", l.Addr().String(), ClientConfig{}), ErrUnauthorized))
+}
+
+func TestUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	startServer(t, "unix", path, ServerConfig{})
+
+	assert.NoError(t, call("unix", path, ClientConfig{}))
+}
+
+func TestUnixSocket_InUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	startServer(t, "unix", path, ServerConfig{})
+
+	// The socket of a running server must not be taken over:
+	_, err := Listen("unix", path, nil)
+	assert.True(t, errors.Is(err, ErrAddressInUse))
+	assert.NoError(t, call("unix", path, ClientConfig{}))
+}
+
+func TestUnixSocket_Stale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+
+	// Simulate a socket file left by a process which was killed:
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, l.Close())
+
+	startServer(t, "unix", path, ServerConfig{})
+	assert.NoError(t, call("unix", path, ClientConfig{}))
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := writeCert(t, dir, "ca", nil, nil)
+	writeCert(t, dir, "server", ca, caKey)
+	writeCert(t, dir, "client", ca, caKey)
+
+	cfg := TLS{
+		Enable:         true,
+		CertFile:       filepath.Join(dir, "server.crt"),
+		KeyFile:        filepath.Join(dir, "server.key"),
+		ClientCAFile:   filepath.Join(dir, "ca.crt"),
+		CAFile:         filepath.Join(dir, "ca.crt"),
+		ClientCertFile: filepath.Join(dir, "client.crt"),
+		ClientKeyFile:  filepath.Join(dir, "client.key"),
+		ServerName:     "localhost",
+	}
+	var errs validation.Errors
+	cfg.ValidateAgent(&errs, "rpc", "tls")
+	cfg.ValidateClient(&errs, "rpc", "tls")
+	require.NoError(t, errs.Err())
+
+	serverTLS, err := cfg.ServerConfig()
+	require.NoError(t, err)
+	clientTLS, err := cfg.ClientConfig()
+	require.NoError(t, err)
+	l := startServer(t, "tcp", "127.0.0.1:0", ServerConfig{TLS: serverTLS})
+
+	assert.NoError(t, call("tcp", l.Addr().String(), ClientConfig{TLS: clientTLS}))
+
+	// The agent requires a client certificate:
+	noCert := cfg
+	noCert.ClientCertFile = ""
+	noCert.ClientKeyFile = ""
+	noCertTLS, err := noCert.ClientConfig()
+	require.NoError(t, err)
+	assert.Error(t, call("tcp", l.Addr().String(), ClientConfig{TLS: noCertTLS}))
+
+	// The agent does not use TLS:
+	assert.Error(t, call("tcp", l.Addr().String(), ClientConfig{}))
+}
+
+func TestTLS_ValidateAgent(t *testing.T) {
+	var errs validation.Errors
+	TLS{Enable: true, CertFile: "server.crt", ClientCAFile: "/nonexistent"}.ValidateAgent(&errs, "rpc", "tls")
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, "/rpc/tls/keyFile", errs[0].Pointer)
+	assert.Equal(t, "/rpc/tls/clientCAFile", errs[1].Pointer)
+
+	// The certificate and key are required:
+	errs = nil
+	TLS{Enable: true}.ValidateAgent(&errs, "rpc", "tls")
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/rpc/tls/certFile", errs[0].Pointer)
+
+	// Disabled TLS is not validated:
+	errs = nil
+	TLS{CertFile: "server.crt"}.ValidateAgent(&errs, "rpc", "tls")
+	assert.NoError(t, errs.Err())
+}
+
+func TestTLS_ValidateClient(t *testing.T) {
+	var errs validation.Errors
+	TLS{Enable: true, CAFile: "/nonexistent", ClientCertFile: "client.crt"}.ValidateClient(&errs, "rpc", "tls")
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, "/rpc/tls/caFile", errs[0].Pointer)
+	assert.Equal(t, "/rpc/tls/clientKeyFile", errs[1].Pointer)
+
+	// The client doesn't need the agent certificate:
+	errs = nil
+	TLS{Enable: true}.ValidateClient(&errs, "rpc", "tls")
+	assert.NoError(t, errs.Err())
+}
+
+// writeCert writes the PEM encoded certificate and key, signed by
+// the given CA, to the dir. If the CA is nil, a self-signed CA is created.
+func writeCert(t *testing.T, dir, name string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if ca == nil {
+		tpl.IsCA = true
+		tpl.BasicConstraintsValid = true
+		tpl.KeyUsage |= x509.KeyUsageCertSign
+		ca, caKey = tpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".key"), keyPEM, 0600))
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
@@ -0,0 +1,170 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpcsec
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+)
+
+var errNoCertificates = errors.New("no certificates found in the file")
+
+// TLS is the TLS configuration used in the rpc sections of configuration
+// files. The same section is used by the agent and by clients, so it
+// contains options for both sides.
+type TLS struct {
+	// Enable enables TLS.
+	Enable bool `json:"enable"`
+	// CertFile and KeyFile are paths to the PEM encoded certificate and
+	// key of the agent.
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	// ClientCAFile is the path to the PEM encoded CA certificates used by
+	// the agent to verify client certificates. If set, clients must provide
+	// a valid certificate.
+	ClientCAFile string `json:"clientCAFile"`
+	// CAFile is the path to the PEM encoded CA certificates used by clients
+	// to verify the agent certificate. If empty, system CAs are used.
+	CAFile string `json:"caFile"`
+	// ClientCertFile and ClientKeyFile are paths to the PEM encoded
+	// certificate and key used by clients if the agent requires client
+	// certificates.
+	ClientCertFile string `json:"clientCertFile"`
+	ClientKeyFile  string `json:"clientKeyFile"`
+	// ServerName is used by clients to verify the hostname of the agent
+	// certificate. If empty, the host from the address is used.
+	ServerName string `json:"serverName"`
+}
+
+// ServerConfig returns the TLS configuration for the agent. It returns nil
+// if TLS is disabled.
+func (c TLS) ServerConfig() (*tls.Config, error) {
+	if !c.Enable {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if c.ClientCAFile != "" {
+		pool, err := loadCertPool(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// ClientConfig returns the TLS configuration for clients. It returns nil
+// if TLS is disabled.
+func (c TLS) ClientConfig() (*tls.Config, error) {
+	if !c.Enable {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+	}
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	if c.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// ValidateAgent adds problems found in the agent options to errs. The
+// certificate and key are required if TLS is enabled. The pointer is
+// the JSON pointer to the TLS section.
+func (c TLS) ValidateAgent(errs *validation.Errors, pointer ...interface{}) {
+	if !c.Enable {
+		return
+	}
+	path := tlsPointer(pointer)
+	switch {
+	case c.CertFile == "":
+		errs.Add(path("certFile"), "certFile is required if TLS is enabled")
+	case c.KeyFile == "":
+		errs.Add(path("keyFile"), "keyFile is required if TLS is enabled")
+	default:
+		if _, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile); err != nil {
+			errs.Add(path("certFile"), "%s", err)
+		}
+	}
+	if c.ClientCAFile != "" {
+		if _, err := loadCertPool(c.ClientCAFile); err != nil {
+			errs.Add(path("clientCAFile"), "%s", err)
+		}
+	}
+}
+
+// ValidateClient adds problems found in the client options to errs. The
+// pointer is the JSON pointer to the TLS section.
+func (c TLS) ValidateClient(errs *validation.Errors, pointer ...interface{}) {
+	if !c.Enable {
+		return
+	}
+	path := tlsPointer(pointer)
+	if c.CAFile != "" {
+		if _, err := loadCertPool(c.CAFile); err != nil {
+			errs.Add(path("caFile"), "%s", err)
+		}
+	}
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		errs.Add(path("clientKeyFile"), "clientCertFile and clientKeyFile must be set together")
+	} else if c.ClientCertFile != "" {
+		if _, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile); err != nil {
+			errs.Add(path("clientCertFile"), "%s", err)
+		}
+	}
+}
+
+func tlsPointer(pointer []interface{}) func(field string) string {
+	return func(field string) string {
+		return validation.Pointer(append(append([]interface{}{}, pointer...), field)...)
+	}
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, errNoCertificates
+	}
+	return pool, nil
+}
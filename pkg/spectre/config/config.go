@@ -16,49 +16,69 @@
 package config
 
 import (
-	"bytes"
 	"context"
-	"crypto/ed25519"
-	"crypto/rand"
-	"encoding/hex"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"strings"
+	"math/big"
+	"net/url"
 	"time"
 
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/libp2p/go-libp2p-core/crypto"
-
 	suite "github.com/makerdao/oracle-suite"
+	"github.com/makerdao/oracle-suite/pkg/config/shared"
 	"github.com/makerdao/oracle-suite/pkg/datastore"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
 	ethereumGeth "github.com/makerdao/oracle-suite/pkg/ethereum/geth"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+	goferRPC "github.com/makerdao/oracle-suite/pkg/gofer/rpc"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
 	oracleGeth "github.com/makerdao/oracle-suite/pkg/oracle/geth"
+	"github.com/makerdao/oracle-suite/pkg/rpcsec"
 	"github.com/makerdao/oracle-suite/pkg/spectre"
 	"github.com/makerdao/oracle-suite/pkg/transport"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
 	"github.com/makerdao/oracle-suite/pkg/transport/p2p"
+	"github.com/makerdao/oracle-suite/pkg/transport/p2p/crypto/ethkey"
 )
 
 var ErrFailedToLoadConfiguration = errors.New("failed to load Spectre's configuration")
-var ErrFailedToReadPassphraseFile = errors.New("failed to read the ethereum password file")
-var ErrFailedToParsePrivKeySeed = errors.New("failed to parse the privKeySeed field")
+var ErrFailedToReadPassphraseFile = shared.ErrFailedToReadPassphraseFile
+var ErrFailedToParsePrivKeySeed = shared.ErrFailedToParsePrivKeySeed
 
 type Config struct {
-	Ethereum    Ethereum              `json:"ethereum"`
-	P2P         P2P                   `json:"p2p"`
-	Options     Options               `json:"options"`
-	Feeds       []string              `json:"feeds"`
-	Medianizers map[string]Medianizer `json:"medianizers"`
+	Ethereum     Ethereum              `json:"ethereum"`
+	P2P          P2P                   `json:"p2p"`
+	Options      Options               `json:"options"`
+	Feeds        []string              `json:"feeds"`
+	Medianizers  map[string]Medianizer `json:"medianizers"`
+	OSMs         map[string]OSM        `json:"osms"`
+	Transactions Transactions          `json:"transactions"`
+	Coordination Coordination          `json:"coordination"`
+	Reference    Reference             `json:"reference"`
+	Datastore    Datastore             `json:"datastore"`
+	Health       Health                `json:"health"`
 }
 
 type Ethereum struct {
 	From     string `json:"from"`
 	Keystore string `json:"keystore"`
 	Password string `json:"password"`
-	RPC      string `json:"rpc"`
+	// RemoteSigner is the address of an external signer, like Clef, which
+	// holds the key of the "from" account. If set, the keystore and
+	// password are not used.
+	RemoteSigner string `json:"remoteSigner"`
+	RPC          string `json:"rpc"`
+	// RPCs is the list of additional RPC addresses. If more than one address
+	// is configured, requests fail over to the next endpoint on errors and
+	// transactions are sent to all endpoints.
+	RPCs []string `json:"rpcs"`
+	// Quorum is the number of endpoints which must return the same result
+	// for contract reads. If less than two, the first working endpoint
+	// is used.
+	Quorum int `json:"quorum"`
+	// GasLimitMultiplier is the multiplier applied to the estimated gas
+	// limit. If zero, the default multiplier is used.
+	GasLimitMultiplier float64 `json:"gasLimitMultiplier"`
 }
 
 type P2P struct {
@@ -72,6 +92,73 @@ type P2P struct {
 
 type Options struct {
 	Interval int `json:"interval"`
+	// Workers is the number of medianizers which may be updated
+	// concurrently. If zero, the default value is used.
+	Workers int `json:"workers"`
+	// GasPriceLimit is the gas price in gwei above which medianizers are
+	// updated one by one in the order of priority instead of concurrently.
+	// If zero, there is no limit.
+	GasPriceLimit float64 `json:"gasPriceLimit"`
+	// DryRun disables sending transactions. Instead, the decision whether
+	// Oracles and OSMs would be updated is logged.
+	DryRun bool `json:"dryRun"`
+}
+
+// Transactions configures how pending transactions are tracked and
+// replaced. Zero values mean defaults.
+type Transactions struct {
+	// ReplaceAfter is the time in seconds after which a pending transaction
+	// is replaced with the same nonce and higher fees.
+	ReplaceAfter int `json:"replaceAfter"`
+	// FeeBump is the multiplier applied to the fees of a replaced
+	// transaction, it must be at least 1.1.
+	FeeBump float64 `json:"feeBump"`
+	// MaxReplacements is the number of replacements after which
+	// the transaction is no longer tracked.
+	MaxReplacements int `json:"maxReplacements"`
+	// PollInterval is the interval in seconds between receipt checks.
+	PollInterval int `json:"pollInterval"`
+}
+
+// Coordination configures how multiple Spectre instances coordinate Oracle
+// updates to avoid sending duplicated transactions.
+type Coordination struct {
+	// Relayers is the list of addresses of all coordinated relayers,
+	// including this one. If empty, the coordination is disabled.
+	Relayers []string `json:"relayers"`
+	// Backoff is the time in seconds for which a relayer waits for relayers
+	// before it in the schedule. If zero, the default value is used.
+	Backoff int `json:"backoff"`
+}
+
+// Reference configures the Gofer used to cross-check prices from feeds
+// before updating medianizers with the maxDeviationFromReference option.
+type Reference struct {
+	// RPC is the address of the Gofer RPC agent. If empty, the Gofer is
+	// configured using the gofer section of the config file.
+	RPC string `json:"rpc"`
+	// Token is the bearer token sent to the Gofer RPC agent.
+	Token string `json:"token"`
+	// TLS configures TLS for the connection to the Gofer RPC agent.
+	TLS rpcsec.TLS `json:"tls"`
+}
+
+// Datastore configures the persistent storage for prices received from
+// feeds, so they are available after a restart.
+type Datastore struct {
+	// Path is the path to the file in which prices are stored. If empty,
+	// prices are kept only in memory.
+	Path string `json:"path"`
+	// Expiration is the time in seconds after which stored prices are
+	// removed. If zero, the longest msgExpiration of
+	// medianizers is used.
+	Expiration int `json:"expiration"`
+}
+
+type Health struct {
+	// Address is the address on which the /healthz and /readyz endpoints are
+	// served. If empty, the endpoints are disabled.
+	Address string `json:"address"`
 }
 
 type Medianizer struct {
@@ -79,30 +166,77 @@ type Medianizer struct {
 	OracleSpread     float64 `json:"oracleSpread"`
 	OracleExpiration int64   `json:"oracleExpiration"`
 	MsgExpiration    int64   `json:"msgExpiration"`
+	// Feeds is the list of feeds from which prices for this medianizer are
+	// accepted. If empty, the global feeds list is used.
+	Feeds []string `json:"feeds"`
+	// DynamicFees enables EIP-1559 transactions for the medianizer.
+	DynamicFees bool `json:"dynamicFees"`
+	// MaxFee is the upper limit for the max fee per gas in gwei, or for
+	// the gas price if dynamic fees are disabled. If zero, there is no limit.
+	MaxFee float64 `json:"maxFee"`
+	// MaxPriorityFee is the upper limit for the priority fee per gas in gwei.
+	// If zero, there is no limit.
+	MaxPriorityFee float64 `json:"maxPriorityFee"`
+	// Selection is the strategy used to choose prices sent to the Oracle
+	// when there are more prices than required to achieve a quorum. It can
+	// be "freshest" or "median". If empty, "freshest" is used.
+	Selection string `json:"selection"`
+	// Interval is the time in seconds between update attempts. If zero,
+	// the global interval is used.
+	Interval int `json:"interval"`
+	// Priority determines the order in which medianizers are updated,
+	// medianizers with a higher priority are updated first.
+	Priority int `json:"priority"`
+	// MaxDeviationFromReference is the maximum deviation, in percentage
+	// points, between the median of feed prices and the price from
+	// the reference Gofer. If it is exceeded, the medianizer is not updated.
+	// If zero, prices are not cross-checked.
+	MaxDeviationFromReference float64 `json:"maxDeviationFromReference"`
+}
+
+// OSM is the Oracle Security Module contract poked by Spectre whenever
+// it can be poked.
+type OSM struct {
+	Contract string `json:"oracle"`
+	// DynamicFees, MaxFee and MaxPriorityFee are the same as in
+	// the Medianizer.
+	DynamicFees    bool    `json:"dynamicFees"`
+	MaxFee         float64 `json:"maxFee"`
+	MaxPriorityFee float64 `json:"maxPriorityFee"`
 }
 
 type Dependencies struct {
 	Context context.Context
-	Logger  log.Logger
+	// Gofer is used as the reference Gofer if the reference RPC address
+	// is not configured.
+	Gofer  gofer.Gofer
+	Logger log.Logger
 }
 
 type Instances struct {
-	Ethereum  ethereum.Client
-	Signer    ethereum.Signer
-	Transport transport.Transport
-	Spectre   *spectre.Spectre
+	Ethereum  *ethereumGeth.MultiClient
+	TxManager *ethereum.TxManager
+	// Coordinator coordinates Oracle updates with other Spectre instances,
+	// it is nil if the coordination is disabled.
+	Coordinator *spectre.TransportCoordinator
+	Signer      ethereum.Signer
+	Transport   transport.Transport
+	Datastore   *datastore.Datastore
+	Spectre     *spectre.Spectre
+	// Gofer is the reference Gofer, it is nil if no medianizer uses it.
+	Gofer gofer.Gofer
+	// Health is the server for the health endpoints, it is nil if
+	// the endpoints are disabled.
+	Health *health.Server
 }
 
 func (c *Config) Configure(deps Dependencies) (*Instances, error) {
-	// Ethereum account:
-	acc, err := c.configureAccount()
+	// Signer for the Ethereum account:
+	sig, err := c.configureSigner()
 	if err != nil {
 		return nil, fmt.Errorf("(account) %v: %v", ErrFailedToLoadConfiguration, err)
 	}
 
-	// Signer:
-	sig := c.configureSigner(acc)
-
 	// Transport:
 	tra, err := c.configureTransport(deps.Context, sig, deps.Logger)
 	if err != nil {
@@ -110,27 +244,74 @@ func (c *Config) Configure(deps Dependencies) (*Instances, error) {
 	}
 
 	// Create Ethereum client:
-	eth, err := c.configureEthClient(sig)
+	eth, err := c.configureEthClient(sig, deps.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("(ethereum) %v: %v", ErrFailedToLoadConfiguration, err)
 	}
 
+	// Transaction manager:
+	txm := c.configureTxManager(eth, deps.Logger)
+
 	// Datastore:
-	dat := c.configureDatastore(sig, tra, deps.Logger)
+	dat, err := c.configureDatastore(sig, tra, deps.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("(datastore) %v: %v", ErrFailedToLoadConfiguration, err)
+	}
+
+	// Coordinator:
+	coo := c.configureCoordinator(deps.Context, sig, tra, deps.Logger)
+
+	// Reference Gofer:
+	gof, err := c.configureReference(deps.Gofer)
+	if err != nil {
+		return nil, fmt.Errorf("(reference) %v: %v", ErrFailedToLoadConfiguration, err)
+	}
 
 	// Create and configure Spectre:
-	spe := c.configureSpectre(sig, dat, deps.Logger, eth)
+	spe, err := c.configureSpectre(sig, dat, deps.Logger, txm, coo, eth, gof)
+	if err != nil {
+		return nil, fmt.Errorf("(spectre) %v: %v", ErrFailedToLoadConfiguration, err)
+	}
+
+	// Health endpoints:
+	checkers := map[string]health.Checker{
+		"spectre":   spe,
+		"datastore": dat,
+		"ethereum":  eth,
+		"transport": tra,
+	}
+	if hc, ok := gof.(health.Checker); ok {
+		checkers["gofer"] = hc
+	}
+	hs := c.configureHealthServer(checkers, deps.Logger)
 
 	return &Instances{
-		Ethereum:  eth,
-		Signer:    sig,
-		Transport: tra,
-		Spectre:   spe,
+		Ethereum:    eth,
+		TxManager:   txm,
+		Coordinator: coo,
+		Signer:      sig,
+		Transport:   tra,
+		Datastore:   dat,
+		Spectre:     spe,
+		Gofer:       gof,
+		Health:      hs,
 	}, nil
 }
 
+func (c *Config) configureHealthServer(checkers map[string]health.Checker, l log.Logger) *health.Server {
+	if c.Health.Address == "" {
+		return nil
+	}
+	return health.NewServer(health.Config{
+		Checkers: checkers,
+		Network:  "tcp",
+		Address:  c.Health.Address,
+		Logger:   l,
+	})
+}
+
 func (c *Config) configureAccount() (*ethereumGeth.Account, error) {
-	passphrase, err := c.readAccountPassphrase(c.Ethereum.Password)
+	passphrase, err := shared.ReadAccountPassphrase(c.Ethereum.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -147,21 +328,30 @@ func (c *Config) configureAccount() (*ethereumGeth.Account, error) {
 	return a, nil
 }
 
-func (c *Config) configureSigner(a *ethereumGeth.Account) ethereum.Signer {
-	return ethereumGeth.NewSigner(a)
+func (c *Config) configureSigner() (ethereum.Signer, error) {
+	if c.Ethereum.RemoteSigner != "" {
+		return ethereumGeth.DialRemoteSigner(c.Ethereum.RemoteSigner, ethereum.HexToAddress(c.Ethereum.From))
+	}
+	a, err := c.configureAccount()
+	if err != nil {
+		return nil, err
+	}
+	return ethereumGeth.NewSigner(a), nil
 }
 
-func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l log.Logger) (transport.Transport, error) {
-	peerPrivKey, err := c.generatePrivKey()
+func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l log.Logger) (*p2p.P2P, error) {
+	peerPrivKey, err := shared.GeneratePrivKey(c.P2P.PrivKeySeed)
 	if err != nil {
 		return nil, err
 	}
 
-	// Spectre doesn't create any messages so there is no need to
-	// configure the MessagePrivKey field.
+	// Spectre broadcasts relay intents when coordination with other relayers
+	// is enabled, so messages are signed with the relayer's key, the same
+	// way as Ghost and Spire do for their messages.
 	cfg := p2p.Config{
 		Context:          ctx,
 		PeerPrivKey:      peerPrivKey,
+		MessagePrivKey:   ethkey.NewPrivKey(s),
 		ListenAddrs:      c.P2P.ListenAddrs,
 		BootstrapAddrs:   c.P2P.BootstrapAddrs,
 		DirectPeersAddrs: c.P2P.DirectPeersAddrs,
@@ -172,9 +362,7 @@ func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l lo
 		AppName:          "spectre",
 		AppVersion:       suite.Version,
 	}
-	for _, feed := range c.Feeds {
-		cfg.FeedersAddrs = append(cfg.FeedersAddrs, ethereum.HexToAddress(feed))
-	}
+	cfg.FeedersAddrs = c.allFeeds()
 
 	p, err := p2p.New(cfg)
 	if err != nil {
@@ -186,93 +374,266 @@ func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l lo
 		_ = p.Close()
 		return nil, err
 	}
+	if len(c.Coordination.Relayers) > 0 {
+		err = p.Subscribe(messages.RelayIntentMessageName, (*messages.RelayIntent)(nil))
+		if err != nil {
+			_ = p.Close()
+			return nil, err
+		}
+	}
 
 	return p, nil
 }
 
-func (c *Config) configureEthClient(s ethereum.Signer) (*ethereumGeth.Client, error) {
-	client, err := ethclient.Dial(c.Ethereum.RPC)
-	if err != nil {
-		return nil, err
+func (c *Config) configureEthClient(s ethereum.Signer, l log.Logger) (*ethereumGeth.MultiClient, error) {
+	var endpoints []ethereumGeth.Endpoint
+	for i, addr := range c.rpcAddresses() {
+		client, err := ethereumGeth.DialEthClient(addr)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, ethereumGeth.Endpoint{
+			Name: endpointName(i, addr),
+			Client: ethereumGeth.NewClientWithOptions(client, s, ethereumGeth.ClientOptions{
+				GasLimitMultiplier: c.Ethereum.GasLimitMultiplier,
+			}),
+		})
 	}
 
-	return ethereumGeth.NewClient(client, s), nil
+	return ethereumGeth.NewMultiClient(ethereumGeth.MultiClientConfig{
+		Endpoints: endpoints,
+		Signer:    s,
+		Quorum:    c.Ethereum.Quorum,
+		Logger:    l,
+	})
 }
 
-func (c *Config) configureDatastore(s ethereum.Signer, t transport.Transport, l log.Logger) *datastore.Datastore {
-	cfg := datastore.Config{
-		Signer:    s,
+// rpcAddresses returns all configured RPC addresses.
+func (c *Config) rpcAddresses() []string {
+	var addrs []string
+	if c.Ethereum.RPC != "" {
+		addrs = append(addrs, c.Ethereum.RPC)
+	}
+	return append(addrs, c.Ethereum.RPCs...)
+}
+
+func (c *Config) configureTxManager(e ethereum.Client, l log.Logger) *ethereum.TxManager {
+	return ethereum.NewTxManager(ethereum.TxManagerConfig{
+		Client:          e,
+		ReplaceAfter:    time.Second * time.Duration(c.Transactions.ReplaceAfter),
+		FeeBump:         c.Transactions.FeeBump,
+		MaxReplacements: c.Transactions.MaxReplacements,
+		PollInterval:    time.Second * time.Duration(c.Transactions.PollInterval),
+		Logger:          l,
+	})
+}
+
+func (c *Config) configureCoordinator(
+	ctx context.Context,
+	s ethereum.Signer,
+	t transport.Transport,
+	l log.Logger,
+) *spectre.TransportCoordinator {
+
+	if len(c.Coordination.Relayers) == 0 {
+		return nil
+	}
+
+	var relayers []ethereum.Address
+	for _, relayer := range c.Coordination.Relayers {
+		relayers = append(relayers, ethereum.HexToAddress(relayer))
+	}
+
+	return spectre.NewCoordinator(spectre.CoordinatorConfig{
+		Context:   ctx,
 		Transport: t,
-		Pairs:     make(map[string]*datastore.Pair),
+		Signer:    s,
+		Relayers:  relayers,
+		Backoff:   time.Second * time.Duration(c.Coordination.Backoff),
 		Logger:    l,
+	})
+}
+
+// EmbeddedGofer returns true if the reference Gofer must be configured using
+// the gofer section of the config file and passed in the Dependencies.
+func (c *Config) EmbeddedGofer() bool {
+	return c.Reference.RPC == "" && c.referenceRequired()
+}
+
+// referenceRequired returns true if any medianizer uses the reference Gofer.
+func (c *Config) referenceRequired() bool {
+	for _, m := range c.Medianizers {
+		if m.MaxDeviationFromReference > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) configureReference(g gofer.Gofer) (gofer.Gofer, error) {
+	if !c.referenceRequired() {
+		return nil, nil
 	}
+	if c.Reference.RPC != "" {
+		tls, err := c.Reference.TLS.ClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		network, address := rpcsec.ParseAddress(c.Reference.RPC)
+		return goferRPC.NewSecureGofer(network, address, rpcsec.ClientConfig{TLS: tls, Token: c.Reference.Token}), nil
+	}
+	return g, nil
+}
 
-	var feeds []ethereum.Address
-	for _, feed := range c.Feeds {
-		feeds = append(feeds, ethereum.HexToAddress(feed))
+func (c *Config) configureDatastore(
+	s ethereum.Signer,
+	t transport.Transport,
+	l log.Logger,
+) (*datastore.Datastore, error) {
+
+	cfg := datastore.Config{
+		Signer:            s,
+		Transport:         t,
+		Pairs:             make(map[string]*datastore.Pair),
+		StorageExpiration: time.Second * time.Duration(c.Datastore.Expiration),
+		Logger:            l,
+	}
+	if c.Datastore.Path != "" {
+		storage, err := datastore.NewFileStorage(c.Datastore.Path)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Storage = storage
 	}
 
-	for name := range c.Medianizers {
+	for name, m := range c.Medianizers {
+		var feeds []ethereum.Address
+		for _, feed := range c.medianizerFeeds(m) {
+			feeds = append(feeds, ethereum.HexToAddress(feed))
+		}
 		cfg.Pairs[name] = &datastore.Pair{Feeds: feeds}
+
+		// Prices older than msgExpiration are never used, so there is no need
+		// to store them longer:
+		if c.Datastore.Expiration == 0 {
+			if exp := time.Second * time.Duration(m.MsgExpiration); exp > cfg.StorageExpiration {
+				cfg.StorageExpiration = exp
+			}
+		}
 	}
 
-	return datastore.NewDatastore(cfg)
+	return datastore.NewDatastore(cfg), nil
+}
+
+// medianizerFeeds returns the list of feeds used by the medianizer.
+func (c *Config) medianizerFeeds(m Medianizer) []string {
+	if len(m.Feeds) > 0 {
+		return m.Feeds
+	}
+	return c.Feeds
+}
+
+// allFeeds returns addresses of all feeds used by any medianizer and
+// the global feeds list, without duplicates.
+func (c *Config) allFeeds() []ethereum.Address {
+	var addrs []ethereum.Address
+	seen := map[ethereum.Address]bool{}
+	add := func(feeds []string) {
+		for _, feed := range feeds {
+			addr := ethereum.HexToAddress(feed)
+			if !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	add(c.Feeds)
+	for _, m := range c.Medianizers {
+		add(m.Feeds)
+	}
+	return addrs
 }
 
 func (c *Config) configureSpectre(
 	s ethereum.Signer,
 	d spectre.Datastore,
 	l log.Logger,
-	e ethereum.Client,
-) *spectre.Spectre {
+	e *ethereum.TxManager,
+	coo *spectre.TransportCoordinator,
+	g spectre.GasPricer,
+	gof gofer.Gofer,
+) (*spectre.Spectre, error) {
 
 	cfg := spectre.Config{
-		Signer:    s,
-		Interval:  time.Second * time.Duration(c.Options.Interval),
-		Datastore: d,
-		Logger:    l,
-		Pairs:     nil,
+		Signer:        s,
+		TxTracker:     e,
+		Interval:      time.Second * time.Duration(c.Options.Interval),
+		Workers:       c.Options.Workers,
+		GasPricer:     g,
+		GasPriceLimit: gweiToWei(c.Options.GasPriceLimit),
+		DryRun:        c.Options.DryRun,
+		Gofer:         gof,
+		Datastore:     d,
+		Logger:        l,
+		Pairs:         nil,
+	}
+	if coo != nil {
+		cfg.Coordinator = coo
 	}
 
 	for name, pair := range c.Medianizers {
+		sel, err := spectre.SelectorByName(pair.Selection)
+		if err != nil {
+			return nil, err
+		}
 		cfg.Pairs = append(cfg.Pairs, &spectre.Pair{
 			AssetPair:        name,
 			OracleSpread:     pair.OracleSpread,
 			OracleExpiration: time.Second * time.Duration(pair.OracleExpiration),
 			PriceExpiration:  time.Second * time.Duration(pair.MsgExpiration),
-			Median:           oracleGeth.NewMedian(e, ethereum.HexToAddress(pair.Contract)),
+			Median: oracleGeth.NewMedianWithOptions(e, ethereum.HexToAddress(pair.Contract), oracleGeth.TxOptions{
+				DynamicFees:    pair.DynamicFees,
+				MaxFeeCap:      gweiToWei(pair.MaxFee),
+				PriorityFeeCap: gweiToWei(pair.MaxPriorityFee),
+			}),
+			Selector: sel,
+			Interval: time.Second * time.Duration(pair.Interval),
+			Priority: pair.Priority,
+
+			MaxDeviationFromReference: pair.MaxDeviationFromReference,
 		})
 	}
 
-	return spectre.NewSpectre(cfg)
+	for name, osm := range c.OSMs {
+		cfg.OSMs = append(cfg.OSMs, &spectre.OSM{
+			AssetPair: name,
+			OSM: oracleGeth.NewOSMWithOptions(e, ethereum.HexToAddress(osm.Contract), oracleGeth.TxOptions{
+				DynamicFees:    osm.DynamicFees,
+				MaxFeeCap:      gweiToWei(osm.MaxFee),
+				PriorityFeeCap: gweiToWei(osm.MaxPriorityFee),
+			}),
+		})
+	}
+
+	return spectre.NewSpectre(cfg), nil
 }
 
-func (c *Config) generatePrivKey() (crypto.PrivKey, error) {
-	seedReader := rand.Reader
-	if len(c.P2P.PrivKeySeed) != 0 {
-		seed, err := hex.DecodeString(c.P2P.PrivKeySeed)
-		if err != nil {
-			return nil, fmt.Errorf("%v: %v", ErrFailedToParsePrivKeySeed, err)
-		}
-		if len(seed) != ed25519.SeedSize {
-			return nil, fmt.Errorf("%v: seed must be 32 bytes", ErrFailedToParsePrivKeySeed)
-		}
-		seedReader = bytes.NewReader(seed)
-	}
-	privKey, _, err := crypto.GenerateEd25519Key(seedReader)
-	if err != nil {
-		return nil, fmt.Errorf("%v: %v", ErrFailedToParsePrivKeySeed, err)
+// gweiToWei converts the amount in gwei to wei. It returns nil for zero
+// so it can be used for optional limits.
+func gweiToWei(gwei float64) *big.Int {
+	if gwei == 0 {
+		return nil
 	}
-	return privKey, nil
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(1e9)).Int(nil)
+	return wei
 }
 
-func (c *Config) readAccountPassphrase(path string) (string, error) {
-	if path == "" {
-		return "", nil
-	}
-	passphraseFile, err := ioutil.ReadFile(path)
-	if err != nil {
-		return "", fmt.Errorf("%v: %v", ErrFailedToReadPassphraseFile, err)
+// endpointName returns the name of the endpoint used in logs and health
+// details. Only the host is used, because the URL may contain an API key.
+func endpointName(i int, addr string) string {
+	u, err := url.Parse(addr)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("rpc%d", i)
 	}
-	return strings.TrimSuffix(string(passphraseFile), "\n"), nil
+	return fmt.Sprintf("rpc%d (%s)", i, u.Host)
 }
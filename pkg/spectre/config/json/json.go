@@ -13,14 +13,16 @@
 //  You should have received a copy of the GNU Affero General Public License
 //  along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+// Package json is kept for compatibility only.
+//
+// Deprecated: use the loader package instead, which also supports YAML, HCL,
+// environment variables and included files.
 package json
 
 import (
 	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"os"
 
+	"github.com/makerdao/oracle-suite/pkg/config/loader"
 	"github.com/makerdao/oracle-suite/pkg/spectre/config"
 )
 
@@ -32,21 +34,23 @@ func (e ConfigErr) Error() string {
 	return e.Err.Error()
 }
 
-func ParseJSONFile(cfg *config.Config, path string) error {
-	f, err := os.Open(path)
-	if err != nil {
-		return fmt.Errorf("failed to load JSON config file: %w", err)
-	}
-	defer f.Close()
+func (e ConfigErr) Unwrap() error {
+	return e.Err
+}
 
-	b, err := ioutil.ReadAll(f)
-	if err != nil {
-		return ConfigErr{fmt.Errorf("failed to load JSON config file: %w", err)}
+// ParseJSONFile loads the config file using loader.LoadFile.
+//
+// Deprecated: use loader.LoadFile instead.
+func ParseJSONFile(cfg *config.Config, path string) error {
+	if err := loader.LoadFile(cfg, path); err != nil {
+		return ConfigErr{err}
 	}
-
-	return ParseJSON(cfg, b)
+	return nil
 }
 
+// ParseJSON parses the JSON config.
+//
+// Deprecated: use loader.LoadFile instead.
 func ParseJSON(cfg *config.Config, b []byte) error {
 	err := json.Unmarshal(b, cfg)
 	if err != nil {
@@ -0,0 +1,186 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"sort"
+
+	"github.com/makerdao/oracle-suite/pkg/config/shared"
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/spectre"
+)
+
+// Validate checks the configuration and returns validation.Errors with all
+// problems found, or nil if the configuration is valid.
+func (c *Config) Validate() error {
+	var errs validation.Errors
+
+	c.validateEthereum(&errs)
+	shared.ValidateP2P(&errs, c.P2P.PrivKeySeed, shared.P2PAddrs{
+		ListenAddrs:      c.P2P.ListenAddrs,
+		BootstrapAddrs:   c.P2P.BootstrapAddrs,
+		DirectPeersAddrs: c.P2P.DirectPeersAddrs,
+		BlockedAddrs:     c.P2P.BlockedAddrs,
+	})
+
+	if len(c.rpcAddresses()) == 0 {
+		errs.Add(validation.Pointer("ethereum", "rpc"), "RPC address is required")
+	}
+	if c.Ethereum.Quorum < 0 {
+		errs.Add(validation.Pointer("ethereum", "quorum"), "quorum must not be negative")
+	}
+	if c.Ethereum.Quorum > len(c.rpcAddresses()) {
+		errs.Add(validation.Pointer("ethereum", "quorum"), "quorum must not be greater than the number of RPC addresses")
+	}
+	if c.Options.Interval <= 0 {
+		errs.Add(validation.Pointer("options", "interval"), "interval must be greater than zero")
+	}
+	if c.Options.Workers < 0 {
+		errs.Add(validation.Pointer("options", "workers"), "number of workers must not be negative")
+	}
+	if c.Options.GasPriceLimit < 0 {
+		errs.Add(validation.Pointer("options", "gasPriceLimit"), "gas price limit must not be negative")
+	}
+
+	for i, feed := range c.Feeds {
+		if !ethereum.IsHexAddress(feed) {
+			errs.Add(validation.Pointer("feeds", i), "invalid feed address %s", feed)
+		}
+	}
+
+	var names []string
+	for name := range c.Medianizers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m := c.Medianizers[name]
+		// Every medianizer must have its own feeds list or use the global one:
+		if len(c.medianizerFeeds(m)) == 0 {
+			errs.Add(validation.Pointer("medianizers", name, "feeds"), "feeds list is required, set it here or in the global feeds list")
+		}
+		for i, feed := range m.Feeds {
+			if !ethereum.IsHexAddress(feed) {
+				errs.Add(validation.Pointer("medianizers", name, "feeds", i), "invalid feed address %s", feed)
+			}
+		}
+		if !ethereum.IsHexAddress(m.Contract) {
+			errs.Add(validation.Pointer("medianizers", name, "oracle"), "invalid contract address %s", m.Contract)
+		}
+		if m.OracleSpread < 0 {
+			errs.Add(validation.Pointer("medianizers", name, "oracleSpread"), "spread must not be negative")
+		}
+		if m.OracleExpiration <= 0 {
+			errs.Add(validation.Pointer("medianizers", name, "oracleExpiration"), "expiration must be greater than zero")
+		}
+		if m.MsgExpiration <= 0 {
+			errs.Add(validation.Pointer("medianizers", name, "msgExpiration"), "expiration must be greater than zero")
+		}
+		if m.MaxDeviationFromReference < 0 {
+			errs.Add(validation.Pointer("medianizers", name, "maxDeviationFromReference"), "deviation must not be negative")
+		}
+		if m.Interval < 0 {
+			errs.Add(validation.Pointer("medianizers", name, "interval"), "interval must not be negative")
+		}
+		if _, err := spectre.SelectorByName(m.Selection); err != nil {
+			errs.Add(validation.Pointer("medianizers", name, "selection"), "%s", err)
+		}
+		validateFees(&errs, m.MaxFee, m.MaxPriorityFee, "medianizers", name)
+	}
+
+	names = nil
+	for name := range c.OSMs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		o := c.OSMs[name]
+		if !ethereum.IsHexAddress(o.Contract) {
+			errs.Add(validation.Pointer("osms", name, "oracle"), "invalid contract address %s", o.Contract)
+		}
+		validateFees(&errs, o.MaxFee, o.MaxPriorityFee, "osms", name)
+	}
+
+	c.validateTransactions(&errs)
+	c.validateCoordination(&errs)
+	c.Reference.TLS.ValidateClient(&errs, "reference", "tls")
+
+	if c.Datastore.Expiration < 0 {
+		errs.Add(validation.Pointer("datastore", "expiration"), "expiration must not be negative")
+	}
+
+	return errs.Err()
+}
+
+func (c *Config) validateTransactions(errs *validation.Errors) {
+	if c.Transactions.ReplaceAfter < 0 {
+		errs.Add(validation.Pointer("transactions", "replaceAfter"), "time must not be negative")
+	}
+	if c.Transactions.FeeBump != 0 && c.Transactions.FeeBump < 1.1 {
+		errs.Add(validation.Pointer("transactions", "feeBump"), "fee bump must be at least 1.1")
+	}
+	if c.Transactions.MaxReplacements < 0 {
+		errs.Add(validation.Pointer("transactions", "maxReplacements"), "number of replacements must not be negative")
+	}
+	if c.Transactions.PollInterval < 0 {
+		errs.Add(validation.Pointer("transactions", "pollInterval"), "interval must not be negative")
+	}
+}
+
+func (c *Config) validateCoordination(errs *validation.Errors) {
+	if len(c.Coordination.Relayers) == 0 {
+		return
+	}
+	found := false
+	for i, relayer := range c.Coordination.Relayers {
+		if !ethereum.IsHexAddress(relayer) {
+			errs.Add(validation.Pointer("coordination", "relayers", i), "invalid relayer address %s", relayer)
+			continue
+		}
+		if ethereum.HexToAddress(relayer) == ethereum.HexToAddress(c.Ethereum.From) {
+			found = true
+		}
+	}
+	if !found {
+		errs.Add(validation.Pointer("coordination", "relayers"), "relayers list must contain the ethereum.from address")
+	}
+	if c.Coordination.Backoff < 0 {
+		errs.Add(validation.Pointer("coordination", "backoff"), "backoff must not be negative")
+	}
+}
+
+func (c *Config) validateEthereum(errs *validation.Errors) {
+	shared.ValidateEthereum(errs, c.Ethereum.From, c.Ethereum.Password)
+	if c.Ethereum.GasLimitMultiplier != 0 && c.Ethereum.GasLimitMultiplier < 1 {
+		errs.Add(validation.Pointer("ethereum", "gasLimitMultiplier"), "multiplier must not be less than 1")
+	}
+}
+
+func validateFees(errs *validation.Errors, maxFee, maxPriorityFee float64, path ...interface{}) {
+	ptr := func(field string) string {
+		return validation.Pointer(append(path, field)...)
+	}
+	if maxFee < 0 {
+		errs.Add(ptr("maxFee"), "max fee must not be negative")
+	}
+	if maxPriorityFee < 0 {
+		errs.Add(ptr("maxPriorityFee"), "max priority fee must not be negative")
+	}
+	if maxFee > 0 && maxPriorityFee > maxFee {
+		errs.Add(ptr("maxPriorityFee"), "max priority fee must not be greater than max fee")
+	}
+}
@@ -0,0 +1,286 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spectre
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/log"
+	"github.com/makerdao/oracle-suite/pkg/transport"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+const CoordinatorLoggerTag = "SPECTRE_COORDINATOR"
+
+// DefaultCoordinatorBackoff is the default time for which a relayer waits
+// for relayers before it in the schedule.
+const DefaultCoordinatorBackoff = 2 * time.Minute
+
+var errInvalidSignature = errors.New("received relay intent has an invalid signature")
+var errUnknownRelayer = errors.New("relayer is not allowed to send relay intents")
+
+// Coordinator coordinates Oracle updates between multiple Spectre instances
+// to avoid sending duplicated transactions.
+type Coordinator interface {
+	// Turn reports whether this relayer should update the Oracle for
+	// the given pair in the given round.
+	Turn(assetPair string, round int64) bool
+	// Relayed informs other relayers that this relayer sent the transaction
+	// which updates the Oracle for the given pair in the given round.
+	Relayed(assetPair string, round int64, tx ethereum.Hash) error
+}
+
+// TransportCoordinator implements the Coordinator interface using relay
+// intent messages sent over the transport.
+//
+// For every pair and round, all relayers are ordered using a deterministic
+// schedule, so every relayer computes the same order without communicating.
+// The first relayer in the schedule updates the Oracle immediately, every
+// next one waits for an additional backoff period. A relayer also waits
+// for the backoff period after receiving a relay intent from another
+// relayer, so it sends a transaction only if the other relayer's
+// transaction was not mined in time. A round is identified by the Oracle
+// age, so it ends as soon as any transaction updating the Oracle is mined.
+type TransportCoordinator struct {
+	mu  sync.Mutex
+	ctx context.Context
+
+	transport transport.Transport
+	signer    ethereum.Signer
+	relayers  []ethereum.Address
+	backoff   time.Duration
+	rounds    map[roundKey]*round
+	log       log.Logger
+	doneCh    chan struct{}
+}
+
+type roundKey struct {
+	assetPair string
+	round     int64
+}
+
+type round struct {
+	// start is the time when this relayer first tried to update the Oracle
+	// in the round.
+	start time.Time
+	// intents contains the time of the last relay intent received from
+	// other relayers.
+	intents map[ethereum.Address]time.Time
+}
+
+// CoordinatorConfig is the configuration for the TransportCoordinator.
+type CoordinatorConfig struct {
+	// Context is used to stop the coordinator.
+	Context context.Context
+	// Transport is used to send and receive relay intents. It must be
+	// subscribed to the messages.RelayIntentMessageName topic.
+	Transport transport.Transport
+	// Signer is used to sign and verify relay intents. Its address must be
+	// on the Relayers list.
+	Signer ethereum.Signer
+	// Relayers is the list of addresses of all coordinated relayers.
+	Relayers []ethereum.Address
+	// Backoff is the time for which a relayer waits for relayers before it
+	// in the schedule. If zero, DefaultCoordinatorBackoff is used.
+	Backoff time.Duration
+	Logger  log.Logger
+}
+
+// NewCoordinator returns a new instance of the TransportCoordinator.
+func NewCoordinator(cfg CoordinatorConfig) *TransportCoordinator {
+	if cfg.Context == nil {
+		cfg.Context = context.Background()
+	}
+	if cfg.Backoff == 0 {
+		cfg.Backoff = DefaultCoordinatorBackoff
+	}
+	return &TransportCoordinator{
+		ctx:       cfg.Context,
+		transport: cfg.Transport,
+		signer:    cfg.Signer,
+		relayers:  cfg.Relayers,
+		backoff:   cfg.Backoff,
+		rounds:    make(map[roundKey]*round),
+		log:       cfg.Logger.WithField("tag", CoordinatorLoggerTag),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+func (c *TransportCoordinator) Start() error {
+	c.log.Info("Starting")
+	c.intentsLoop()
+	return nil
+}
+
+func (c *TransportCoordinator) Stop() error {
+	defer c.log.Info("Stopped")
+	close(c.doneCh)
+	return nil
+}
+
+// Turn implements the Coordinator interface.
+func (c *TransportCoordinator) Turn(assetPair string, roundID int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.prune(assetPair, roundID)
+	rd := c.round(assetPair, roundID)
+	if rd.start.IsZero() {
+		rd.start = now
+	}
+
+	slot := c.slot(assetPair, roundID, c.signer.Address())
+	turn := rd.start.Add(time.Duration(slot) * c.backoff)
+	for _, t := range rd.intents {
+		if t.Add(c.backoff).After(turn) {
+			turn = t.Add(c.backoff)
+		}
+	}
+	return !now.Before(turn)
+}
+
+// Relayed implements the Coordinator interface.
+func (c *TransportCoordinator) Relayed(assetPair string, roundID int64, tx ethereum.Hash) error {
+	msg := &messages.RelayIntent{
+		AssetPair: assetPair,
+		Round:     roundID,
+		Tx:        tx,
+	}
+	if err := msg.Sign(c.signer); err != nil {
+		return err
+	}
+	return c.transport.Broadcast(messages.RelayIntentMessageName, msg)
+}
+
+// Schedule returns relayers ordered by their turn for the given pair and
+// round.
+func (c *TransportCoordinator) Schedule(assetPair string, roundID int64) []ethereum.Address {
+	relayers := append([]ethereum.Address{}, c.relayers...)
+	sort.Slice(relayers, func(i, j int) bool {
+		return bytes.Compare(
+			scheduleHash(assetPair, roundID, relayers[i]),
+			scheduleHash(assetPair, roundID, relayers[j]),
+		) < 0
+	})
+	return relayers
+}
+
+// slot returns the position of the relayer in the schedule. Relayers which
+// are not on the list are placed after all other relayers.
+func (c *TransportCoordinator) slot(assetPair string, roundID int64, address ethereum.Address) int {
+	for i, addr := range c.Schedule(assetPair, roundID) {
+		if addr == address {
+			return i
+		}
+	}
+	return len(c.relayers)
+}
+
+// round returns the round for the given pair, it creates a new one if
+// necessary.
+func (c *TransportCoordinator) round(assetPair string, roundID int64) *round {
+	key := roundKey{assetPair: assetPair, round: roundID}
+	rd, ok := c.rounds[key]
+	if !ok {
+		rd = &round{intents: make(map[ethereum.Address]time.Time)}
+		c.rounds[key] = rd
+	}
+	return rd
+}
+
+// prune removes rounds for the given pair which are older than the given
+// round.
+func (c *TransportCoordinator) prune(assetPair string, roundID int64) {
+	for key := range c.rounds {
+		if key.assetPair == assetPair && key.round < roundID {
+			delete(c.rounds, key)
+		}
+	}
+}
+
+// collectIntent records the relay intent received from another relayer.
+func (c *TransportCoordinator) collectIntent(msg *messages.RelayIntent) (*ethereum.Address, error) {
+	from, err := msg.From(c.signer)
+	if err != nil {
+		return nil, errInvalidSignature
+	}
+	if c.slot(msg.AssetPair, msg.Round, *from) == len(c.relayers) {
+		return from, errUnknownRelayer
+	}
+	if *from == c.signer.Address() {
+		return from, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.round(msg.AssetPair, msg.Round).intents[*from] = time.Now()
+	return from, nil
+}
+
+// intentsLoop creates a asynchronous loop which receives relay intents
+// from other relayers.
+func (c *TransportCoordinator) intentsLoop() {
+	go func() {
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-c.doneCh:
+				return
+			case status := <-c.transport.WaitFor(messages.RelayIntentMessageName):
+				if status.Error != nil {
+					c.log.
+						WithError(status.Error).
+						Warn("Unable to read relay intents from the transport")
+					continue
+				}
+				msg := status.Message.(*messages.RelayIntent)
+				fields := log.Fields{
+					"assetPair": msg.AssetPair,
+					"round":     msg.Round,
+					"tx":        msg.Tx.String(),
+				}
+				from, err := c.collectIntent(msg)
+				if from != nil {
+					fields["from"] = from.String()
+				}
+				if err != nil {
+					c.log.WithError(err).WithFields(fields).Warn("Received invalid relay intent")
+					continue
+				}
+				c.log.WithFields(fields).Info("Relay intent received")
+			}
+		}
+	}()
+}
+
+// scheduleHash returns the hash used to order relayers in the schedule.
+func scheduleHash(assetPair string, roundID int64, address ethereum.Address) []byte {
+	b := make([]byte, 32+8+ethereum.AddressLength)
+	copy(b[0:32], assetPair)
+	binary.BigEndian.PutUint64(b[32:40], uint64(roundID))
+	copy(b[40:], address.Bytes())
+	return ethereum.SHA3Hash(b)
+}
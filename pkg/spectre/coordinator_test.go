@@ -0,0 +1,135 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spectre
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/ethereum/mocks"
+	"github.com/makerdao/oracle-suite/pkg/log/null"
+	"github.com/makerdao/oracle-suite/pkg/transport/local"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+var (
+	relayer1   = ethereum.Address{1}
+	relayer2   = ethereum.Address{2}
+	relayer3   = ethereum.Address{3}
+	signature1 = ethereum.Signature{1}
+	signature2 = ethereum.Signature{2}
+	signature3 = ethereum.Signature{3}
+)
+
+func newTestCoordinator(backoff time.Duration, relayers ...ethereum.Address) (*TransportCoordinator, *local.Local) {
+	sig := &mocks.Signer{}
+	sig.On("Address").Return(relayer1)
+	sig.On("Signature", mock.Anything).Return(signature1, nil)
+	sig.On("Recover", signature1, mock.Anything).Return(&relayer1, nil)
+	sig.On("Recover", signature2, mock.Anything).Return(&relayer2, nil)
+	sig.On("Recover", signature3, mock.Anything).Return(&relayer3, nil)
+
+	tra := local.New(1)
+	_ = tra.Subscribe(messages.RelayIntentMessageName, (*messages.RelayIntent)(nil))
+
+	return NewCoordinator(CoordinatorConfig{
+		Transport: tra,
+		Signer:    sig,
+		Relayers:  relayers,
+		Backoff:   backoff,
+		Logger:    null.New(),
+	}), tra
+}
+
+// findRound returns the first round in which relayer1 has the given slot.
+func findRound(t *testing.T, c *TransportCoordinator, slot int) int64 {
+	for round := int64(0); round < 100; round++ {
+		if c.Schedule("AAABBB", round)[slot] == relayer1 {
+			return round
+		}
+	}
+	require.Fail(t, "round not found")
+	return 0
+}
+
+func TestTransportCoordinator_Schedule(t *testing.T) {
+	c, _ := newTestCoordinator(time.Minute, relayer1, relayer2, relayer3)
+
+	leaders := map[ethereum.Address]bool{}
+	for round := int64(0); round < 20; round++ {
+		s := c.Schedule("AAABBB", round)
+		assert.ElementsMatch(t, []ethereum.Address{relayer1, relayer2, relayer3}, s)
+		assert.Equal(t, s, c.Schedule("AAABBB", round))
+		leaders[s[0]] = true
+	}
+
+	// Leaders should rotate between rounds:
+	assert.Greater(t, len(leaders), 1)
+}
+
+func TestTransportCoordinator_Turn(t *testing.T) {
+	c, _ := newTestCoordinator(time.Hour, relayer1, relayer2)
+
+	// The first relayer in the schedule updates the Oracle immediately:
+	assert.True(t, c.Turn("AAABBB", findRound(t, c, 0)))
+
+	// The second one has to wait:
+	assert.False(t, c.Turn("AAABBB", findRound(t, c, 1)))
+}
+
+func TestTransportCoordinator_Turn_Backoff(t *testing.T) {
+	c, _ := newTestCoordinator(10*time.Millisecond, relayer1, relayer2)
+
+	round := findRound(t, c, 1)
+	assert.False(t, c.Turn("AAABBB", round))
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, c.Turn("AAABBB", round))
+}
+
+func TestTransportCoordinator_Turn_Intent(t *testing.T) {
+	c, _ := newTestCoordinator(time.Hour, relayer1, relayer2)
+
+	// The relayer should wait even if it is first in the schedule, because
+	// other relayer has already sent a transaction:
+	round := findRound(t, c, 0)
+	_, err := c.collectIntent(&messages.RelayIntent{AssetPair: "AAABBB", Round: round, Signature: signature2})
+	require.NoError(t, err)
+	assert.False(t, c.Turn("AAABBB", round))
+}
+
+func TestTransportCoordinator_collectIntent_UnknownRelayer(t *testing.T) {
+	c, _ := newTestCoordinator(time.Hour, relayer1, relayer2)
+
+	_, err := c.collectIntent(&messages.RelayIntent{AssetPair: "AAABBB", Round: 1, Signature: signature3})
+	assert.Equal(t, errUnknownRelayer, err)
+}
+
+func TestTransportCoordinator_Relayed(t *testing.T) {
+	c, tra := newTestCoordinator(time.Hour, relayer1, relayer2)
+
+	require.NoError(t, c.Relayed("AAABBB", 42, ethereum.Hash{1}))
+
+	msg := (<-tra.WaitFor(messages.RelayIntentMessageName)).Message.(*messages.RelayIntent)
+	assert.Equal(t, "AAABBB", msg.AssetPair)
+	assert.Equal(t, int64(42), msg.Round)
+	assert.Equal(t, ethereum.Hash{1}, msg.Tx)
+	assert.Equal(t, signature1, msg.Signature)
+}
@@ -18,7 +18,6 @@ package spectre
 import (
 	"math"
 	"math/big"
-	"math/rand"
 	"sort"
 	"time"
 
@@ -57,21 +56,20 @@ func (p *prices) oraclePrices() []*oracle.Price {
 	return prices
 }
 
-// truncate removes random msgs until the number of remaining prices is equal
-// to n. If the number of prices is less or equal to n, it does nothing.
+// truncate removes msgs until the number of remaining prices is equal to n.
+// Remaining prices are chosen by the given selector. If the number of prices
+// is less or equal to n, it does nothing. It returns removed msgs.
 //
 // This method is used to reduce number of arguments in transaction which will
 // reduce transaction costs.
-func (p *prices) truncate(n int64) {
+func (p *prices) truncate(n int64, s Selector) []*messages.Price {
 	if int64(len(p.msgs)) <= n {
-		return
+		return nil
 	}
 
-	rand.Shuffle(len(p.msgs), func(i, j int) {
-		p.msgs[i], p.msgs[j] = p.msgs[j], p.msgs[i]
-	})
-
-	p.msgs = p.msgs[0:n]
+	var excluded []*messages.Price
+	p.msgs, excluded = s.Select(p.msgs, int(n))
+	return excluded
 }
 
 // median calculates the median price for all messages in the list.
@@ -78,15 +78,15 @@ func TestPrices_truncate(t *testing.T) {
 	}
 
 	ps1 := newPrices(msgs)
-	ps1.truncate(5)
+	ps1.truncate(5, FreshestSelector{})
 	assert.Len(t, ps1.messages(), 4)
 
 	ps2 := newPrices(msgs)
-	ps2.truncate(4)
+	ps2.truncate(4, FreshestSelector{})
 	assert.Len(t, ps2.messages(), 4)
 
 	ps3 := newPrices(msgs)
-	ps3.truncate(3)
+	ps3.truncate(3, FreshestSelector{})
 	assert.Len(t, ps3.messages(), 3)
 }
 
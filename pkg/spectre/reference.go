@@ -0,0 +1,98 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spectre
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/makerdao/oracle-suite/pkg/oracle"
+)
+
+const (
+	reasonReference   = "median price deviates from the reference price"
+	reasonNoReference = "unable to fetch the reference price"
+)
+
+var errNoReference = errors.New("reference Gofer is not configured")
+
+type errReferenceDeviation struct {
+	AssetPair string
+	Deviation float64
+}
+
+func (e errReferenceDeviation) Error() string {
+	return fmt.Sprintf(
+		"unable to update the Oracle for %s pair, the median price deviates from the reference price by %f%%",
+		e.AssetPair,
+		e.Deviation,
+	)
+}
+
+// referencePrice returns the price for the given pair from the reference
+// Gofer. Gofer stores pairs in the AAA/BBB format, but Spectre uses
+// the AAABBB format, so the pair has to be found first.
+func (r *Spectre) referencePrice(assetPair string) (float64, error) {
+	if r.gofer == nil {
+		return 0, errNoReference
+	}
+	pairs, err := r.gofer.Pairs()
+	if err != nil {
+		return 0, err
+	}
+	for _, pair := range pairs {
+		if pair.Base+pair.Quote != assetPair {
+			continue
+		}
+		price, err := r.gofer.Price(pair)
+		if err != nil {
+			return 0, err
+		}
+		if price.Error != "" {
+			return 0, fmt.Errorf("unable to fetch the reference price for %s pair: %s", assetPair, price.Error)
+		}
+		return price.Price, nil
+	}
+	return 0, fmt.Errorf("pair %s does not exists in the reference Gofer", assetPair)
+}
+
+// checkReference compares the median price with the price from
+// the reference Gofer. If the reference price is not available or
+// the deviation is higher than allowed, the Oracle must not be updated.
+func (r *Spectre) checkReference(pair *Pair, rep *Report, median *big.Int) {
+	refPrice, err := r.referencePrice(pair.AssetPair)
+	if err != nil {
+		rep.Poke = false
+		rep.Reason = fmt.Sprintf("%s: %s", reasonNoReference, err)
+		rep.referenceErr = fmt.Errorf("%s: %w", reasonNoReference, err)
+		return
+	}
+
+	rep.ReferencePrice = new(big.Float).Mul(big.NewFloat(refPrice), big.NewFloat(oracle.PriceMultiplier)).Text('f', 0)
+	deviation := math.Inf(1)
+	if refPrice > 0 {
+		medianF, _ := new(big.Float).Quo(new(big.Float).SetInt(median), big.NewFloat(oracle.PriceMultiplier)).Float64()
+		deviation = math.Abs(medianF-refPrice) / refPrice * 100
+		rep.ReferenceDeviation = &deviation
+	}
+	if deviation > pair.MaxDeviationFromReference {
+		rep.Poke = false
+		rep.Reason = reasonReference
+		rep.referenceErr = errReferenceDeviation{AssetPair: pair.AssetPair, Deviation: deviation}
+	}
+}
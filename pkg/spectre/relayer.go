@@ -17,18 +17,26 @@ package spectre
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/makerdao/oracle-suite/pkg/datastore"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
 	"github.com/makerdao/oracle-suite/pkg/oracle"
 )
 
 const LoggerTag = "SPECTRE"
 
+// DefaultWorkers is the default number of pairs relayed concurrently.
+const DefaultWorkers = 4
+
 type errNotEnoughPricesForQuorum struct {
 	AssetPair string
 }
@@ -56,32 +64,123 @@ func (e errNoPrices) Error() string {
 	return fmt.Sprintf("there is no prices in the datastore for %s pair", e.AssetPair)
 }
 
+type errPendingTransaction struct {
+	AssetPair string
+	Tx        ethereum.Hash
+}
+
+func (e errPendingTransaction) Error() string {
+	return fmt.Sprintf(
+		"previous transaction %s for %s pair is still pending",
+		e.Tx.String(),
+		e.AssetPair,
+	)
+}
+
+type errNotOurTurn struct {
+	AssetPair string
+}
+
+func (e errNotOurTurn) Error() string {
+	return fmt.Sprintf("waiting for another relayer to update the Oracle for %s pair", e.AssetPair)
+}
+
 type Datastore interface {
 	Prices() *datastore.PriceStore
 	Start() error
 	Stop() error
 }
 
+// GasPricer returns the current gas price.
+type GasPricer interface {
+	GasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// TxTracker reports whether a transaction sent to the Ethereum network is
+// still pending.
+type TxTracker interface {
+	Pending(hash ethereum.Hash) bool
+}
+
 type Spectre struct {
 	mu  sync.Mutex
 	ctx context.Context
 
 	signer    ethereum.Signer
 	datastore Datastore
+	txTracker TxTracker
+	coord     Coordinator
+	gasPricer GasPricer
+	gofer     gofer.Gofer
 	interval  time.Duration
+	workers   int
+	dryRun    bool
 	log       log.Logger
 	pairs     map[string]*Pair
+	osms      []*OSM
+	lastTx    map[string]ethereum.Hash
+	nextRelay map[string]time.Time
 	doneCh    chan struct{}
+
+	gasPriceLimit *big.Int
+
+	// Fields used to report the health status:
+	statusMu  sync.RWMutex
+	lastCycle time.Time
+	lastRelay map[string]relayStatus
+	lastPoke  map[string]relayStatus
+	lastSel   map[string]selectionStatus
+}
+
+// relayStatus describes the last relay attempt for a pair.
+type relayStatus struct {
+	Time  time.Time `json:"time"`
+	Tx    string    `json:"tx,omitempty"`
+	Error string    `json:"error,omitempty"`
+}
+
+// selectionStatus describes which feeds were selected for the last relay
+// attempt for a pair and which were excluded.
+type selectionStatus struct {
+	Time     time.Time    `json:"time"`
+	Selector string       `json:"selector"`
+	Selected []FeedStatus `json:"selected"`
+	Excluded []FeedStatus `json:"excluded"`
 }
 
 type Config struct {
 	Signer ethereum.Signer
 	// Datastore provides prices for Spectre.
 	Datastore Datastore
-	// Interval describes how often we should try to update Oracles.
+	// TxTracker is used to skip pairs for which the previous Oracle update
+	// is still pending. If nil, pending updates are not checked.
+	TxTracker TxTracker
+	// Coordinator is used to coordinate Oracle updates with other Spectre
+	// instances. If nil, Oracles are updated without coordination.
+	Coordinator Coordinator
+	// Interval describes how often we should try to update Oracles. It is
+	// used for pairs without their own interval and for OSMs.
 	Interval time.Duration
+	// Workers is the number of pairs which may be relayed concurrently. If
+	// zero, DefaultWorkers is used.
+	Workers int
+	// GasPricer is used to check the current gas price. If the gas price
+	// is above the GasPriceLimit, pairs are relayed one by one in the order
+	// of priority instead of concurrently. If GasPricer or GasPriceLimit is
+	// nil, the gas price is not checked.
+	GasPricer     GasPricer
+	GasPriceLimit *big.Int
+	// Gofer provides reference prices used to cross-check prices from
+	// feeds. It is required only for pairs with MaxDeviationFromReference.
+	Gofer gofer.Gofer
 	// Pairs is the list supported pairs by Spectre with their configuration.
 	Pairs []*Pair
+	// OSMs is the list of OSM contracts poked by Spectre after updating
+	// Oracles.
+	OSMs []*OSM
+	// DryRun disables sending transactions. Instead, the decision whether
+	// Oracles and OSMs would be updated is logged.
+	DryRun bool
 	// Logger is a current logger interface used by the Spectre. The Logger is
 	// required to monitor asynchronous processes.
 	Logger log.Logger
@@ -102,6 +201,32 @@ type Pair struct {
 	// Median is the instance of the oracle.Median which is the interface for
 	// the Oracle contract.
 	Median oracle.Median
+	// Selector chooses which prices are sent to the Oracle if there are
+	// more prices than required to achieve a quorum. If nil,
+	// the FreshestSelector is used.
+	Selector Selector
+	// Interval describes how often we should try to update the Oracle. If
+	// zero, the global interval is used.
+	Interval time.Duration
+	// MaxDeviationFromReference is the maximum deviation, in percentage
+	// points, between the median of feed prices and the price from
+	// the reference Gofer. If it is exceeded, or the reference price is not
+	// available, the Oracle is not updated. If zero, prices are not
+	// cross-checked.
+	MaxDeviationFromReference float64
+	// Priority determines the order in which pairs are relayed, pairs with
+	// a higher priority are relayed first. If the gas price is above
+	// the limit, pairs are relayed one by one in that order.
+	Priority int
+}
+
+type OSM struct {
+	// AssetPair is the name of asset pair of the OSM, e.g. ETHUSD. It is used
+	// only in logs.
+	AssetPair string
+	// OSM is the instance of the oracle.OSM which is the interface for
+	// the Oracle Security Module contract.
+	OSM oracle.OSM
 }
 
 func NewSpectre(cfg Config) *Spectre {
@@ -109,19 +234,49 @@ func NewSpectre(cfg Config) *Spectre {
 		ctx:       context.Background(),
 		signer:    cfg.Signer,
 		datastore: cfg.Datastore,
+		txTracker: cfg.TxTracker,
+		coord:     cfg.Coordinator,
+		gasPricer: cfg.GasPricer,
+		gofer:     cfg.Gofer,
 		interval:  cfg.Interval,
+		workers:   cfg.Workers,
+		dryRun:    cfg.DryRun,
 		pairs:     make(map[string]*Pair),
+		osms:      cfg.OSMs,
+		lastTx:    make(map[string]ethereum.Hash),
+		nextRelay: make(map[string]time.Time),
 		log:       cfg.Logger.WithField("tag", LoggerTag),
 		doneCh:    make(chan struct{}),
+		lastRelay: make(map[string]relayStatus),
+		lastPoke:  make(map[string]relayStatus),
+		lastSel:   make(map[string]selectionStatus),
+
+		gasPriceLimit: cfg.GasPriceLimit,
+	}
+	if r.workers <= 0 {
+		r.workers = DefaultWorkers
 	}
 
 	for _, p := range cfg.Pairs {
+		if p.Selector == nil {
+			p.Selector = FreshestSelector{}
+		}
 		r.pairs[p.AssetPair] = p
 	}
 
 	return r
 }
 
+// AssetPairs returns the sorted list of asset pairs supported by Spectre.
+func (r *Spectre) AssetPairs() []string {
+	var assetPairs []string
+	for assetPair := range r.pairs {
+		assetPairs = append(assetPairs, assetPair)
+	}
+	sort.Strings(assetPairs)
+	return assetPairs
+}
+
 func (r *Spectre) Start() error {
 	r.log.Info("Starting")
 	err := r.datastore.Start()
@@ -129,6 +284,10 @@ func (r *Spectre) Start() error {
 		return err
 	}
 
+	r.statusMu.Lock()
+	r.lastCycle = time.Now()
+	r.statusMu.Unlock()
+
 	r.relayerLoop()
 	return nil
 }
@@ -144,80 +303,413 @@ func (r *Spectre) Stop() error {
 	return nil
 }
 
-// relay tries to update an Oracle contract for given pair. It'll return
-// transaction hash or nil if there is no need to update Oracle.
-func (r *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// HealthStatus implements the health.Checker interface. Spectre is ready if
+// the relayer loop was not stopped and did not miss the last cycle.
+func (r *Spectre) HealthStatus() health.Status {
+	r.statusMu.RLock()
+	defer r.statusMu.RUnlock()
 
-	pair, ok := r.pairs[assetPair]
-	if !ok {
-		return nil, errUnknownAsset{AssetPair: assetPair}
+	lastRelay := make(map[string]interface{}, len(r.lastRelay))
+	for assetPair, s := range r.lastRelay {
+		lastRelay[assetPair] = s
 	}
-
-	prices := newPrices(r.datastore.Prices().AssetPair(assetPair))
-	if prices == nil || prices.len() == 0 {
-		return nil, errNoPrices{AssetPair: assetPair}
+	lastSel := make(map[string]interface{}, len(r.lastSel))
+	for assetPair, s := range r.lastSel {
+		lastSel[assetPair] = s
+	}
+	details := map[string]interface{}{
+		"lastCycle":     r.lastCycle,
+		"lastRelay":     lastRelay,
+		"lastSelection": lastSel,
+	}
+	if len(r.osms) > 0 {
+		lastPoke := make(map[string]interface{}, len(r.lastPoke))
+		for assetPair, s := range r.lastPoke {
+			lastPoke[assetPair] = s
+		}
+		details["lastOSMPoke"] = lastPoke
+	}
+	return health.Status{
+		Ready:   r.interval > 0 && !r.lastCycle.IsZero() && time.Since(r.lastCycle) <= 2*r.interval,
+		Details: details,
 	}
+}
 
-	oracleQuorum, err := pair.Median.Bar(r.ctx)
-	if err != nil {
-		return nil, err
+// recordRelay stores the result of the relay attempt used to report
+// the health status.
+func (r *Spectre) recordRelay(assetPair string, tx *ethereum.Hash, err error) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	r.lastRelay[assetPair] = newRelayStatus(tx, err)
+}
+
+// recordSelection stores the prices selected for the relay attempt used
+// to report the health status.
+func (r *Spectre) recordSelection(assetPair string, s selectionStatus) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	r.lastSel[assetPair] = s
+}
+
+// recordPoke stores the result of the OSM poke attempt used to report
+// the health status.
+func (r *Spectre) recordPoke(assetPair string, tx *ethereum.Hash, err error) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	r.lastPoke[assetPair] = newRelayStatus(tx, err)
+}
+
+func newRelayStatus(tx *ethereum.Hash, err error) relayStatus {
+	s := relayStatus{Time: time.Now()}
+	if tx != nil {
+		s.Tx = tx.String()
 	}
-	oracleTime, err := pair.Median.Age(r.ctx)
 	if err != nil {
-		return nil, err
+		s.Error = err.Error()
 	}
-	oraclePrice, err := pair.Median.Val(r.ctx)
+	return s
+}
+
+// relay tries to update an Oracle contract for given pair. It'll return
+// transaction hash or nil if there is no need to update Oracle.
+func (r *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
+	rep, err := r.explain(assetPair)
 	if err != nil {
 		return nil, err
 	}
+	if rep.PendingTx != "" {
+		return nil, errPendingTransaction{AssetPair: assetPair, Tx: rep.pendingTx}
+	}
 
-	// Clear expired prices:
-	prices.clearOlderThan(time.Now().Add(-1 * pair.PriceExpiration))
-	prices.clearOlderThan(oracleTime)
-
-	// Use only a minimum prices required to achieve a quorum:
-	prices.truncate(oracleQuorum)
-
-	spread := prices.spread(oraclePrice)
-	isExpired := oracleTime.Add(pair.OracleExpiration).Before(time.Now())
-	isStale := spread >= pair.OracleSpread
+	r.recordSelection(assetPair, selectionStatus{
+		Time:     time.Now(),
+		Selector: rep.Selector,
+		Selected: rep.Selected,
+		Excluded: rep.Excluded,
+	})
 
 	// Print logs:
 	r.log.
 		WithFields(log.Fields{
 			"assetPair":        assetPair,
-			"bar":              oracleQuorum,
-			"age":              oracleTime.String(),
-			"val":              oraclePrice.String(),
-			"expired":          isExpired,
-			"stale":            isStale,
-			"oracleExpiration": pair.OracleExpiration.String(),
-			"oracleSpread":     pair.OracleSpread,
-			"timeToExpiration": time.Since(oracleTime).String(),
-			"currentSpread":    spread,
+			"bar":              rep.Bar,
+			"age":              rep.OracleAge.String(),
+			"val":              rep.OracleVal,
+			"expired":          rep.Expired,
+			"stale":            rep.Stale,
+			"oracleExpiration": rep.OracleExpiration,
+			"oracleSpread":     rep.OracleSpread,
+			"timeToExpiration": time.Since(rep.OracleAge).String(),
+			"currentSpread":    rep.spread,
+			"selector":         rep.Selector,
+			"excluded":         len(rep.excluded),
 		}).
 		Debug("Trying to update Oracle")
-	for _, price := range prices.oraclePrices() {
+	for _, price := range rep.prices {
 		r.log.
 			WithFields(price.Fields(r.signer)).
 			Debug("Feed")
 	}
+	for _, price := range rep.excluded {
+		r.log.
+			WithFields(price.Price.Fields(r.signer)).
+			Debug("Excluded feed")
+	}
 
-	if isExpired || isStale {
+	if rep.referenceErr != nil {
+		return nil, rep.referenceErr
+	}
+	if !rep.Poke {
 		// Check if there are enough prices to achieve a quorum:
-		if int64(prices.len()) != oracleQuorum {
+		if (rep.Expired || rep.Stale) && !rep.Quorum {
 			return nil, errNotEnoughPricesForQuorum{AssetPair: assetPair}
 		}
+		// There is no need to update Oracle:
+		return nil, nil
+	}
+
+	// Let other relayers update the Oracle if it is not our turn:
+	round := rep.OracleAge.Unix()
+	if r.coord != nil && !r.coord.Turn(assetPair, round) {
+		return nil, errNotOurTurn{AssetPair: assetPair}
+	}
+
+	// Send *actual* transaction to the Ethereum network:
+	tx, err := r.pairs[assetPair].Median.Poke(r.ctx, rep.prices, true)
+	if tx != nil {
+		r.setLastTx(assetPair, *tx)
+		if r.coord != nil {
+			if err := r.coord.Relayed(assetPair, round, *tx); err != nil {
+				r.log.
+					WithFields(log.Fields{"assetPair": assetPair, "tx": tx.String()}).
+					WithError(err).
+					Warn("Unable to send relay intent")
+			}
+		}
+	}
+	return tx, err
+}
+
+// pokeOSM pokes the OSM contract if enough time has passed since its last
+// update. It'll return transaction hash or nil if the OSM cannot be poked
+// yet.
+func (r *Spectre) pokeOSM(osm *OSM) (*ethereum.Hash, error) {
+	// Transactions are tracked separately from the Oracle updates for
+	// the same pair:
+	key := "osm:" + osm.AssetPair
+	if tx, ok := r.pendingTx(key); ok {
+		return nil, errPendingTransaction{AssetPair: osm.AssetPair, Tx: tx}
+	}
+
+	pass, err := osm.OSM.Pass(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !pass {
+		return nil, nil
+	}
+
+	tx, err := osm.OSM.Poke(r.ctx, true)
+	if tx != nil {
+		r.setLastTx(key, *tx)
+	}
+	return tx, err
+}
+
+// pendingTx returns the last transaction sent for the given key if it is
+// still pending.
+func (r *Spectre) pendingTx(key string) (ethereum.Hash, bool) {
+	r.mu.Lock()
+	tx, ok := r.lastTx[key]
+	r.mu.Unlock()
+
+	if !ok || r.txTracker == nil {
+		return ethereum.Hash{}, false
+	}
+	return tx, r.txTracker.Pending(tx)
+}
+
+// setLastTx stores the last transaction sent for the given key.
+func (r *Spectre) setLastTx(key string, tx ethereum.Hash) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastTx[key] = tx
+}
+
+// pokeOSMs pokes all OSMs which can be poked. It is called after Oracles
+// are updated, so the OSMs read the most recent prices.
+func (r *Spectre) pokeOSMs() {
+	for _, osm := range r.osms {
+		if r.dryRun {
+			r.explainOSM(osm)
+			continue
+		}
+
+		tx, err := r.pokeOSM(osm)
+		r.recordPoke(osm.AssetPair, tx, err)
+
+		fields := log.Fields{"assetPair": osm.AssetPair}
+		var pendingErr errPendingTransaction
+		switch {
+		case errors.As(err, &pendingErr):
+			fields["tx"] = pendingErr.Tx.String()
+			r.log.WithFields(fields).Info("Previous OSM poke is still pending")
+		case err != nil:
+			r.log.WithFields(fields).WithError(err).Warn("Unable to poke OSM")
+		case tx == nil:
+			r.log.WithFields(fields).Debug("OSM cannot be poked yet")
+		default:
+			fields["tx"] = tx.String()
+			r.log.WithFields(fields).Info("OSM poked")
+		}
+	}
+}
+
+// explainRelay logs the decision whether the Oracle for given pair would
+// be updated. It is used in the dry-run mode instead of relay.
+func (r *Spectre) explainRelay(assetPair string) {
+	rep, err := r.Explain(assetPair)
+	if err != nil {
+		r.log.
+			WithFields(log.Fields{"assetPair": assetPair}).
+			WithError(err).
+			Warn("Unable to explain Oracle update")
+		return
+	}
+	r.log.
+		WithFields(log.Fields{
+			"assetPair": assetPair,
+			"poke":      rep.Poke,
+			"reason":    rep.Reason,
+			"calldata":  rep.Calldata,
+		}).
+		Info("Dry run, Oracle update skipped")
+}
+
+// explainOSM logs whether the OSM would be poked. It is used in the dry-run
+// mode instead of pokeOSM.
+func (r *Spectre) explainOSM(osm *OSM) {
+	fields := log.Fields{"assetPair": osm.AssetPair}
+	pass, err := osm.OSM.Pass(r.ctx)
+	if err != nil {
+		r.log.WithFields(fields).WithError(err).Warn("Unable to check OSM")
+		return
+	}
+	fields["poke"] = pass
+	r.log.WithFields(fields).Info("Dry run, OSM poke skipped")
+}
+
+// relayPair tries to update the Oracle for given pair and prints logs with
+// the result.
+func (r *Spectre) relayPair(assetPair string) {
+	if r.dryRun {
+		r.explainRelay(assetPair)
+		return
+	}
+
+	tx, err := r.relay(assetPair)
+	r.recordRelay(assetPair, tx, err)
+
+	// Print log if the previous update is still pending:
+	var pendingErr errPendingTransaction
+	if errors.As(err, &pendingErr) {
+		r.log.
+			WithFields(log.Fields{"assetPair": assetPair, "tx": pendingErr.Tx.String()}).
+			Info("Previous Oracle update is still pending")
+		return
+	}
+	// Print log if the median price deviates from the reference price, it
+	// may indicate that feeds are compromised:
+	var devErr errReferenceDeviation
+	if errors.As(err, &devErr) {
+		r.log.
+			WithFields(log.Fields{"assetPair": assetPair, "deviation": devErr.Deviation}).
+			WithError(err).
+			Error("Median price deviates from the reference price, Oracle update refused")
+		return
+	}
+	// Print log if another relayer should update the Oracle:
+	var turnErr errNotOurTurn
+	if errors.As(err, &turnErr) {
+		r.log.
+			WithFields(log.Fields{"assetPair": assetPair}).
+			Info("Waiting for another relayer to update Oracle")
+		return
+	}
+	// Print log in case of an error:
+	if err != nil {
+		r.log.
+			WithFields(log.Fields{"assetPair": assetPair}).
+			WithError(err).
+			Warn("Unable to update Oracle")
+	}
+	// Print log if there was no need to update prices:
+	if err == nil && tx == nil {
+		r.log.
+			WithFields(log.Fields{"assetPair": assetPair}).
+			Info("Oracle price is still valid")
+	}
+	// Print log if Oracle update transaction was sent:
+	if tx != nil {
+		r.log.
+			WithFields(log.Fields{"assetPair": assetPair, "tx": tx.String()}).
+			Info("Oracle updated")
+	}
+}
+
+// relayPairs relays all pairs which are due at the given time. Pairs are
+// relayed concurrently by a pool of workers, in the order of priority.
+func (r *Spectre) relayPairs(now time.Time) {
+	pairs := r.duePairs(now)
+	workers := r.workers
+
+	// If the gas price is too high, relay pairs one by one, so the most
+	// important Oracles are updated first. Pairs are never skipped, because
+	// an Oracle is updated only if its price is expired or deviates too
+	// much:
+	if len(pairs) > 0 && r.gasPricer != nil && r.gasPriceLimit != nil {
+		gasPrice, err := r.gasPricer.GasPrice(r.ctx)
+		switch {
+		case err != nil:
+			r.log.WithError(err).Warn("Unable to fetch the gas price")
+		case gasPrice.Cmp(r.gasPriceLimit) > 0:
+			r.log.
+				WithFields(log.Fields{
+					"gasPrice":      gasPrice.String(),
+					"gasPriceLimit": r.gasPriceLimit.String(),
+				}).
+				Info("Gas price is above the limit, relaying pairs one by one in the order of priority")
+			workers = 1
+		}
+	}
+
+	r.mu.Lock()
+	for _, pair := range pairs {
+		r.nextRelay[pair.AssetPair] = now.Add(r.pairInterval(pair))
+	}
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	ch := make(chan *Pair)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pair := range ch {
+				r.relayPair(pair.AssetPair)
+			}
+		}()
+	}
+	for _, pair := range pairs {
+		ch <- pair
+	}
+	close(ch)
+	wg.Wait()
+}
+
+// duePairs returns pairs which should be relayed at the given time, ordered
+// by priority.
+func (r *Spectre) duePairs(now time.Time) []*Pair {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pairs []*Pair
+	for assetPair, pair := range r.pairs {
+		if !now.Before(r.nextRelay[assetPair]) {
+			pairs = append(pairs, pair)
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Priority != pairs[j].Priority {
+			return pairs[i].Priority > pairs[j].Priority
+		}
+		return pairs[i].AssetPair < pairs[j].AssetPair
+	})
+	return pairs
+}
 
-		// Send *actual* transaction to the Ethereum network:
-		tx, err := pair.Median.Poke(r.ctx, prices.oraclePrices(), true)
-		return tx, err
+// pairInterval returns the relay interval for given pair.
+func (r *Spectre) pairInterval(pair *Pair) time.Duration {
+	if pair.Interval > 0 {
+		return pair.Interval
 	}
+	return r.interval
+}
 
-	// There is no need to update Oracle:
-	return nil, nil
+// tick returns the interval of the relayer loop, which is the shortest
+// interval of all pairs.
+func (r *Spectre) tick() time.Duration {
+	tick := r.interval
+	for _, pair := range r.pairs {
+		if i := r.pairInterval(pair); i < tick {
+			tick = i
+		}
+	}
+	return tick
 }
 
 // relayerLoop creates a asynchronous loop which tries to send an update
@@ -227,37 +719,23 @@ func (r *Spectre) relayerLoop() {
 		return
 	}
 
-	ticker := time.NewTicker(r.interval)
+	ticker := time.NewTicker(r.tick())
 	go func() {
+		var nextOSMPoke time.Time
 		for {
 			select {
 			case <-r.doneCh:
 				ticker.Stop()
 				return
-			case <-ticker.C:
-				for assetPair := range r.pairs {
-					tx, err := r.relay(assetPair)
-
-					// Print log in case of an error:
-					if err != nil {
-						r.log.
-							WithFields(log.Fields{"assetPair": assetPair}).
-							WithError(err).
-							Warn("Unable to update Oracle")
-					}
-					// Print log if there was no need to update prices:
-					if err == nil && tx == nil {
-						r.log.
-							WithFields(log.Fields{"assetPair": assetPair}).
-							Info("Oracle price is still valid")
-					}
-					// Print log if Oracle update transaction was sent:
-					if tx != nil {
-						r.log.
-							WithFields(log.Fields{"assetPair": assetPair, "tx": tx.String()}).
-							Info("Oracle updated")
-					}
+			case now := <-ticker.C:
+				r.relayPairs(now)
+				if !now.Before(nextOSMPoke) {
+					r.pokeOSMs()
+					nextOSMPoke = now.Add(r.interval)
 				}
+				r.statusMu.Lock()
+				r.lastCycle = time.Now()
+				r.statusMu.Unlock()
 			}
 		}
 	}()
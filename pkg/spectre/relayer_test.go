@@ -0,0 +1,361 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spectre
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/datastore"
+	"github.com/makerdao/oracle-suite/pkg/datastore/testutil"
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/ethereum/mocks"
+	"github.com/makerdao/oracle-suite/pkg/gofer"
+	goferMocks "github.com/makerdao/oracle-suite/pkg/gofer/mocks"
+	"github.com/makerdao/oracle-suite/pkg/log/null"
+	"github.com/makerdao/oracle-suite/pkg/oracle"
+)
+
+type testDatastore struct {
+	prices *datastore.PriceStore
+}
+
+func (d *testDatastore) Prices() *datastore.PriceStore { return d.prices }
+func (d *testDatastore) Start() error                  { return nil }
+func (d *testDatastore) Stop() error                   { return nil }
+
+type testMedian struct {
+	bar   int64
+	age   time.Time
+	val   *big.Int
+	pokes [][]*oracle.Price
+}
+
+func (m *testMedian) Age(context.Context) (time.Time, error)            { return m.age, nil }
+func (m *testMedian) Bar(context.Context) (int64, error)                { return m.bar, nil }
+func (m *testMedian) Val(context.Context) (*big.Int, error)             { return m.val, nil }
+func (m *testMedian) Wat(context.Context) (string, error)               { return "AAABBB", nil }
+func (m *testMedian) Feeds(context.Context) ([]ethereum.Address, error) { return nil, nil }
+func (m *testMedian) PokeCalldata(prices []*oracle.Price) ([]byte, error) {
+	return []byte{byte(len(prices))}, nil
+}
+func (m *testMedian) Lift(context.Context, []common.Address, bool) (*ethereum.Hash, error) {
+	return nil, nil
+}
+func (m *testMedian) Drop(context.Context, []common.Address, bool) (*ethereum.Hash, error) {
+	return nil, nil
+}
+func (m *testMedian) SetBar(context.Context, *big.Int, bool) (*ethereum.Hash, error) { return nil, nil }
+
+func (m *testMedian) Poke(_ context.Context, prices []*oracle.Price, _ bool) (*ethereum.Hash, error) {
+	m.pokes = append(m.pokes, prices)
+	return &ethereum.Hash{byte(len(m.pokes))}, nil
+}
+
+func newTestSpectre(median *testMedian) *Spectre {
+	ps := datastore.NewPriceStore()
+	ps.Add(testutil.Address1, testutil.PriceAAABBB1)
+	ps.Add(testutil.Address2, testutil.PriceAAABBB2)
+	ps.Add(ethereum.Address{3}, testutil.PriceAAABBB3)
+	ps.Add(ethereum.Address{4}, testutil.PriceAAABBB4)
+
+	sig := &mocks.Signer{}
+	sig.On("Recover", mock.Anything, mock.Anything).Return(&testutil.Address1, nil)
+
+	return NewSpectre(Config{
+		Signer:    sig,
+		Datastore: &testDatastore{prices: ps},
+		Pairs: []*Pair{{
+			AssetPair:        "AAABBB",
+			OracleSpread:     1,
+			OracleExpiration: time.Hour,
+			PriceExpiration:  time.Since(time.Unix(0, 0)),
+			Median:           median,
+		}},
+		Logger: null.New(),
+	})
+}
+
+func TestSpectre_Explain(t *testing.T) {
+	median := &testMedian{bar: 3, age: time.Unix(150, 0), val: big.NewInt(10)}
+	s := newTestSpectre(median)
+
+	rep, err := s.Explain("AAABBB")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), rep.Bar)
+	assert.Equal(t, 4, rep.Prices)
+	// The first price is older than the Oracle price:
+	assert.Equal(t, 1, rep.ExpiredPrices)
+	assert.Len(t, rep.Selected, 3)
+	assert.Len(t, rep.Excluded, 0)
+	assert.Equal(t, "30", rep.Median)
+	assert.True(t, rep.Expired)
+	assert.True(t, rep.Stale)
+	assert.True(t, rep.Quorum)
+	assert.True(t, rep.Poke)
+	assert.Equal(t, reasonExpired, rep.Reason)
+	assert.Equal(t, "0x03", rep.Calldata)
+
+	// Explain must not send transactions:
+	assert.Len(t, median.pokes, 0)
+}
+
+func TestSpectre_Explain_NoQuorum(t *testing.T) {
+	median := &testMedian{bar: 5, age: time.Unix(50, 0), val: big.NewInt(10)}
+	s := newTestSpectre(median)
+
+	rep, err := s.Explain("AAABBB")
+	require.NoError(t, err)
+	assert.False(t, rep.Quorum)
+	assert.False(t, rep.Poke)
+	assert.Equal(t, reasonNoQuorum, rep.Reason)
+	assert.Empty(t, rep.Calldata)
+
+	_, err = s.relay("AAABBB")
+	assert.Equal(t, errNotEnoughPricesForQuorum{AssetPair: "AAABBB"}, err)
+}
+
+func TestSpectre_relay(t *testing.T) {
+	median := &testMedian{bar: 3, age: time.Unix(50, 0), val: big.NewInt(10)}
+	s := newTestSpectre(median)
+
+	tx, err := s.relay("AAABBB")
+	require.NoError(t, err)
+	assert.Equal(t, &ethereum.Hash{1}, tx)
+	require.Len(t, median.pokes, 1)
+	assert.Len(t, median.pokes[0], 3)
+	assert.Equal(t, *tx, s.lastTx["AAABBB"])
+}
+
+type testOSM struct {
+	pass     bool
+	pokes    int
+	simulate bool
+}
+
+func (o *testOSM) Peek(context.Context) (*big.Int, bool, error) { return nil, false, nil }
+func (o *testOSM) Peep(context.Context) (*big.Int, bool, error) { return nil, false, nil }
+func (o *testOSM) Zzz(context.Context) (time.Time, error)       { return time.Time{}, nil }
+func (o *testOSM) Hop(context.Context) (time.Duration, error)   { return time.Hour, nil }
+func (o *testOSM) Pass(context.Context) (bool, error)           { return o.pass, nil }
+
+func (o *testOSM) Poke(_ context.Context, simulateBeforeRun bool) (*ethereum.Hash, error) {
+	o.pokes++
+	o.simulate = simulateBeforeRun
+	return &ethereum.Hash{byte(o.pokes)}, nil
+}
+
+type txTracker map[ethereum.Hash]bool
+
+func (t txTracker) Pending(hash ethereum.Hash) bool {
+	return t[hash]
+}
+
+func TestSpectre_relay_PendingTransaction(t *testing.T) {
+	tx := ethereum.Hash{1}
+	s := NewSpectre(Config{
+		TxTracker: txTracker{tx: true},
+		Pairs:     []*Pair{{AssetPair: "AAABBB"}},
+		Logger:    null.New(),
+	})
+	s.lastTx["AAABBB"] = tx
+
+	hash, err := s.relay("AAABBB")
+	assert.Nil(t, hash)
+	assert.Equal(t, errPendingTransaction{AssetPair: "AAABBB", Tx: tx}, err)
+}
+
+func TestSpectre_pokeOSM(t *testing.T) {
+	osm := &testOSM{}
+	tracker := txTracker{}
+	s := NewSpectre(Config{
+		TxTracker: tracker,
+		OSMs:      []*OSM{{AssetPair: "AAABBB", OSM: osm}},
+		Logger:    null.New(),
+	})
+
+	// OSM cannot be poked yet:
+	hash, err := s.pokeOSM(s.osms[0])
+	assert.NoError(t, err)
+	assert.Nil(t, hash)
+	assert.Equal(t, 0, osm.pokes)
+
+	// OSM can be poked, the transaction should be simulated first:
+	osm.pass = true
+	hash, err = s.pokeOSM(s.osms[0])
+	assert.NoError(t, err)
+	assert.Equal(t, &ethereum.Hash{1}, hash)
+	assert.True(t, osm.simulate)
+
+	// The previous poke is still pending:
+	tracker[*hash] = true
+	_, err = s.pokeOSM(s.osms[0])
+	assert.Equal(t, errPendingTransaction{AssetPair: "AAABBB", Tx: ethereum.Hash{1}}, err)
+	assert.Equal(t, 1, osm.pokes)
+
+	// The OSM poke does not block the Oracle update for the same pair:
+	assert.NotContains(t, s.lastTx, "AAABBB")
+}
+
+type testCoordinator struct {
+	turn    bool
+	relayed []ethereum.Hash
+}
+
+func (c *testCoordinator) Turn(string, int64) bool { return c.turn }
+
+func (c *testCoordinator) Relayed(_ string, _ int64, tx ethereum.Hash) error {
+	c.relayed = append(c.relayed, tx)
+	return nil
+}
+
+func TestSpectre_relay_Coordinator(t *testing.T) {
+	median := &testMedian{bar: 3, age: time.Unix(50, 0), val: big.NewInt(10)}
+	coord := &testCoordinator{}
+	s := newTestSpectre(median)
+	s.coord = coord
+
+	// Not our turn:
+	tx, err := s.relay("AAABBB")
+	assert.Nil(t, tx)
+	assert.Equal(t, errNotOurTurn{AssetPair: "AAABBB"}, err)
+	assert.Len(t, median.pokes, 0)
+
+	// Our turn, relay intent should be sent:
+	coord.turn = true
+	tx, err = s.relay("AAABBB")
+	require.NoError(t, err)
+	assert.Len(t, median.pokes, 1)
+	assert.Equal(t, []ethereum.Hash{*tx}, coord.relayed)
+}
+
+type testGasPricer struct {
+	price *big.Int
+}
+
+func (g testGasPricer) GasPrice(context.Context) (*big.Int, error) {
+	return g.price, nil
+}
+
+func newTestSpectreWithPairs(cfg Config, pairs ...*Pair) *Spectre {
+	cfg.Datastore = &testDatastore{prices: datastore.NewPriceStore()}
+	cfg.Interval = time.Hour
+	cfg.Pairs = pairs
+	cfg.Logger = null.New()
+	return NewSpectre(cfg)
+}
+
+func TestSpectre_duePairs(t *testing.T) {
+	now := time.Now()
+	s := newTestSpectreWithPairs(
+		Config{},
+		&Pair{AssetPair: "AAABBB", Priority: 1, Interval: time.Minute},
+		&Pair{AssetPair: "CCCDDD", Priority: 5},
+		&Pair{AssetPair: "EEEFFF", Priority: 1},
+	)
+
+	// Pairs are ordered by priority and name:
+	var assetPairs []string
+	for _, pair := range s.duePairs(now) {
+		assetPairs = append(assetPairs, pair.AssetPair)
+	}
+	assert.Equal(t, []string{"CCCDDD", "AAABBB", "EEEFFF"}, assetPairs)
+	assert.Equal(t, time.Minute, s.tick())
+
+	// After relaying, pairs are due after their intervals:
+	s.relayPairs(now)
+	assert.Len(t, s.duePairs(now), 0)
+	require.Len(t, s.duePairs(now.Add(time.Minute)), 1)
+	assert.Equal(t, "AAABBB", s.duePairs(now.Add(time.Minute))[0].AssetPair)
+	assert.Len(t, s.duePairs(now.Add(time.Hour)), 3)
+}
+
+func TestSpectre_relayPairs_GasPriceLimit(t *testing.T) {
+	now := time.Now()
+	s := newTestSpectreWithPairs(
+		Config{
+			GasPricer:     testGasPricer{price: big.NewInt(200)},
+			GasPriceLimit: big.NewInt(100),
+		},
+		&Pair{AssetPair: "AAABBB", Priority: 1},
+		&Pair{AssetPair: "CCCDDD"},
+	)
+
+	// Pairs are not postponed if the gas price is too high, they are only
+	// relayed one by one:
+	s.relayPairs(now)
+	assert.Len(t, s.duePairs(now), 0)
+}
+
+func TestSpectre_relayPairs_GasPriceLimit_ZeroPriority(t *testing.T) {
+	median := &testMedian{bar: 3, age: time.Unix(50, 0), val: big.NewInt(10)}
+	s := newTestSpectre(median)
+	s.gasPricer = testGasPricer{price: big.NewInt(200)}
+	s.gasPriceLimit = big.NewInt(100)
+
+	// The Oracle price is expired, so it must be updated even though
+	// the gas price is above the limit and the pair has no priority:
+	s.relayPairs(time.Now())
+	assert.Len(t, median.pokes, 1)
+}
+
+func TestSpectre_relay_Reference(t *testing.T) {
+	tests := []struct {
+		name      string
+		refPrice  float64
+		refError  string
+		wantPoke  bool
+		wantError bool
+	}{
+		{name: "same-price", refPrice: 30e-18, wantPoke: true},
+		{name: "small-deviation", refPrice: 31e-18, wantPoke: true},
+		{name: "high-deviation", refPrice: 60e-18, wantError: true},
+		{name: "reference-error", refError: "error", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			median := &testMedian{bar: 3, age: time.Unix(50, 0), val: big.NewInt(10)}
+			gof := &goferMocks.Gofer{}
+			pair := gofer.Pair{Base: "AAA", Quote: "BBB"}
+			gof.On("Pairs").Return([]gofer.Pair{pair}, nil)
+			gof.On("Price", pair).Return(&gofer.Price{Pair: pair, Price: tt.refPrice, Error: tt.refError}, nil)
+
+			s := newTestSpectre(median)
+			s.gofer = gof
+			s.pairs["AAABBB"].MaxDeviationFromReference = 5
+
+			rep, err := s.Explain("AAABBB")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPoke, rep.Poke)
+
+			_, err = s.relay("AAABBB")
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Len(t, median.pokes, 0)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, median.pokes, 1)
+			}
+		})
+	}
+}
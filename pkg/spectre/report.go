@@ -0,0 +1,214 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spectre
+
+import (
+	"encoding/hex"
+	"math"
+	"time"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/oracle"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+const (
+	reasonPending  = "previous transaction is still pending"
+	reasonValid    = "oracle price is still valid"
+	reasonNoQuorum = "there is not enough prices to achieve a quorum"
+	reasonExpired  = "oracle price is expired"
+	reasonStale    = "spread between the oracle price and the median price is too high"
+)
+
+// Report describes the decision whether the Oracle for an asset pair should
+// be updated and why.
+type Report struct {
+	AssetPair string `json:"assetPair"`
+
+	// Values read from the Oracle contract:
+	Bar       int64     `json:"bar"`
+	OracleAge time.Time `json:"oracleAge"`
+	OracleVal string    `json:"oracleVal"`
+
+	// Pair configuration:
+	OracleExpiration string  `json:"oracleExpiration"`
+	OracleSpread     float64 `json:"oracleSpread"`
+
+	// Prices is the number of prices in the datastore and ExpiredPrices is
+	// the number of prices which were rejected because they are expired or
+	// older than the Oracle price.
+	Prices        int          `json:"prices"`
+	ExpiredPrices int          `json:"expiredPrices"`
+	Selector      string       `json:"selector"`
+	Selected      []FeedStatus `json:"selected"`
+	Excluded      []FeedStatus `json:"excluded"`
+	Median        string       `json:"median"`
+	// Spread is the spread between the Oracle price and the median price in
+	// percentage points. It is nil if the spread cannot be calculated.
+	Spread *float64 `json:"spread"`
+
+	// Decision:
+	Expired   bool   `json:"expired"`
+	Stale     bool   `json:"stale"`
+	Quorum    bool   `json:"quorum"`
+	PendingTx string `json:"pendingTx,omitempty"`
+	Poke      bool   `json:"poke"`
+	Reason    string `json:"reason"`
+	// Calldata is the calldata of the poke transaction. It is empty if there
+	// is not enough prices to achieve a quorum.
+	Calldata string `json:"calldata,omitempty"`
+	// ReferencePrice is the price from the reference Gofer and
+	// ReferenceDeviation is the deviation of the median price from it in
+	// percentage points. They are set only if the Oracle would be updated
+	// and the cross-check is enabled for the pair.
+	ReferencePrice     string   `json:"referencePrice,omitempty"`
+	ReferenceDeviation *float64 `json:"referenceDeviation,omitempty"`
+
+	spread    float64
+	pendingTx ethereum.Hash
+	// referenceErr is set if the Oracle must not be updated because of
+	// the cross-check with the reference price.
+	referenceErr error
+	prices       []*oracle.Price
+	excluded     []*messages.Price
+}
+
+// FeedStatus describes a single price from a feed.
+type FeedStatus struct {
+	From string    `json:"from"`
+	Val  string    `json:"val"`
+	Age  time.Time `json:"age"`
+}
+
+// Explain returns the report describing whether the Oracle for the given
+// asset pair would be updated and why. It does not send any transactions.
+func (r *Spectre) Explain(assetPair string) (*Report, error) {
+	return r.explain(assetPair)
+}
+
+// explain performs the whole relay decision for given pair without sending
+// a transaction.
+func (r *Spectre) explain(assetPair string) (*Report, error) {
+	pair, ok := r.pairs[assetPair]
+	if !ok {
+		return nil, errUnknownAsset{AssetPair: assetPair}
+	}
+
+	rep := &Report{
+		AssetPair:        assetPair,
+		OracleExpiration: pair.OracleExpiration.String(),
+		OracleSpread:     pair.OracleSpread,
+		Selector:         pair.Selector.Name(),
+	}
+
+	// Do not send another update until the previous one is mined, otherwise
+	// we would send a duplicated transaction with a new nonce:
+	if tx, ok := r.pendingTx(assetPair); ok {
+		rep.pendingTx = tx
+		rep.PendingTx = tx.String()
+		rep.Reason = reasonPending
+		return rep, nil
+	}
+
+	prices := newPrices(r.datastore.Prices().AssetPair(assetPair))
+	if prices == nil || prices.len() == 0 {
+		return nil, errNoPrices{AssetPair: assetPair}
+	}
+	rep.Prices = prices.len()
+
+	oracleQuorum, err := pair.Median.Bar(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	oracleTime, err := pair.Median.Age(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	oraclePrice, err := pair.Median.Val(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep.Bar = oracleQuorum
+	rep.OracleAge = oracleTime
+	rep.OracleVal = oraclePrice.String()
+
+	// Clear expired prices:
+	prices.clearOlderThan(time.Now().Add(-1 * pair.PriceExpiration))
+	prices.clearOlderThan(oracleTime)
+	rep.ExpiredPrices = rep.Prices - prices.len()
+
+	// Use only a minimum prices required to achieve a quorum:
+	rep.excluded = prices.truncate(oracleQuorum, pair.Selector)
+	rep.Selected = r.feedStatuses(prices.messages())
+	rep.Excluded = r.feedStatuses(rep.excluded)
+	median := prices.median()
+	rep.Median = median.String()
+
+	rep.spread = prices.spread(oraclePrice)
+	if !math.IsInf(rep.spread, 0) {
+		rep.Spread = &rep.spread
+	}
+	rep.Expired = oracleTime.Add(pair.OracleExpiration).Before(time.Now())
+	rep.Stale = rep.spread >= pair.OracleSpread
+	rep.Quorum = int64(prices.len()) == oracleQuorum
+	rep.prices = prices.oraclePrices()
+
+	if rep.Quorum {
+		cd, err := pair.Median.PokeCalldata(rep.prices)
+		if err != nil {
+			return nil, err
+		}
+		rep.Calldata = "0x" + hex.EncodeToString(cd)
+	}
+
+	switch {
+	case !rep.Expired && !rep.Stale:
+		rep.Reason = reasonValid
+	case !rep.Quorum:
+		rep.Reason = reasonNoQuorum
+	case rep.Expired:
+		rep.Poke = true
+		rep.Reason = reasonExpired
+	default:
+		rep.Poke = true
+		rep.Reason = reasonStale
+	}
+
+	// Cross-check the median price with the reference price to protect
+	// against compromised feeds:
+	if rep.Poke && pair.MaxDeviationFromReference > 0 {
+		r.checkReference(pair, rep, median)
+	}
+
+	return rep, nil
+}
+
+// feedStatuses converts prices to the list of FeedStatus.
+func (r *Spectre) feedStatuses(msgs []*messages.Price) []FeedStatus {
+	feeds := make([]FeedStatus, 0, len(msgs))
+	for _, msg := range msgs {
+		from := "*invalid signature*"
+		if addr, err := msg.Price.From(r.signer); err == nil {
+			from = addr.String()
+		}
+		feeds = append(feeds, FeedStatus{
+			From: from,
+			Val:  msg.Price.Val.String(),
+			Age:  msg.Price.Age,
+		})
+	}
+	return feeds
+}
@@ -0,0 +1,146 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spectre
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+const (
+	// FreshestSelectorName is the name of the FreshestSelector.
+	FreshestSelectorName = "freshest"
+	// MedianSelectorName is the name of the MedianSelector.
+	MedianSelectorName = "median"
+)
+
+// Selector selects the prices sent to the Oracle contract when there are
+// more prices than required to achieve a quorum.
+//
+// Implementations must be deterministic, so the same set of prices always
+// results in the same transaction. This makes relays reproducible and
+// prevents feeders from influencing the selection by, for example,
+// broadcasting their prices more often.
+type Selector interface {
+	// Name returns the name of the selection strategy, it is used in logs.
+	Name() string
+	// Select returns n prices from the given list and the prices that were
+	// excluded. The given list must not be modified.
+	Select(msgs []*messages.Price, n int) (selected, excluded []*messages.Price)
+}
+
+// SelectorByName returns the selector with the given name. An empty name
+// returns the default selector which is the FreshestSelector.
+func SelectorByName(name string) (Selector, error) {
+	switch name {
+	case "", FreshestSelectorName:
+		return FreshestSelector{}, nil
+	case MedianSelectorName:
+		return MedianSelector{}, nil
+	default:
+		return nil, fmt.Errorf("unknown selection strategy %s", name)
+	}
+}
+
+// FreshestSelector selects the most recent prices.
+type FreshestSelector struct{}
+
+// Name implements the Selector interface.
+func (FreshestSelector) Name() string {
+	return FreshestSelectorName
+}
+
+// Select implements the Selector interface.
+func (FreshestSelector) Select(msgs []*messages.Price, n int) ([]*messages.Price, []*messages.Price) {
+	sorted := sortedPrices(msgs, func(a, b *messages.Price) int {
+		switch {
+		case a.Price.Age.After(b.Price.Age):
+			return -1
+		case a.Price.Age.Before(b.Price.Age):
+			return 1
+		}
+		return 0
+	})
+	return split(sorted, n)
+}
+
+// MedianSelector selects prices closest to the median of all prices, so
+// the median of the selected prices is as close as possible to the median
+// of the full set. Outliers are always excluded first.
+type MedianSelector struct{}
+
+// Name implements the Selector interface.
+func (MedianSelector) Name() string {
+	return MedianSelectorName
+}
+
+// Select implements the Selector interface.
+func (MedianSelector) Select(msgs []*messages.Price, n int) ([]*messages.Price, []*messages.Price) {
+	median := newPrices(append([]*messages.Price{}, msgs...)).median()
+	distance := func(p *messages.Price) *big.Int {
+		return new(big.Int).Abs(new(big.Int).Sub(p.Price.Val, median))
+	}
+	sorted := sortedPrices(msgs, func(a, b *messages.Price) int {
+		if c := distance(a).Cmp(distance(b)); c != 0 {
+			return c
+		}
+		// For prices with the same distance, prefer fresher ones:
+		switch {
+		case a.Price.Age.After(b.Price.Age):
+			return -1
+		case a.Price.Age.Before(b.Price.Age):
+			return 1
+		}
+		return 0
+	})
+	return split(sorted, n)
+}
+
+// sortedPrices returns a sorted copy of the msgs list. Prices for which
+// the cmp function returns 0 are ordered by their value and signature, so
+// the order never depends on the order of the given list.
+func sortedPrices(msgs []*messages.Price, cmp func(a, b *messages.Price) int) []*messages.Price {
+	sorted := append([]*messages.Price{}, msgs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if c := cmp(a, b); c != 0 {
+			return c < 0
+		}
+		if c := a.Price.Val.Cmp(b.Price.Val); c != 0 {
+			return c < 0
+		}
+		if c := bytes.Compare(a.Price.R[:], b.Price.R[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(a.Price.S[:], b.Price.S[:]) < 0
+	})
+	return sorted
+}
+
+// split splits the msgs list into the first n elements and the rest.
+func split(msgs []*messages.Price, n int) ([]*messages.Price, []*messages.Price) {
+	if n >= len(msgs) {
+		return msgs, nil
+	}
+	if n < 0 {
+		n = 0
+	}
+	return msgs[:n], msgs[n:]
+}
@@ -0,0 +1,95 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spectre
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/datastore/testutil"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+func TestFreshestSelector_Select(t *testing.T) {
+	msgs := []*messages.Price{
+		testutil.PriceAAABBB3,
+		testutil.PriceAAABBB1,
+		testutil.PriceAAABBB4,
+		testutil.PriceAAABBB2,
+	}
+
+	selected, excluded := FreshestSelector{}.Select(msgs, 2)
+	assert.Equal(t, []*messages.Price{testutil.PriceAAABBB4, testutil.PriceAAABBB3}, selected)
+	assert.Equal(t, []*messages.Price{testutil.PriceAAABBB2, testutil.PriceAAABBB1}, excluded)
+
+	// The given list must not be modified:
+	assert.Equal(t, testutil.PriceAAABBB3, msgs[0])
+}
+
+func TestMedianSelector_Select(t *testing.T) {
+	msgs := []*messages.Price{
+		testutil.PriceAAABBB1,
+		testutil.PriceAAABBB2,
+		testutil.PriceAAABBB3,
+		testutil.PriceAAABBB4,
+	}
+
+	// The median is 25, so the price 10 is the furthest one:
+	selected, excluded := MedianSelector{}.Select(msgs, 3)
+	assert.Equal(t, []*messages.Price{testutil.PriceAAABBB4, testutil.PriceAAABBB3, testutil.PriceAAABBB2}, selected)
+	assert.Equal(t, []*messages.Price{testutil.PriceAAABBB1}, excluded)
+}
+
+func TestSelectors_Deterministic(t *testing.T) {
+	msgs1 := []*messages.Price{
+		testutil.PriceAAABBB1,
+		testutil.PriceAAABBB2,
+		testutil.PriceAAABBB3,
+		testutil.PriceAAABBB4,
+	}
+	msgs2 := []*messages.Price{
+		testutil.PriceAAABBB4,
+		testutil.PriceAAABBB2,
+		testutil.PriceAAABBB1,
+		testutil.PriceAAABBB3,
+	}
+
+	for _, s := range []Selector{FreshestSelector{}, MedianSelector{}} {
+		for n := 0; n <= len(msgs1); n++ {
+			selected1, excluded1 := s.Select(msgs1, n)
+			selected2, excluded2 := s.Select(msgs2, n)
+			assert.Equal(t, selected1, selected2, s.Name())
+			assert.Equal(t, excluded1, excluded2, s.Name())
+			assert.Len(t, selected1, n, s.Name())
+			assert.Len(t, excluded1, len(msgs1)-n, s.Name())
+		}
+	}
+}
+
+func TestSelectorByName(t *testing.T) {
+	s, err := SelectorByName("")
+	require.NoError(t, err)
+	assert.Equal(t, FreshestSelector{}, s)
+
+	s, err = SelectorByName(MedianSelectorName)
+	require.NoError(t, err)
+	assert.Equal(t, MedianSelector{}, s)
+
+	_, err = SelectorByName("random")
+	assert.Error(t, err)
+}
@@ -21,42 +21,63 @@ import (
 	"net/http"
 	"net/rpc"
 
+	"github.com/makerdao/oracle-suite/pkg/datastore"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
+	"github.com/makerdao/oracle-suite/pkg/rpcsec"
 	"github.com/makerdao/oracle-suite/pkg/transport"
 )
 
 const AgentLoggerTag = "SPIRE_AGENT"
 
 type Agent struct {
-	api      *API
-	rpc      *rpc.Server
-	listener net.Listener
-	network  string
-	address  string
-	log      log.Logger
+	api          *API
+	rpc          *rpc.Server
+	listener     net.Listener
+	network      string
+	address      string
+	httpAddress  string
+	httpListener net.Listener
+	httpServer   *http.Server
+	security     rpcsec.ServerConfig
+	doneCh       chan struct{}
+	log          log.Logger
 }
 
 type AgentConfig struct {
 	Datastore Datastore
+	// Monitor is an optional monitor of feeders, used by the FeedsStatus
+	// method.
+	Monitor   *datastore.Monitor
 	Transport transport.Transport
 	Signer    ethereum.Signer
 	Network   string
 	Address   string
-	Logger    log.Logger
+	// HTTPAddress is the TCP address of the JSON HTTP and WebSocket API.
+	// If empty, the API is disabled.
+	HTTPAddress string
+	// Security configures TLS and the authentication of clients. It is
+	// used by both the RPC and the HTTP API.
+	Security rpcsec.ServerConfig
+	Logger   log.Logger
 }
 
 func NewAgent(cfg AgentConfig) (*Agent, error) {
 	s := &Agent{
 		api: &API{
 			datastore: cfg.Datastore,
+			monitor:   cfg.Monitor,
 			transport: cfg.Transport,
 			signer:    cfg.Signer,
 			log:       cfg.Logger.WithField("tag", AgentLoggerTag),
 		},
-		network: cfg.Network,
-		address: cfg.Address,
-		log:     cfg.Logger.WithField("tag", AgentLoggerTag),
+		network:     cfg.Network,
+		address:     cfg.Address,
+		httpAddress: cfg.HTTPAddress,
+		security:    cfg.Security,
+		doneCh:      make(chan struct{}),
+		log:         cfg.Logger.WithField("tag", AgentLoggerTag),
 	}
 	s.rpc = rpc.NewServer()
 	err := s.rpc.Register(s.api)
@@ -79,21 +100,53 @@ func (s *Agent) Start() error {
 
 	s.log.Debugln("Starting Spire RPC server")
 
-	s.listener, err = net.Listen(s.network, s.address)
+	s.listener, err = rpcsec.Listen(s.network, s.address, s.security.TLS)
 	if err != nil {
 		return err
 	}
 
 	go func() {
-		err := http.Serve(s.listener, nil)
+		err := http.Serve(s.listener, rpcsec.Handler(http.DefaultServeMux, s.security.Token))
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.log.WithError(err).Error("RPC server crashed")
 		}
 	}()
 
+	if s.httpAddress != "" {
+		s.log.Debugln("Starting Spire HTTP server")
+
+		network, address := rpcsec.ParseAddress(s.httpAddress)
+		s.httpListener, err = rpcsec.Listen(network, address, s.security.TLS)
+		if err != nil {
+			return err
+		}
+		s.httpServer = &http.Server{Handler: newHTTPHandler(s.api, s.security.Token, s.doneCh)}
+
+		go func() {
+			err := s.httpServer.Serve(s.httpListener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.log.WithError(err).Error("HTTP server crashed")
+			}
+		}()
+	}
+
 	return nil
 }
 
+// HealthStatus implements the health.Checker interface. It merges statuses
+// of the Datastore and the Transport if they implement the health.Checker
+// interface.
+func (s *Agent) HealthStatus() health.Status {
+	checkers := map[string]health.Checker{}
+	if hc, ok := s.api.datastore.(health.Checker); ok {
+		checkers["datastore"] = hc
+	}
+	if hc, ok := s.api.transport.(health.Checker); ok {
+		checkers["transport"] = hc
+	}
+	return health.Merge(checkers)
+}
+
 func (s *Agent) Stop() {
 	defer s.log.Infof("Stopped")
 	var err error
@@ -107,4 +160,14 @@ func (s *Agent) Stop() {
 	if err != nil {
 		s.log.WithError(err).Error("Unable to close RPC listener")
 	}
+
+	// Closing the doneCh ends WebSocket streams, which are not closed by
+	// the HTTP server:
+	close(s.doneCh)
+	if s.httpServer != nil {
+		err = s.httpServer.Close()
+		if err != nil {
+			s.log.WithError(err).Error("Unable to close HTTP server")
+		}
+	}
 }
@@ -16,7 +16,10 @@
 package spire
 
 import (
+	"errors"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/makerdao/oracle-suite/pkg/datastore"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
@@ -27,8 +30,12 @@ import (
 
 type Nothing = struct{}
 
+var ErrMonitorDisabled = errors.New("feeder monitoring is disabled")
+var ErrPublishDisabled = errors.New("publishing prices is disabled because the RPC token is not set")
+
 type Datastore interface {
 	Prices() *datastore.PriceStore
+	Subscribe() (<-chan datastore.ReceivedPrice, func())
 	Start() error
 	Stop() error
 }
@@ -36,6 +43,7 @@ type Datastore interface {
 type API struct {
 	transport transport.Transport
 	datastore Datastore
+	monitor   *datastore.Monitor
 	signer    ethereum.Signer
 	log       log.Logger
 }
@@ -62,6 +70,34 @@ type PullPriceResp struct {
 	Price *messages.Price
 }
 
+type PullPriceHistoryArg struct {
+	FilterAssetPair string
+	FilterFeeder    string
+	From            time.Time
+	To              time.Time
+}
+
+type PullPriceHistoryResp struct {
+	History []*PriceHistory
+}
+
+type FeedsStatusArg struct {
+	FilterAssetPair string
+	FilterFeeder    string
+}
+
+type FeedsStatusResp struct {
+	Feeds []*datastore.FeederStatus
+}
+
+// PriceHistory is the list of prices sent by a feeder for an asset pair,
+// sorted from the oldest.
+type PriceHistory struct {
+	AssetPair string
+	Feeder    string
+	Prices    []*messages.Price
+}
+
 func (n *API) PublishPrice(arg *PublishPriceArg, _ *Nothing) error {
 	n.log.
 		WithFields(arg.Price.Price.Fields(n.signer)).
@@ -104,3 +140,63 @@ func (n *API) PullPrice(arg *PullPriceArg, resp *PullPriceResp) error {
 
 	return nil
 }
+
+func (n *API) PullPriceHistory(arg *PullPriceHistoryArg, resp *PullPriceHistoryResp) error {
+	n.log.
+		WithField("assetPair", arg.FilterAssetPair).
+		WithField("feeder", arg.FilterFeeder).
+		WithField("from", arg.From).
+		WithField("to", arg.To).
+		Info("Pull price history")
+
+	var history []*PriceHistory
+	for fp, p := range n.datastore.Prices().AllHistory(arg.From, arg.To) {
+		if arg.FilterAssetPair != "" && arg.FilterAssetPair != fp.AssetPair {
+			continue
+		}
+		if arg.FilterFeeder != "" && !strings.EqualFold(arg.FilterFeeder, fp.Feeder.String()) {
+			continue
+		}
+		history = append(history, &PriceHistory{
+			AssetPair: fp.AssetPair,
+			Feeder:    fp.Feeder.String(),
+			Prices:    p,
+		})
+	}
+	sort.Slice(history, func(i, j int) bool {
+		if history[i].AssetPair != history[j].AssetPair {
+			return history[i].AssetPair < history[j].AssetPair
+		}
+		return strings.ToLower(history[i].Feeder) < strings.ToLower(history[j].Feeder)
+	})
+
+	*resp = PullPriceHistoryResp{History: history}
+
+	return nil
+}
+
+func (n *API) FeedsStatus(arg *FeedsStatusArg, resp *FeedsStatusResp) error {
+	n.log.
+		WithField("assetPair", arg.FilterAssetPair).
+		WithField("feeder", arg.FilterFeeder).
+		Info("Feeds status")
+
+	if n.monitor == nil {
+		return ErrMonitorDisabled
+	}
+
+	var feeds []*datastore.FeederStatus
+	for _, s := range n.monitor.Status(time.Now()) {
+		if arg.FilterAssetPair != "" && arg.FilterAssetPair != s.AssetPair {
+			continue
+		}
+		if arg.FilterFeeder != "" && !strings.EqualFold(arg.FilterFeeder, s.Feeder) {
+			continue
+		}
+		feeds = append(feeds, s)
+	}
+
+	*resp = FeedsStatusResp{Feeds: feeds}
+
+	return nil
+}
@@ -58,25 +58,31 @@ func newTestInstances() (*Agent, *Spire) {
 		panic(err)
 	}
 
+	pairs := map[string]*datastore.Pair{
+		"AAABBB": {Feeds: []ethereum.Address{testAddress}},
+		"XXXYYY": {Feeds: []ethereum.Address{testAddress}},
+	}
+	mon := datastore.NewMonitor(datastore.MonitorConfig{Pairs: pairs})
 	dat := datastore.NewDatastore(datastore.Config{
 		Signer:    sig,
 		Transport: tra,
-		Pairs: map[string]*datastore.Pair{
-			"AAABBB": {Feeds: []ethereum.Address{testAddress}},
-			"XXXYYY": {Feeds: []ethereum.Address{testAddress}},
-		},
-		Logger: null.New(),
+		Pairs:     pairs,
+		History:   datastore.HistoryConfig{Size: 10},
+		Monitor:   mon,
+		Logger:    null.New(),
 	})
 
 	sig.On("Recover", mock.Anything, mock.Anything).Return(&testAddress, nil)
 
 	agt, err := NewAgent(AgentConfig{
-		Datastore: dat,
-		Transport: tra,
-		Signer:    sig,
-		Network:   "tcp",
-		Address:   "127.0.0.1:0",
-		Logger:    log,
+		Datastore:   dat,
+		Monitor:     mon,
+		Transport:   tra,
+		Signer:      sig,
+		Network:     "tcp",
+		Address:     "127.0.0.1:0",
+		HTTPAddress: "127.0.0.1:0",
+		Logger:      log,
 	})
 	if err != nil {
 		panic(err)
@@ -169,6 +175,50 @@ func TestClient_PullPrices_ByFeeder(t *testing.T) {
 	assertEqualPrices(t, testPriceAAABBB, prices[0])
 }
 
+func TestClient_PullPriceHistory(t *testing.T) {
+	var err error
+	var history []*PriceHistory
+
+	err = spire.PublishPrice(testPriceAAABBB)
+	assert.NoError(t, err)
+
+	wait(func() bool {
+		history, err = spire.PullPriceHistory("AAABBB", testAddress.String(), time.Time{}, time.Time{})
+		return len(history) > 0
+	}, time.Second)
+
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, "AAABBB", history[0].AssetPair)
+	assert.Equal(t, testAddress.String(), history[0].Feeder)
+	assert.Len(t, history[0].Prices, 1)
+	assertEqualPrices(t, testPriceAAABBB, history[0].Prices[0])
+
+	// Price is outside of the time range:
+	history, err = spire.PullPriceHistory("AAABBB", "", time.Unix(101, 0), time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestClient_FeedsStatus(t *testing.T) {
+	var err error
+	var status []*datastore.FeederStatus
+
+	err = spire.PublishPrice(testPriceAAABBB)
+	assert.NoError(t, err)
+
+	wait(func() bool {
+		status, err = spire.FeedsStatus("AAABBB", testAddress.String())
+		return len(status) > 0
+	}, time.Second)
+
+	assert.NoError(t, err)
+	assert.Len(t, status, 1)
+	assert.Equal(t, testAddress.String(), status[0].Feeder)
+	assert.Equal(t, testPriceAAABBB.Price.Age, status[0].LastPriceAge)
+	assert.NotZero(t, status[0].Messages)
+}
+
 func assertEqualPrices(t *testing.T, expected, given *messages.Price) {
 	je, _ := json.Marshal(expected)
 	jg, _ := json.Marshal(given)
@@ -16,23 +16,19 @@
 package config
 
 import (
-	"bytes"
 	"context"
-	"crypto/ed25519"
-	"crypto/rand"
-	"encoding/hex"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"strings"
-
-	"github.com/libp2p/go-libp2p-core/crypto"
+	"time"
 
 	suite "github.com/makerdao/oracle-suite"
+	"github.com/makerdao/oracle-suite/pkg/config/shared"
 	"github.com/makerdao/oracle-suite/pkg/datastore"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
 	"github.com/makerdao/oracle-suite/pkg/ethereum/geth"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
+	"github.com/makerdao/oracle-suite/pkg/rpcsec"
 	"github.com/makerdao/oracle-suite/pkg/spire"
 	"github.com/makerdao/oracle-suite/pkg/transport"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
@@ -41,21 +37,28 @@ import (
 )
 
 var ErrFailedToLoadConfiguration = errors.New("failed to load Spire's configuration")
-var ErrFailedToReadPassphraseFile = errors.New("failed to read the ethereum password file")
-var ErrFailedToParsePrivKeySeed = errors.New("failed to parse the privKeySeed field")
+var ErrFailedToReadPassphraseFile = shared.ErrFailedToReadPassphraseFile
+var ErrFailedToParsePrivKeySeed = shared.ErrFailedToParsePrivKeySeed
 
 type Config struct {
-	Ethereum Ethereum `json:"ethereum"`
-	P2P      P2P      `json:"p2p"`
-	RPC      RPC      `json:"rpc"`
-	Feeds    []string `json:"feeds"`
-	Pairs    []string `json:"pairs"`
+	Ethereum  Ethereum  `json:"ethereum"`
+	P2P       P2P       `json:"p2p"`
+	RPC       RPC       `json:"rpc"`
+	HTTP      HTTP      `json:"http"`
+	Feeds     []string  `json:"feeds"`
+	Pairs     []string  `json:"pairs"`
+	Health    Health    `json:"health"`
+	Datastore Datastore `json:"datastore"`
 }
 
 type Ethereum struct {
 	From     string `json:"from"`
 	Keystore string `json:"keystore"`
 	Password string `json:"password"`
+	// RemoteSigner is the address of an external signer, like Clef, which
+	// holds the key of the "from" account. If set, the keystore and
+	// password are not used.
+	RemoteSigner string `json:"remoteSigner"`
 }
 
 type P2P struct {
@@ -68,6 +71,46 @@ type P2P struct {
 }
 
 type RPC struct {
+	// Address is the TCP address of the agent, or the path to the Unix
+	// domain socket prefixed with "unix:".
+	Address string `json:"address"`
+	// Token is the bearer token required by the agent and sent by clients.
+	// If empty, the authentication is disabled.
+	Token string     `json:"token"`
+	TLS   rpcsec.TLS `json:"tls"`
+}
+
+// Datastore configures the persistent storage for prices received from
+// feeds, so they are available after a restart.
+type Datastore struct {
+	// Path is the path to the file in which prices are stored. If empty,
+	// prices are kept only in memory.
+	Path string `json:"path"`
+	// Expiration is the time in seconds after which stored prices are
+	// removed. If zero, the datastore default is used.
+	Expiration int `json:"expiration"`
+	// HistorySize is the number of prices kept in memory for each feeder
+	// and asset pair. If zero, the number of prices is not limited by the
+	// size, but the history is enabled only if historyDuration is set.
+	HistorySize int `json:"historySize"`
+	// HistoryDuration is the time in seconds for which prices are kept in
+	// memory, relative to the newest price from the same feeder.
+	HistoryDuration int `json:"historyDuration"`
+	// MonitorWindow is the time window in seconds used to calculate
+	// the message rate of feeders. If zero, the default value is used.
+	MonitorWindow int `json:"monitorWindow"`
+}
+
+// HTTP configures the JSON HTTP and WebSocket API for price consumers.
+type HTTP struct {
+	// Address is the address on which the API is served. If empty, the API
+	// is disabled.
+	Address string `json:"address"`
+}
+
+type Health struct {
+	// Address is the address on which the /healthz and /readyz endpoints are
+	// served. If empty, the endpoints are disabled.
 	Address string `json:"address"`
 }
 
@@ -77,32 +120,49 @@ type Dependencies struct {
 }
 
 func (c *Config) ConfigureAgent(deps Dependencies) (*spire.Agent, error) {
-	// Ethereum account:
-	acc, err := c.configureAccount()
+	// Signer for the Ethereum account:
+	sig, err := c.configureSigner()
 	if err != nil {
 		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
 	}
 
-	// Signer:
-	sig := c.configureSigner(acc)
+	// TLS for the RPC and HTTP API:
+	tls, err := c.RPC.TLS.ServerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
+
+	// Feeder monitor:
+	pairs := c.datastorePairs()
+	mon := datastore.NewMonitor(datastore.MonitorConfig{
+		Window: time.Second * time.Duration(c.Datastore.MonitorWindow),
+		Pairs:  pairs,
+	})
 
 	// Transport:
-	tra, err := c.configureTransport(deps.Context, sig, deps.Logger)
+	tra, err := c.configureTransport(deps.Context, sig, mon, deps.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
 	}
 
 	// Datastore:
-	dat := c.configureDatastore(sig, tra, deps.Logger)
+	dat, err := c.configureDatastore(sig, tra, pairs, mon, deps.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
 
 	// Spire's RPC Agent:
+	network, address := rpcsec.ParseAddress(c.RPC.Address)
 	srv, err := spire.NewAgent(spire.AgentConfig{
-		Datastore: dat,
-		Transport: tra,
-		Signer:    sig,
-		Network:   "tcp",
-		Address:   c.RPC.Address,
-		Logger:    deps.Logger,
+		Datastore:   dat,
+		Monitor:     mon,
+		Transport:   tra,
+		Signer:      sig,
+		Network:     network,
+		Address:     address,
+		HTTPAddress: c.HTTP.Address,
+		Security:    rpcsec.ServerConfig{TLS: tls, Token: c.RPC.Token},
+		Logger:      deps.Logger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
@@ -111,26 +171,46 @@ func (c *Config) ConfigureAgent(deps Dependencies) (*spire.Agent, error) {
 	return srv, nil
 }
 
+// ConfigureHealthServer returns the server for the health endpoints which
+// reports the status of the given agent. It returns nil if the endpoints are
+// disabled.
+func (c *Config) ConfigureHealthServer(agent *spire.Agent, l log.Logger) *health.Server {
+	if c.Health.Address == "" {
+		return nil
+	}
+	return health.NewServer(health.Config{
+		Checkers: map[string]health.Checker{"agent": agent},
+		Network:  "tcp",
+		Address:  c.Health.Address,
+		Logger:   l,
+	})
+}
+
 func (c *Config) ConfigureSpire(deps Dependencies) (*spire.Spire, error) {
-	// Ethereum account:
-	acc, err := c.configureAccount()
+	// Signer for the Ethereum account:
+	sig, err := c.configureSigner()
 	if err != nil {
 		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
 	}
 
-	// Signer:
-	sig := c.configureSigner(acc)
+	// TLS for the RPC:
+	tls, err := c.RPC.TLS.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrFailedToLoadConfiguration, err)
+	}
 
 	// Spire:
+	network, address := rpcsec.ParseAddress(c.RPC.Address)
 	return spire.NewSpire(spire.Config{
-		Signer:  sig,
-		Network: "tcp",
-		Address: c.RPC.Address,
+		Signer:   sig,
+		Network:  network,
+		Address:  address,
+		Security: rpcsec.ClientConfig{TLS: tls, Token: c.RPC.Token},
 	}), nil
 }
 
 func (c *Config) configureAccount() (*geth.Account, error) {
-	passphrase, err := c.readAccountPassphrase(c.Ethereum.Password)
+	passphrase, err := shared.ReadAccountPassphrase(c.Ethereum.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -147,12 +227,25 @@ func (c *Config) configureAccount() (*geth.Account, error) {
 	return a, nil
 }
 
-func (c *Config) configureSigner(a *geth.Account) ethereum.Signer {
-	return geth.NewSigner(a)
+func (c *Config) configureSigner() (ethereum.Signer, error) {
+	if c.Ethereum.RemoteSigner != "" {
+		return geth.DialRemoteSigner(c.Ethereum.RemoteSigner, ethereum.HexToAddress(c.Ethereum.From))
+	}
+	a, err := c.configureAccount()
+	if err != nil {
+		return nil, err
+	}
+	return geth.NewSigner(a), nil
 }
 
-func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l log.Logger) (transport.Transport, error) {
-	peerPrivKey, err := c.generatePrivKey()
+func (c *Config) configureTransport(
+	ctx context.Context,
+	s ethereum.Signer,
+	r p2p.PriceRejectionRecorder,
+	l log.Logger,
+) (*p2p.P2P, error) {
+
+	peerPrivKey, err := shared.GeneratePrivKey(c.P2P.PrivKeySeed)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +260,7 @@ func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l lo
 		BlockedAddrs:     c.P2P.BlockedAddrs,
 		Discovery:        !c.P2P.DisableDiscovery,
 		Signer:           s,
+		PriceRejections:  r,
 		Logger:           l,
 		AppName:          "spire",
 		AppVersion:       suite.Version,
@@ -190,52 +284,48 @@ func (c *Config) configureTransport(ctx context.Context, s ethereum.Signer, l lo
 	return p, nil
 }
 
-func (c *Config) configureDatastore(s ethereum.Signer, t transport.Transport, l log.Logger) *datastore.Datastore {
+func (c *Config) configureDatastore(
+	s ethereum.Signer,
+	t transport.Transport,
+	p map[string]*datastore.Pair,
+	m *datastore.Monitor,
+	l log.Logger,
+) (*datastore.Datastore, error) {
+
 	cfg := datastore.Config{
-		Signer:    s,
-		Transport: t,
-		Pairs:     make(map[string]*datastore.Pair),
-		Logger:    l,
+		Signer:            s,
+		Transport:         t,
+		Pairs:             p,
+		StorageExpiration: time.Second * time.Duration(c.Datastore.Expiration),
+		History: datastore.HistoryConfig{
+			Size:     c.Datastore.HistorySize,
+			Duration: time.Second * time.Duration(c.Datastore.HistoryDuration),
+		},
+		Monitor: m,
+		Logger:  l,
+	}
+	if c.Datastore.Path != "" {
+		storage, err := datastore.NewFileStorage(c.Datastore.Path)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Storage = storage
 	}
 
+	return datastore.NewDatastore(cfg), nil
+}
+
+// datastorePairs returns the asset pairs with the list of feeds from which
+// prices are accepted.
+func (c *Config) datastorePairs() map[string]*datastore.Pair {
 	var feeds []ethereum.Address
 	for _, feed := range c.Feeds {
 		feeds = append(feeds, ethereum.HexToAddress(feed))
 	}
 
+	pairs := make(map[string]*datastore.Pair)
 	for _, pair := range c.Pairs {
-		cfg.Pairs[pair] = &datastore.Pair{Feeds: feeds}
-	}
-
-	return datastore.NewDatastore(cfg)
-}
-
-func (c *Config) generatePrivKey() (crypto.PrivKey, error) {
-	seedReader := rand.Reader
-	if len(c.P2P.PrivKeySeed) != 0 {
-		seed, err := hex.DecodeString(c.P2P.PrivKeySeed)
-		if err != nil {
-			return nil, fmt.Errorf("%v: %v", ErrFailedToParsePrivKeySeed, err)
-		}
-		if len(seed) != ed25519.SeedSize {
-			return nil, fmt.Errorf("%v: seed must be 32 bytes", ErrFailedToParsePrivKeySeed)
-		}
-		seedReader = bytes.NewReader(seed)
-	}
-	privKey, _, err := crypto.GenerateEd25519Key(seedReader)
-	if err != nil {
-		return nil, fmt.Errorf("%v: %v", ErrFailedToParsePrivKeySeed, err)
-	}
-	return privKey, nil
-}
-
-func (c *Config) readAccountPassphrase(path string) (string, error) {
-	if path == "" {
-		return "", nil
-	}
-	passphraseFile, err := ioutil.ReadFile(path)
-	if err != nil {
-		return "", fmt.Errorf("%v: %v", ErrFailedToReadPassphraseFile, err)
+		pairs[pair] = &datastore.Pair{Feeds: feeds}
 	}
-	return strings.TrimSuffix(string(passphraseFile), "\n"), nil
+	return pairs
 }
@@ -0,0 +1,77 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"github.com/makerdao/oracle-suite/pkg/config/shared"
+	"github.com/makerdao/oracle-suite/pkg/config/validation"
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+)
+
+// Validate checks the configuration and returns validation.Errors with all
+// problems found, or nil if the configuration is valid.
+func (c *Config) Validate() error {
+	var errs validation.Errors
+
+	shared.ValidateEthereum(&errs, c.Ethereum.From, c.Ethereum.Password)
+	shared.ValidateP2P(&errs, c.P2P.PrivKeySeed, shared.P2PAddrs{
+		ListenAddrs:      c.P2P.ListenAddrs,
+		BootstrapAddrs:   c.P2P.BootstrapAddrs,
+		DirectPeersAddrs: c.P2P.DirectPeersAddrs,
+		BlockedAddrs:     c.P2P.BlockedAddrs,
+	})
+
+	if c.RPC.Address == "" {
+		errs.Add(validation.Pointer("rpc", "address"), "RPC address is required")
+	}
+	// The same section is used by the agent and by its clients:
+	c.RPC.TLS.ValidateAgent(&errs, "rpc", "tls")
+	c.RPC.TLS.ValidateClient(&errs, "rpc", "tls")
+	for i, feed := range c.Feeds {
+		if !ethereum.IsHexAddress(feed) {
+			errs.Add(validation.Pointer("feeds", i), "invalid feed address %s", feed)
+		}
+	}
+	for i, pair := range c.Pairs {
+		if pair == "" {
+			errs.Add(validation.Pointer("pairs", i), "pair name must not be empty")
+		}
+	}
+
+	c.Datastore.Validate(&errs, "datastore")
+
+	return errs.Err()
+}
+
+// Validate adds problems found in the datastore section to errs. The pointer
+// is the path to the section in the config file.
+func (d Datastore) Validate(errs *validation.Errors, pointer ...interface{}) {
+	field := func(name string) string {
+		return validation.Pointer(append(append([]interface{}{}, pointer...), name)...)
+	}
+	if d.Expiration < 0 {
+		errs.Add(field("expiration"), "expiration must not be negative")
+	}
+	if d.HistorySize < 0 {
+		errs.Add(field("historySize"), "history size must not be negative")
+	}
+	if d.HistoryDuration < 0 {
+		errs.Add(field("historyDuration"), "history duration must not be negative")
+	}
+	if d.MonitorWindow < 0 {
+		errs.Add(field("monitorWindow"), "monitor window must not be negative")
+	}
+}
@@ -0,0 +1,209 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spire
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/makerdao/oracle-suite/pkg/rpcsec"
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+// Values used by the WebSocket stream:
+const wsWriteTimeout = 10 * time.Second
+const wsPingInterval = 30 * time.Second
+const wsPongTimeout = 60 * time.Second
+
+// maxPublishBodySize is the maximum size of the POST /prices request body.
+const maxPublishBodySize = 64 * 1024
+
+type httpError struct {
+	Error string `json:"error"`
+}
+
+// httpHandler serves the JSON HTTP API and the WebSocket stream of prices:
+//
+//	GET  /prices?pair=&feeder=         returns the latest prices
+//	POST /prices                       publishes a price, requires the token,
+//	                                   it is forbidden if the token is not set
+//	GET  /prices/stream?pair=&feeder=  streams newly accepted prices
+type httpHandler struct {
+	api      *API
+	token    string
+	upgrader websocket.Upgrader
+	doneCh   chan struct{}
+}
+
+func newHTTPHandler(api *API, token string, doneCh chan struct{}) *httpHandler {
+	return &httpHandler{
+		api:   api,
+		token: token,
+		upgrader: websocket.Upgrader{
+			// The API is read only for browsers and prices are public, so
+			// requests from all origins are allowed:
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		doneCh: doneCh,
+	}
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/prices":
+		switch r.Method {
+		case http.MethodGet:
+			h.pullPrices(w, r)
+		case http.MethodPost:
+			h.publishPrice(w, r)
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case "/prices/stream":
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		h.streamPrices(w, r)
+	default:
+		writeJSONError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *httpHandler) pullPrices(w http.ResponseWriter, r *http.Request) {
+	resp := &PullPricesResp{}
+	err := h.api.PullPrices(&PullPricesArg{
+		FilterAssetPair: r.URL.Query().Get("pair"),
+		FilterFeeder:    r.URL.Query().Get("feeder"),
+	}, resp)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	prices := resp.Prices
+	if prices == nil {
+		prices = []*messages.Price{}
+	}
+	writeJSON(w, http.StatusOK, prices)
+}
+
+func (h *httpHandler) publishPrice(w http.ResponseWriter, r *http.Request) {
+	// Publishing prices is restricted, because they are broadcast on behalf
+	// of the agent:
+	if h.token == "" {
+		writeJSONError(w, http.StatusForbidden, ErrPublishDisabled.Error())
+		return
+	}
+	if !rpcsec.Authorized(r, h.token) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeJSONError(w, http.StatusUnauthorized, rpcsec.ErrUnauthorized.Error())
+		return
+	}
+	price := &messages.Price{}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPublishBodySize))
+	if err := dec.Decode(price); err != nil || price.Price == nil {
+		writeJSONError(w, http.StatusBadRequest, messages.ErrPriceMalformedMessage.Error())
+		return
+	}
+	if err := h.api.PublishPrice(&PublishPriceArg{Price: price}, &Nothing{}); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *httpHandler) streamPrices(w http.ResponseWriter, r *http.Request) {
+	pair := r.URL.Query().Get("pair")
+	feeder := r.URL.Query().Get("feeder")
+
+	// Subscribe before the upgrade, so no prices are missed after
+	// the connection is established:
+	prices, unsubscribe := h.api.datastore.Subscribe()
+	defer unsubscribe()
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with an error.
+		return
+	}
+	defer conn.Close()
+
+	// Messages from the client are not expected, but they must be read to
+	// handle control messages and to detect a closed connection:
+	closedCh := make(chan struct{})
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+	go func() {
+		defer close(closedCh)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-h.doneCh:
+			_ = conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, ""),
+				time.Now().Add(wsWriteTimeout),
+			)
+			return
+		case <-closedCh:
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		case p, ok := <-prices:
+			if !ok {
+				return
+			}
+			if pair != "" && pair != p.Price.Price.Wat {
+				continue
+			}
+			if feeder != "" && !strings.EqualFold(feeder, p.Feeder.String()) {
+				continue
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(p.Price); err != nil {
+				h.api.log.WithError(err).Warn("Unable to write price to the WebSocket stream")
+				return
+			}
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, httpError{Error: msg})
+}
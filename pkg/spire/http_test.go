@@ -0,0 +1,157 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package spire
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/makerdao/oracle-suite/pkg/transport/messages"
+)
+
+func TestHTTP_PublishAndPullPrices(t *testing.T) {
+	srv := httptest.NewServer(newHTTPHandler(agent.api, "secret", make(chan struct{})))
+	defer srv.Close()
+	addr := srv.URL
+
+	body, err := json.Marshal(testPriceAAABBB)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, addr+"/prices", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusAccepted, res.StatusCode)
+
+	var prices []*messages.Price
+	wait(func() bool {
+		res, err := http.Get(addr + "/prices?pair=AAABBB&feeder=" + testAddress.String())
+		if err != nil {
+			return false
+		}
+		defer res.Body.Close()
+		prices = nil
+		return json.NewDecoder(res.Body).Decode(&prices) == nil && len(prices) > 0
+	}, time.Second)
+
+	require.Len(t, prices, 1)
+	assertEqualPrices(t, testPriceAAABBB, prices[0])
+
+	// Filters which do not match any price:
+	res, err = http.Get(addr + "/prices?pair=CCCDDD")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	prices = nil
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&prices))
+	assert.Empty(t, prices)
+}
+
+func TestHTTP_PublishPrice_Malformed(t *testing.T) {
+	srv := httptest.NewServer(newHTTPHandler(agent.api, "secret", make(chan struct{})))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/prices", bytes.NewReader([]byte(`{"trace":null}`)))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	var e httpError
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&e))
+	assert.Equal(t, messages.ErrPriceMalformedMessage.Error(), e.Error)
+}
+
+func TestHTTP_MethodNotAllowed(t *testing.T) {
+	addr := "http://" + agent.httpListener.Addr().String()
+
+	req, err := http.NewRequest(http.MethodDelete, addr+"/prices", nil)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+}
+
+func TestHTTP_StreamPrices(t *testing.T) {
+	addr := "ws://" + agent.httpListener.Addr().String()
+
+	conn, _, err := websocket.DefaultDialer.Dial(addr+"/prices/stream?pair=AAABBB", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, spire.PublishPrice(testPriceAAABBB))
+
+	price := &messages.Price{}
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	require.NoError(t, conn.ReadJSON(price))
+	assertEqualPrices(t, testPriceAAABBB, price)
+}
+
+func TestHTTP_PublishPrice_Unauthorized(t *testing.T) {
+	srv := httptest.NewServer(newHTTPHandler(agent.api, "secret", make(chan struct{})))
+	defer srv.Close()
+
+	body, err := json.Marshal(testPriceAAABBB)
+	require.NoError(t, err)
+
+	res, err := http.Post(srv.URL+"/prices", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/prices", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusAccepted, res.StatusCode)
+
+	// Reading prices does not require the token:
+	res, err = http.Get(srv.URL + "/prices")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestHTTP_PublishPrice_NoToken(t *testing.T) {
+	addr := "http://" + agent.httpListener.Addr().String()
+
+	body, err := json.Marshal(testPriceAAABBB)
+	require.NoError(t, err)
+
+	// Publishing prices is forbidden if the agent has no token:
+	res, err := http.Post(addr+"/prices", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+	var e httpError
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&e))
+	assert.Equal(t, ErrPublishDisabled.Error(), e.Error)
+}
@@ -17,34 +17,41 @@ package spire
 
 import (
 	"net/rpc"
+	"time"
 
+	"github.com/makerdao/oracle-suite/pkg/datastore"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/rpcsec"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
 )
 
 type Spire struct {
-	rpc     *rpc.Client
-	network string
-	address string
-	signer  ethereum.Signer
+	rpc      *rpc.Client
+	network  string
+	address  string
+	security rpcsec.ClientConfig
+	signer   ethereum.Signer
 }
 
 type Config struct {
 	Signer  ethereum.Signer
 	Network string
 	Address string
+	// Security configures TLS and the token sent to the agent.
+	Security rpcsec.ClientConfig
 }
 
 func NewSpire(cfg Config) *Spire {
 	return &Spire{
-		network: cfg.Network,
-		address: cfg.Address,
-		signer:  cfg.Signer,
+		network:  cfg.Network,
+		address:  cfg.Address,
+		security: cfg.Security,
+		signer:   cfg.Signer,
 	}
 }
 
 func (s *Spire) Start() error {
-	client, err := rpc.DialHTTP(s.network, s.address)
+	client, err := rpcsec.DialHTTP(s.network, s.address, s.security)
 	if err != nil {
 		return err
 	}
@@ -81,3 +88,30 @@ func (s *Spire) PullPrice(assetPair string, feeder string) (*messages.Price, err
 	}
 	return resp.Price, nil
 }
+
+// PullPriceHistory returns the history of prices with the age between from
+// and to. Zero from or to times mean that the range is not limited on that
+// side. The history is available only if it is enabled in the agent.
+func (s *Spire) PullPriceHistory(assetPair string, feeder string, from, to time.Time) ([]*PriceHistory, error) {
+	resp := &PullPriceHistoryResp{}
+	err := s.rpc.Call("API.PullPriceHistory", PullPriceHistoryArg{
+		FilterAssetPair: assetPair,
+		FilterFeeder:    feeder,
+		From:            from,
+		To:              to,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.History, nil
+}
+
+// FeedsStatus returns the performance and liveness statuses of feeders.
+func (s *Spire) FeedsStatus(assetPair string, feeder string) ([]*datastore.FeederStatus, error) {
+	resp := &FeedsStatusResp{}
+	err := s.rpc.Call("API.FeedsStatus", FeedsStatusArg{FilterAssetPair: assetPair, FilterFeeder: feeder}, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Feeds, nil
+}
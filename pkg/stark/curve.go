@@ -0,0 +1,148 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package stark
+
+import (
+	"math/big"
+)
+
+// Parameters of the STARK-friendly elliptic curve used by StarkEx:
+//
+//	y^2 = x^3 + alpha*x + beta (mod p)
+//
+// See: https://docs.starkware.co/starkex/crypto/stark-curve.html
+var (
+	// p is the field prime, 2^251 + 17*2^192 + 1.
+	p = hexInt("0800000000000011000000000000000000000000000000000000000000000001")
+	// alpha is the curve's alpha coefficient.
+	alpha = big.NewInt(1)
+	// beta is the curve's beta coefficient.
+	beta = hexInt("06f21413efbe40de150e596d72f7a8c5609ad26c15c915c1f4cdfcb99cee9e89")
+	// n is the order of the generator point.
+	n = hexInt("0800000000000010ffffffffffffffffb781126dcae7b2321e66a241adc64d2f")
+	// g is the generator point.
+	g = point{
+		x: hexInt("01ef15c18599971b7beced415a40f0c7deacfd9b0d1819e03d723d8bc943cfca"),
+		y: hexInt("005668060aa49730b7be4801df46ec62de53ecd11abe43a32873000c36e8dc1f"),
+	}
+	// maxValue is the upper bound (exclusive) for hashes, r and w values
+	// accepted by the signature scheme, 2^251.
+	maxValue = new(big.Int).Lsh(big.NewInt(1), 251)
+)
+
+// point is a point on the curve in affine coordinates. The point at infinity
+// is represented by nil coordinates.
+type point struct {
+	x, y *big.Int
+}
+
+func (a point) isInfinity() bool {
+	return a.x == nil
+}
+
+// isOnCurve checks if the point satisfies the curve equation.
+func (a point) isOnCurve() bool {
+	if a.isInfinity() {
+		return true
+	}
+	l := new(big.Int).Mul(a.y, a.y)
+	l.Mod(l, p)
+	return l.Cmp(rhs(a.x)) == 0
+}
+
+func (a point) neg() point {
+	if a.isInfinity() {
+		return a
+	}
+	return point{x: a.x, y: new(big.Int).Sub(p, a.y)}
+}
+
+func (a point) add(b point) point {
+	if a.isInfinity() {
+		return b
+	}
+	if b.isInfinity() {
+		return a
+	}
+	var m *big.Int
+	if a.x.Cmp(b.x) == 0 {
+		if new(big.Int).Add(a.y, b.y).Cmp(p) == 0 || a.y.Sign() == 0 && b.y.Sign() == 0 {
+			return point{}
+		}
+		// Doubling, m = (3x^2 + alpha) / 2y:
+		num := new(big.Int).Mul(a.x, a.x)
+		num.Mul(num, big.NewInt(3))
+		num.Add(num, alpha)
+		den := new(big.Int).Lsh(a.y, 1)
+		m = num.Mul(num, den.ModInverse(den.Mod(den, p), p))
+	} else {
+		// Addition, m = (y2 - y1) / (x2 - x1):
+		num := new(big.Int).Sub(b.y, a.y)
+		den := new(big.Int).Sub(b.x, a.x)
+		m = num.Mul(num, den.ModInverse(den.Mod(den, p), p))
+	}
+	m.Mod(m, p)
+	x := new(big.Int).Mul(m, m)
+	x.Sub(x, a.x)
+	x.Sub(x, b.x)
+	x.Mod(x, p)
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, m)
+	y.Sub(y, a.y)
+	y.Mod(y, p)
+	return point{x: x, y: y}
+}
+
+// mul multiplies the point by the scalar k using the double-and-add method.
+func (a point) mul(k *big.Int) point {
+	r := point{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r = r.add(r)
+		if k.Bit(i) == 1 {
+			r = r.add(a)
+		}
+	}
+	return r
+}
+
+// pointFromX returns one of two points with the given x coordinate. It
+// returns false if there is no such point on the curve.
+func pointFromX(x *big.Int) (point, bool) {
+	if x.Sign() < 0 || x.Cmp(p) >= 0 {
+		return point{}, false
+	}
+	y := new(big.Int).ModSqrt(rhs(x), p)
+	if y == nil {
+		return point{}, false
+	}
+	return point{x: new(big.Int).Set(x), y: y}, true
+}
+
+// rhs calculates the right-hand side of the curve equation.
+func rhs(x *big.Int) *big.Int {
+	r := new(big.Int).Exp(x, big.NewInt(3), p)
+	r.Add(r, new(big.Int).Mul(alpha, x))
+	r.Add(r, beta)
+	return r.Mod(r, p)
+}
+
+func hexInt(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("stark: invalid hex number " + s)
+	}
+	return i
+}
@@ -0,0 +1,74 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package stark
+
+import (
+	"errors"
+	"math/big"
+)
+
+var ErrInvalidPedersenInput = errors.New("pedersen hash input must be in the range [0, p)")
+
+// Constant points used by the Pedersen hash function.
+//
+// See: https://docs.starkware.co/starkex/crypto/pedersen-hash-function.html
+var (
+	pedersenShift = point{
+		x: hexInt("049ee3eba8c1600700ee1b87eb599f16716b0b1022947733551fde4050ca6804"),
+		y: hexInt("03ca0cfe4b3bc6ddf346d49d06ea0ed34e621062c0e056c1d0405d266e10268a"),
+	}
+	pedersenPoints = [4]point{
+		{
+			x: hexInt("0234287dcbaffe7f969c748655fca9e58fa8120b6d56eb0c1080d17957ebe47b"),
+			y: hexInt("03b056f100f96fb21e889527d41f4e39940135dd7a6c94cc6ed0268ee89e5615"),
+		},
+		{
+			x: hexInt("04fa56f376c83db33f9dab2656558f3399099ec1de5e3018b7a6932dba8aa378"),
+			y: hexInt("03fa0984c931c9e38113e0c0e47e4401562761f92a7a23b45168f4e80ff5b54d"),
+		},
+		{
+			x: hexInt("04ba4cc166be8dec764910f75b45f74b40c690c74709e90f3aa372f0bd2d6997"),
+			y: hexInt("0040301cf5c1751f4b971e46c4ede85fcac5c59a5ce5ae7c48151f27b24b219c"),
+		},
+		{
+			x: hexInt("054302dcb0e6cc1c6e44cca8f61a63bb2ca65048d53fb325d36ff12c49a58202"),
+			y: hexInt("01b77b3e37d13504b348046268d8ae25ce98ad783c25561a879dcc77e99c2426"),
+		},
+	}
+	// lowPartMask is used to split inputs into the low 248 bits and the high
+	// 4 bits.
+	lowPartMask = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 248), big.NewInt(1))
+)
+
+// PedersenHash calculates the Pedersen hash of two field elements:
+//
+//	H(a, b) = [shift + a_low*P0 + a_high*P1 + b_low*P2 + b_high*P3]_x
+//
+// where a_low and b_low are the 248 low bits and a_high and b_high are
+// the 4 high bits of the inputs.
+func PedersenHash(a, b *big.Int) (*big.Int, error) {
+	r := pedersenShift
+	for i, v := range []*big.Int{a, b} {
+		if v.Sign() < 0 || v.Cmp(p) >= 0 {
+			return nil, ErrInvalidPedersenInput
+		}
+		low := new(big.Int).And(v, lowPartMask)
+		high := new(big.Int).Rsh(v, 248)
+		r = r.add(pedersenPoints[2*i].mul(low))
+		r = r.add(pedersenPoints[2*i+1].mul(high))
+	}
+	return r.x, nil
+}
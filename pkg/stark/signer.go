@@ -0,0 +1,191 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package stark
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+var ErrInvalidPrivateKey = errors.New("stark private key must be in the range [1, n)")
+var ErrInvalidHash = errors.New("hash to sign must be in the range [0, 2^251)")
+
+// Signer signs hashes using the ECDSA scheme over the STARK curve.
+type Signer interface {
+	// PublicKey returns the x coordinate of the signer's public key.
+	PublicKey() *big.Int
+	// Sign signs the given hash and returns the r and s values of
+	// the signature.
+	Sign(hash *big.Int) (r, s *big.Int, err error)
+}
+
+// KeySigner is a Signer which uses a private key stored in memory.
+type KeySigner struct {
+	privKey *big.Int
+	pubKey  *big.Int
+}
+
+// NewSigner returns a new KeySigner for the given private key.
+func NewSigner(privKey *big.Int) (*KeySigner, error) {
+	if privKey.Sign() <= 0 || privKey.Cmp(n) >= 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+	return &KeySigner{
+		privKey: new(big.Int).Set(privKey),
+		pubKey:  g.mul(privKey).x,
+	}, nil
+}
+
+// PublicKey implements the Signer interface.
+func (s *KeySigner) PublicKey() *big.Int {
+	return new(big.Int).Set(s.pubKey)
+}
+
+// Sign implements the Signer interface. The nonce is generated
+// deterministically from the private key and the hash as described in
+// RFC 6979, so the same hash always produces the same signature.
+func (s *KeySigner) Sign(hash *big.Int) (*big.Int, *big.Int, error) {
+	if hash.Sign() < 0 || hash.Cmp(maxValue) >= 0 {
+		return nil, nil, ErrInvalidHash
+	}
+	nonce := newNonceGenerator(s.privKey, hash)
+	for {
+		k := nonce()
+
+		// The r and w = s^-1 values must be in the range [1, 2^251):
+		r := g.mul(k).x
+		if r.Sign() == 0 || r.Cmp(maxValue) >= 0 {
+			continue
+		}
+		sum := new(big.Int).Mul(r, s.privKey)
+		sum.Add(sum, hash)
+		sum.Mod(sum, n)
+		if sum.Sign() == 0 {
+			continue
+		}
+		w := new(big.Int).ModInverse(sum, n)
+		w.Mul(w, k)
+		w.Mod(w, n)
+		if w.Sign() == 0 || w.Cmp(maxValue) >= 0 {
+			continue
+		}
+		return r, new(big.Int).ModInverse(w, n), nil
+	}
+}
+
+// Verify verifies the signature of the hash. The public key is the x
+// coordinate of the signer's public key.
+func Verify(hash, r, s, pubKey *big.Int) bool {
+	if hash.Sign() < 0 || hash.Cmp(maxValue) >= 0 {
+		return false
+	}
+	if r.Sign() <= 0 || r.Cmp(maxValue) >= 0 {
+		return false
+	}
+	if s.Sign() <= 0 || s.Cmp(n) >= 0 {
+		return false
+	}
+	w := new(big.Int).ModInverse(s, n)
+	if w == nil || w.Cmp(maxValue) >= 0 {
+		return false
+	}
+	q, ok := pointFromX(pubKey)
+	if !ok {
+		return false
+	}
+	// Only the x coordinate of the public key is known, so both possible
+	// points have to be checked:
+	zg := g.mul(hash)
+	rq := q.mul(r)
+	for _, p := range []point{zg.add(rq), zg.add(rq.neg())} {
+		x := p.mul(w).x
+		if x != nil && x.Cmp(r) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// GrindKey derives a private key from the given seed in the same way as
+// the StarkWare's key derivation does. The seed is hashed together with an
+// index until the result is below the largest multiple of n that fits in
+// 256 bits, so the returned key is uniformly distributed.
+func GrindKey(seed []byte) *big.Int {
+	limit := new(big.Int).Lsh(big.NewInt(1), 256)
+	limit.Sub(limit, new(big.Int).Mod(limit, n))
+	for i := 0; ; i++ {
+		h := sha256.Sum256(append(append([]byte{}, seed...), byte(i)))
+		k := new(big.Int).SetBytes(h[:])
+		if k.Cmp(limit) < 0 {
+			return k.Mod(k, n)
+		}
+	}
+}
+
+// newNonceGenerator returns a function which generates consecutive nonces
+// for the given private key and hash, as described in RFC 6979 section 3.2
+// with HMAC-SHA256.
+func newNonceGenerator(privKey, hash *big.Int) func() *big.Int {
+	const rlen = 32
+	qlen := n.BitLen()
+
+	bits2int := func(b []byte) *big.Int {
+		i := new(big.Int).SetBytes(b)
+		if excess := len(b)*8 - qlen; excess > 0 {
+			i.Rsh(i, uint(excess))
+		}
+		return i
+	}
+	mac := func(key []byte, data ...[]byte) []byte {
+		h := hmac.New(sha256.New, key)
+		for _, d := range data {
+			h.Write(d)
+		}
+		return h.Sum(nil)
+	}
+
+	x := privKey.FillBytes(make([]byte, rlen))
+	h := bits2int(hash.FillBytes(make([]byte, rlen)))
+	h1 := h.Mod(h, n).FillBytes(make([]byte, rlen))
+
+	v := make([]byte, sha256.Size)
+	k := make([]byte, sha256.Size)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k = mac(k, v, []byte{0x00}, x, h1)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, h1)
+	v = mac(k, v)
+
+	first := true
+	return func() *big.Int {
+		for {
+			if !first {
+				k = mac(k, v, []byte{0x00})
+				v = mac(k, v)
+			}
+			first = false
+			v = mac(k, v)
+			nonce := bits2int(v)
+			if nonce.Sign() > 0 && nonce.Cmp(n) < 0 {
+				return nonce
+			}
+		}
+	}
+}
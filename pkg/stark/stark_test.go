@@ -0,0 +1,95 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package stark
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurve_ConstantPoints(t *testing.T) {
+	assert.True(t, g.isOnCurve())
+	assert.True(t, pedersenShift.isOnCurve())
+	for _, pt := range pedersenPoints {
+		assert.True(t, pt.isOnCurve())
+	}
+	assert.True(t, g.mul(n).isInfinity())
+}
+
+func TestPedersenHash(t *testing.T) {
+	// Test vector from the StarkWare's crypto library:
+	h, err := PedersenHash(
+		hexInt("03d937c035c878245caf64531a5756109c53068da139362728feb561405371cb"),
+		hexInt("0208a0a10250e382e1e4bbe2880906c2791bf6275695e02fbbc6aeff9cd8b31a"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, hexInt("030e480bed5fe53fa909cc0f8c4d99b8f9f2c016be4c41e13a4848797979c662"), h)
+
+	_, err = PedersenHash(p, big.NewInt(0))
+	assert.Equal(t, ErrInvalidPedersenInput, err)
+}
+
+func TestKeySigner(t *testing.T) {
+	s, err := NewSigner(GrindKey([]byte("seed")))
+	require.NoError(t, err)
+	hash := hexInt("0397e76d1667c4454bfb83514e120583af836f8e32a516765497823eabe16a3f")
+
+	r1, s1, err := s.Sign(hash)
+	require.NoError(t, err)
+	assert.True(t, Verify(hash, r1, s1, s.PublicKey()))
+
+	// Signatures are deterministic:
+	r2, s2, err := s.Sign(hash)
+	require.NoError(t, err)
+	assert.Equal(t, r1, r2)
+	assert.Equal(t, s1, s2)
+
+	// Different hash:
+	assert.False(t, Verify(new(big.Int).Add(hash, big.NewInt(1)), r1, s1, s.PublicKey()))
+
+	// Different key:
+	o, err := NewSigner(GrindKey([]byte("other")))
+	require.NoError(t, err)
+	assert.False(t, Verify(hash, r1, s1, o.PublicKey()))
+
+	// Hash out of range:
+	_, _, err = s.Sign(maxValue)
+	assert.Equal(t, ErrInvalidHash, err)
+}
+
+func TestKeySigner_PublicKey(t *testing.T) {
+	// Test vector from the StarkWare's crypto library:
+	s, err := NewSigner(hexInt("03c1e9550e66958296d11b60f8e8e7a7ad990d07fa65d5f7652c4a6c87d4e3cc"))
+	require.NoError(t, err)
+	assert.Equal(t, hexInt("077a3b314db07c45076d11f62b6f9e748a39790441823307743cf00d6597ea43"), s.PublicKey())
+}
+
+func TestNewSigner_InvalidKey(t *testing.T) {
+	_, err := NewSigner(big.NewInt(0))
+	assert.Equal(t, ErrInvalidPrivateKey, err)
+	_, err = NewSigner(n)
+	assert.Equal(t, ErrInvalidPrivateKey, err)
+}
+
+func TestGrindKey(t *testing.T) {
+	k := GrindKey([]byte("seed"))
+	assert.Equal(t, k, GrindKey([]byte("seed")))
+	assert.True(t, k.Sign() > 0 && k.Cmp(n) < 0)
+	assert.NotEqual(t, k, GrindKey([]byte("other")))
+}
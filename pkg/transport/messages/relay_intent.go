@@ -0,0 +1,120 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package messages
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/makerdao/oracle-suite/pkg/ethereum"
+)
+
+var RelayIntentMessageName = "relay_intent/v0"
+
+var ErrRelayIntentMalformedMessage = errors.New("malformed relay intent message")
+
+// RelayIntent is broadcast by a relayer after sending an Oracle update, so
+// other relayers can wait until the transaction is mined instead of sending
+// a duplicated one.
+type RelayIntent struct {
+	// AssetPair is the name of the asset pair, e.g. ETHUSD.
+	AssetPair string
+	// Round identifies the Oracle update. It is the Oracle age, as a Unix
+	// timestamp, before the update.
+	Round int64
+	// Tx is the hash of the transaction sent by the relayer.
+	Tx ethereum.Hash
+	// Signature is the relayer's signature of the message.
+	Signature ethereum.Signature
+}
+
+type jsonRelayIntent struct {
+	AssetPair string        `json:"assetPair"`
+	Round     int64         `json:"round"`
+	Tx        ethereum.Hash `json:"tx"`
+	Signature string        `json:"signature"`
+}
+
+// Sign signs the message using the given signer.
+func (r *RelayIntent) Sign(signer ethereum.Signer) error {
+	signature, err := signer.Signature(r.hash())
+	if err != nil {
+		return err
+	}
+	r.Signature = signature
+	return nil
+}
+
+// From returns the address of the relayer who signed the message.
+func (r *RelayIntent) From(signer ethereum.Signer) (*ethereum.Address, error) {
+	return signer.Recover(r.Signature, r.hash())
+}
+
+func (r *RelayIntent) Marshall() ([]byte, error) {
+	return json.Marshal(jsonRelayIntent{
+		AssetPair: r.AssetPair,
+		Round:     r.Round,
+		Tx:        r.Tx,
+		Signature: hex.EncodeToString(r.Signature.Bytes()),
+	})
+}
+
+func (r *RelayIntent) Unmarshall(b []byte) error {
+	var j jsonRelayIntent
+	err := json.Unmarshal(b, &j)
+	if err != nil {
+		return err
+	}
+	if j.AssetPair == "" {
+		return ErrRelayIntentMalformedMessage
+	}
+	signature, err := hex.DecodeString(j.Signature)
+	if err != nil || len(signature) != ethereum.SignatureLength {
+		return ErrRelayIntentMalformedMessage
+	}
+	r.AssetPair = j.AssetPair
+	r.Round = j.Round
+	r.Tx = j.Tx
+	r.Signature = ethereum.SignatureFromBytes(signature)
+	return nil
+}
+
+func (r *RelayIntent) MarshalBinary() ([]byte, error) {
+	return r.Marshall()
+}
+
+func (r *RelayIntent) UnmarshalBinary(data []byte) error {
+	return r.Unmarshall(data)
+}
+
+// hash returns the hash of the signed data.
+func (r *RelayIntent) hash() []byte {
+	// Asset name:
+	wat := make([]byte, 32)
+	copy(wat, r.AssetPair)
+	// Round:
+	round := make([]byte, 32)
+	binary.BigEndian.PutUint64(round[24:], uint64(r.Round))
+
+	hash := make([]byte, 96)
+	copy(hash[0:32], wat)
+	copy(hash[32:64], round)
+	copy(hash[64:96], r.Tx.Bytes())
+
+	return ethereum.SHA3Hash(hash)
+}
@@ -29,10 +29,36 @@ import (
 	"github.com/makerdao/oracle-suite/pkg/transport/p2p/crypto/ethkey"
 )
 
+// Reasons for which a price message may be rejected by the validator.
+const (
+	RejectInvalidSignature = "invalidSignature"
+	RejectAuthorMismatch   = "authorMismatch"
+	RejectUnknownFeeder    = "unknownFeeder"
+	RejectStale            = "stale"
+)
+
+// PriceRejectionRecorder records price messages rejected by the validator.
+type PriceRejectionRecorder interface {
+	// RecordRejectedPrice records the price rejected for the given reason.
+	// The feeder address is nil if the signature is invalid.
+	RecordRejectedPrice(from *ethereum.Address, msg *messages.Price, reason string)
+}
+
 // oracle adds a validator for price messages. The validator checks if the
 // author of the message is allowed to send price messages, the price
 // message is valid, and if the price is not older than 5 min.
-func oracle(feeders []ethereum.Address, signer ethereum.Signer, logger log.Logger) p2p.Options {
+func oracle(
+	feeders []ethereum.Address,
+	signer ethereum.Signer,
+	recorder PriceRejectionRecorder,
+	logger log.Logger,
+) p2p.Options {
+
+	record := func(from *ethereum.Address, msg *messages.Price, reason string) {
+		if recorder != nil {
+			recorder.RecordRejectedPrice(from, msg, reason)
+		}
+	}
 	return func(n *p2p.Node) error {
 		n.AddValidator(func(ctx context.Context, topic string, id peer.ID, psMsg *pubsub.Message) pubsub.ValidationResult {
 			priceMsg, ok := psMsg.ValidatorData.(*messages.Price)
@@ -46,6 +72,7 @@ func oracle(feeders []ethereum.Address, signer ethereum.Signer, logger log.Logge
 					WithError(err).
 					WithField("peerID", psMsg.GetFrom().String()).
 					Info("The price message was rejected, invalid signature")
+				record(nil, priceMsg, RejectInvalidSignature)
 				return pubsub.ValidationReject
 			}
 			// The libp2p message should be created by the same person who signs the price message:
@@ -53,6 +80,7 @@ func oracle(feeders []ethereum.Address, signer ethereum.Signer, logger log.Logge
 				logger.
 					WithField("peerID", psMsg.GetFrom().String()).
 					Info("The price message was rejected, the message author and price signature don't match")
+				record(priceFrom, priceMsg, RejectAuthorMismatch)
 				return pubsub.ValidationReject
 			}
 			// Check if an author is allowed to send price messages:
@@ -68,6 +96,7 @@ func oracle(feeders []ethereum.Address, signer ethereum.Signer, logger log.Logge
 					WithField("peerID", psMsg.GetFrom().String()).
 					WithField("feed", priceFrom.String()).
 					Info("The price message was ignored, the feeder is not allowed to send price messages")
+				record(priceFrom, priceMsg, RejectUnknownFeeder)
 				return pubsub.ValidationIgnore
 			}
 			// Check when message was created, ignore if older than 5 min, reject if older than 10 min:
@@ -76,6 +105,7 @@ func oracle(feeders []ethereum.Address, signer ethereum.Signer, logger log.Logge
 					WithField("peerID", psMsg.GetFrom().String()).
 					WithField("feed", priceFrom.String()).
 					Info("The price message was rejected, the message is older than 5 min")
+				record(priceFrom, priceMsg, RejectStale)
 				if time.Since(priceMsg.Price.Age) > 10*time.Minute {
 					return pubsub.ValidationReject
 				}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/makerdao/oracle-suite/internal/p2p"
 	"github.com/makerdao/oracle-suite/pkg/ethereum"
+	"github.com/makerdao/oracle-suite/pkg/health"
 	"github.com/makerdao/oracle-suite/pkg/log"
 	"github.com/makerdao/oracle-suite/pkg/transport"
 	"github.com/makerdao/oracle-suite/pkg/transport/messages"
@@ -92,6 +93,9 @@ type Config struct {
 	Discovery bool
 	// Signer used to verify price messages.
 	Signer ethereum.Signer
+	// PriceRejections is an optional recorder of price messages rejected
+	// by the validator.
+	PriceRejections PriceRejectionRecorder
 
 	// Application info:
 	AppName    string
@@ -146,7 +150,7 @@ func New(cfg Config) (*P2P, error) {
 			}
 			return nil
 		}),
-		oracle(cfg.FeedersAddrs, cfg.Signer, logger),
+		oracle(cfg.FeedersAddrs, cfg.Signer, cfg.PriceRejections, logger),
 		p2p.Monitor(),
 	}
 	if cfg.PeerPrivKey != nil {
@@ -214,6 +218,16 @@ func (p *P2P) Close() error {
 	return p.node.Stop()
 }
 
+// HealthStatus implements the health.Checker interface. The transport is
+// ready if it is connected to at least one peer.
+func (p *P2P) HealthStatus() health.Status {
+	peers := len(p.node.Host().Network().Peers())
+	return health.Status{
+		Ready:   peers > 0,
+		Details: map[string]interface{}{"peers": peers},
+	}
+}
+
 // strsToMaddrs converts multiaddresses given as strings to a
 // list of multiaddr.Multiaddr.
 func strsToMaddrs(addrs []string) ([]core.Multiaddr, error) {